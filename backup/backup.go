@@ -0,0 +1,231 @@
+// Package backup snapshots user's library (playlists, liked and disliked
+// tracks, liked albums and artists) into a versioned JSON archive and
+// restores it to the same or another account.
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// Version is a version of archive format written by this package.
+const Version = 1
+
+// ErrUnsupportedVersion is returned when reading archive of unknown version.
+var ErrUnsupportedVersion = errors.New("backup: unsupported archive version")
+
+type (
+	// Archive is a snapshot of user's library.
+	Archive struct {
+		Version        int        `json:"version"`
+		CreatedAt      time.Time  `json:"createdAt"`
+		UID            int        `json:"uid"`
+		Login          string     `json:"login,omitempty"`
+		Playlists      []Playlist `json:"playlists"`
+		LikedTracks    []Track    `json:"likedTracks"`
+		DislikedTracks []Track    `json:"dislikedTracks"`
+		LikedAlbums    []Album    `json:"likedAlbums"`
+		LikedArtists   []Artist   `json:"likedArtists"`
+	}
+
+	// Playlist is a user's playlist with tracks.
+	Playlist struct {
+		Kind       int     `json:"kind"`
+		Title      string  `json:"title"`
+		Visibility string  `json:"visibility,omitempty"`
+		Tracks     []Track `json:"tracks"`
+	}
+
+	// Track is a track reference. Title and artists are saved for humans
+	// reading the archive and are not used by restore.
+	Track struct {
+		ID      string `json:"id"`
+		AlbumID int    `json:"albumId,omitempty"`
+		Title   string `json:"title,omitempty"`
+		Artists string `json:"artists,omitempty"`
+	}
+
+	// Album is a liked album.
+	Album struct {
+		ID      int    `json:"id"`
+		Title   string `json:"title,omitempty"`
+		Artists string `json:"artists,omitempty"`
+	}
+
+	// Artist is a liked artist.
+	Artist struct {
+		ID   int    `json:"id"`
+		Name string `json:"name,omitempty"`
+	}
+)
+
+// String returns track id in "trackID:albumID" form.
+func (t Track) String() string {
+	if t.AlbumID == 0 {
+		return t.ID
+	}
+
+	return t.ID + ":" + strconv.Itoa(t.AlbumID)
+}
+
+// Snapshot fetches library of the user the client is authorized as.
+func Snapshot(ctx context.Context, client *yamusic.Client) (*Archive, error) {
+	archive := &Archive{
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	status, resp, err := client.Account().GetStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("backup: get account status: %w", err)
+	}
+	if err := yamusic.CheckResponse(resp, status.Error); err != nil {
+		return nil, fmt.Errorf("backup: get account status: %w", err)
+	}
+
+	archive.UID = status.Result.Account.UID
+	archive.Login = status.Result.Account.Login
+
+	if archive.Playlists, err = snapshotPlaylists(ctx, client); err != nil {
+		return nil, err
+	}
+
+	liked, resp, err := client.Likes().Tracks(ctx)
+	if err == nil {
+		err = yamusic.CheckResponse(resp, liked.Error)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("backup: get liked tracks: %w", err)
+	}
+	archive.LikedTracks = likedTracks(liked)
+
+	disliked, resp, err := client.Likes().DislikedTracks(ctx)
+	if err == nil {
+		err = yamusic.CheckResponse(resp, disliked.Error)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("backup: get disliked tracks: %w", err)
+	}
+	archive.DislikedTracks = likedTracks(disliked)
+
+	albums, resp, err := client.Likes().Albums(ctx)
+	if err == nil {
+		err = yamusic.CheckResponse(resp, albums.Error)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("backup: get liked albums: %w", err)
+	}
+	archive.LikedAlbums = make([]Album, 0, len(albums.Result))
+	for _, item := range albums.Result {
+		archive.LikedAlbums = append(archive.LikedAlbums, Album{
+			ID:      item.ID,
+			Title:   item.Album.Title,
+			Artists: item.Album.Artists.Names(),
+		})
+	}
+
+	artists, resp, err := client.Likes().Artists(ctx)
+	if err == nil {
+		err = yamusic.CheckResponse(resp, artists.Error)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("backup: get liked artists: %w", err)
+	}
+	archive.LikedArtists = make([]Artist, 0, len(artists.Result))
+	for _, item := range artists.Result {
+		archive.LikedArtists = append(archive.LikedArtists, Artist{
+			ID:   item.ID,
+			Name: item.Artist.Name,
+		})
+	}
+
+	return archive, nil
+}
+
+// snapshotPlaylists fetches all playlists of the user with tracks.
+func snapshotPlaylists(ctx context.Context, client *yamusic.Client) ([]Playlist, error) {
+	list, resp, err := client.Playlists().List(ctx, 0)
+	if err == nil {
+		err = yamusic.CheckResponse(resp, list.Error)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("backup: list playlists: %w", err)
+	}
+
+	playlists := make([]Playlist, 0, len(list.Result))
+	for _, item := range list.Result {
+		playlist, resp, err := client.Playlists().Get(ctx, 0, item.Kind)
+		if err == nil {
+			err = yamusic.CheckResponse(resp, playlist.Error)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("backup: get playlist %d: %w", item.Kind, err)
+		}
+
+		tracks := make([]Track, 0, len(playlist.Result.Tracks))
+		for _, track := range playlist.Result.Tracks {
+			tracks = append(tracks, newTrack(track.Track))
+		}
+
+		playlists = append(playlists, Playlist{
+			Kind:       playlist.Result.Kind,
+			Title:      playlist.Result.Title,
+			Visibility: playlist.Result.Visibility,
+			Tracks:     tracks,
+		})
+	}
+
+	return playlists, nil
+}
+
+// Write writes archive as indented JSON.
+func Write(w io.Writer, archive *Archive) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(archive)
+}
+
+// Read reads archive and checks its version.
+func Read(r io.Reader) (*Archive, error) {
+	archive := new(Archive)
+	if err := json.NewDecoder(r).Decode(archive); err != nil {
+		return nil, fmt.Errorf("backup: decode archive: %w", err)
+	}
+
+	if archive.Version < 1 || archive.Version > Version {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, archive.Version)
+	}
+
+	return archive, nil
+}
+
+func newTrack(track yamusic.Track) Track {
+	t := Track{
+		ID:      track.ID,
+		Title:   track.Title,
+		Artists: track.ArtistNames(),
+	}
+	if len(track.Albums) > 0 {
+		t.AlbumID = track.Albums[0].ID
+	}
+
+	return t
+}
+
+func likedTracks(resp *yamusic.LikesTracksResp) []Track {
+	tracks := make([]Track, 0, len(resp.Result.Library.Tracks))
+	for _, liked := range resp.Result.Library.Tracks {
+		track := Track{ID: liked.ID}
+		track.AlbumID, _ = strconv.Atoi(liked.AlbumID)
+		tracks = append(tracks, track)
+	}
+
+	return tracks
+}
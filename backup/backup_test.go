@@ -0,0 +1,131 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setup returns mux of test server and client configured to use it.
+func setup(t *testing.T) (*http.ServeMux, *yamusic.Client) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	baseURL, _ := url.Parse(server.URL + "/")
+	return mux, yamusic.NewClient(yamusic.BaseURL(baseURL), yamusic.AccessToken(2000, "accessToken"))
+}
+
+func TestSnapshot(t *testing.T) {
+	mux, client := setup(t)
+
+	mux.HandleFunc("/account/status", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"account":{"uid":2000,"login":"john"}}}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[{"kind":1000,"title":"Road trip","trackCount":2}]}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/1000", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"kind":1000,"title":"Road trip","description":"Songs for the road","visibility":"public","tracks":[
+			{"id":1,"track":{"id":"1","title":"Yesterday","artists":[{"name":"The Beatles"}],"albums":[{"id":10}]}},
+			{"id":2,"track":{"id":"2","title":"Help!","artists":[{"name":"The Beatles"}],"albums":[{"id":20}]}}
+		]}}`)
+	})
+	mux.HandleFunc("/users/2000/likes/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"library":{"uid":2000,"tracks":[{"id":"1","albumId":"10"},{"id":"3"}]}}}`)
+	})
+	mux.HandleFunc("/users/2000/dislikes/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"library":{"uid":2000,"tracks":[{"id":"4","albumId":"40"}]}}}`)
+	})
+	mux.HandleFunc("/users/2000/likes/albums", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[{"id":20,"album":{"id":20,"title":"Help!","artists":[{"name":"The Beatles"}]}}]}`)
+	})
+	mux.HandleFunc("/users/2000/likes/artists", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[{"id":5,"artist":{"id":5,"name":"The Beatles"}}]}`)
+	})
+
+	archive, err := Snapshot(context.Background(), client)
+	require.NoError(t, err)
+
+	assert.Equal(t, Version, archive.Version)
+	assert.False(t, archive.CreatedAt.IsZero())
+	assert.Equal(t, 2000, archive.UID)
+	assert.Equal(t, "john", archive.Login)
+	assert.Equal(t, []Playlist{{
+		Kind:       1000,
+		Title:      "Road trip",
+		Visibility: "public",
+		Tracks: []Track{
+			{ID: "1", AlbumID: 10, Title: "Yesterday", Artists: "The Beatles"},
+			{ID: "2", AlbumID: 20, Title: "Help!", Artists: "The Beatles"},
+		},
+	}}, archive.Playlists)
+	assert.Equal(t, []Track{{ID: "1", AlbumID: 10}, {ID: "3"}}, archive.LikedTracks)
+	assert.Equal(t, []Track{{ID: "4", AlbumID: 40}}, archive.DislikedTracks)
+	assert.Equal(t, []Album{{ID: 20, Title: "Help!", Artists: "The Beatles"}}, archive.LikedAlbums)
+	assert.Equal(t, []Artist{{ID: 5, Name: "The Beatles"}}, archive.LikedArtists)
+}
+
+func TestSnapshot_Error(t *testing.T) {
+	mux, client := setup(t)
+
+	mux.HandleFunc("/account/status", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"account":{"uid":2000}}}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/list", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"name":"session-expired","message":"Your OAuth token is expired"}}`)
+	})
+
+	_, err := Snapshot(context.Background(), client)
+	assert.EqualError(t, err, "backup: list playlists: 401 Unauthorized: session-expired: Your OAuth token is expired")
+}
+
+func TestWriteRead(t *testing.T) {
+	archive := &Archive{
+		Version:   Version,
+		UID:       2000,
+		Playlists: []Playlist{{Kind: 1000, Title: "Road trip", Tracks: []Track{{ID: "1", AlbumID: 10}}}},
+		LikedTracks: []Track{
+			{ID: "1", AlbumID: 10},
+		},
+		DislikedTracks: []Track{},
+		LikedAlbums:    []Album{{ID: 20}},
+		LikedArtists:   []Artist{{ID: 5, Name: "The Beatles"}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, archive))
+	assert.Contains(t, buf.String(), `"version": 1`)
+
+	got, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, archive, got)
+}
+
+func TestRead_UnsupportedVersion(t *testing.T) {
+	for _, version := range []string{"0", "2"} {
+		_, err := Read(strings.NewReader(`{"version":` + version + `}`))
+		assert.True(t, errors.Is(err, ErrUnsupportedVersion), version)
+	}
+
+	_, err := Read(strings.NewReader(`not json`))
+	assert.Error(t, err)
+}
+
+func TestTrack_String(t *testing.T) {
+	assert.Equal(t, "1:10", Track{ID: "1", AlbumID: 10}.String())
+	assert.Equal(t, "1", Track{ID: "1"}.String())
+}
@@ -0,0 +1,465 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// batchSize is a maximum number of tracks, albums or artists sent in one
+// request.
+const batchSize = 100
+
+// ActionType is a type of change made by Restore.
+type ActionType string
+
+// Restore action types.
+const (
+	ActionCreatePlaylist ActionType = "create-playlist"
+	ActionSkipPlaylist   ActionType = "skip-playlist"
+	ActionResumePlaylist ActionType = "resume-playlist"
+	ActionLikeTracks     ActionType = "like-tracks"
+	ActionDislikeTracks  ActionType = "dislike-tracks"
+	ActionLikeAlbums     ActionType = "like-albums"
+	ActionLikeArtists    ActionType = "like-artists"
+)
+
+type (
+	// RestoreOptions are options of Restore.
+	RestoreOptions struct {
+		// DryRun only reports changes without making them.
+		DryRun bool
+		// Resume adds the rest of tracks to existing playlist with the same
+		// title which has the first tracks of the archived one, e.g. left
+		// by a failed restore, instead of creating a new playlist.
+		Resume bool
+	}
+
+	// RestoreReport describes changes made by Restore.
+	RestoreReport struct {
+		DryRun  bool     `json:"dryRun"`
+		Actions []Action `json:"actions"`
+	}
+
+	// Action is a single change made by Restore.
+	Action struct {
+		Type ActionType `json:"type"`
+		// Playlist is a title of created or skipped playlist.
+		Playlist string `json:"playlist,omitempty"`
+		// Kind is a kind of created, resumed or identical existing playlist.
+		// It's zero for playlists that would be created in dry-run mode.
+		Kind int `json:"kind,omitempty"`
+		// Added is a number of added tracks, albums or artists.
+		Added int `json:"added"`
+		// Present is a number of tracks, albums or artists
+		// that were already in the library.
+		Present int `json:"present"`
+	}
+)
+
+// Restore recreates playlists and likes from archive in the library of
+// the user the client is authorized as. Playlists that already exist with
+// the same title and tracks are skipped and only missing likes are added,
+// so restoring the same archive twice changes nothing.
+//
+// On error Restore returns report of changes made so far.
+func Restore(
+	ctx context.Context,
+	client *yamusic.Client,
+	archive *Archive,
+	opts *RestoreOptions,
+) (*RestoreReport, error) {
+	if opts == nil {
+		opts = &RestoreOptions{}
+	}
+
+	r := &restorer{
+		client: client,
+		dryRun: opts.DryRun,
+		resume: opts.Resume,
+		report: &RestoreReport{DryRun: opts.DryRun},
+	}
+
+	if err := r.restorePlaylists(ctx, archive.Playlists); err != nil {
+		return r.report, err
+	}
+
+	if err := r.restoreTracks(ctx, ActionLikeTracks, archive.LikedTracks); err != nil {
+		return r.report, err
+	}
+
+	if err := r.restoreTracks(ctx, ActionDislikeTracks, archive.DislikedTracks); err != nil {
+		return r.report, err
+	}
+
+	if err := r.restoreAlbums(ctx, archive.LikedAlbums); err != nil {
+		return r.report, err
+	}
+
+	if err := r.restoreArtists(ctx, archive.LikedArtists); err != nil {
+		return r.report, err
+	}
+
+	return r.report, nil
+}
+
+type restorer struct {
+	client *yamusic.Client
+	dryRun bool
+	resume bool
+	report *RestoreReport
+}
+
+func (r *restorer) restorePlaylists(ctx context.Context, playlists []Playlist) error {
+	if len(playlists) == 0 {
+		return nil
+	}
+
+	list, resp, err := r.client.Playlists().List(ctx, 0)
+	if err == nil {
+		err = yamusic.CheckResponse(resp, list.Error)
+	}
+	if err != nil {
+		return fmt.Errorf("backup: list playlists: %w", err)
+	}
+
+	for _, playlist := range playlists {
+		existing, err := r.findExisting(ctx, list.Result, playlist)
+		if err != nil {
+			return err
+		}
+
+		if existing != nil && existing.TrackCount == len(playlist.Tracks) {
+			// Identical playlist.
+			r.report.Actions = append(r.report.Actions, Action{
+				Type:     ActionSkipPlaylist,
+				Playlist: playlist.Title,
+				Kind:     existing.Kind,
+				Present:  len(playlist.Tracks),
+			})
+			continue
+		}
+
+		if err := r.createPlaylist(ctx, playlist, existing); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findExisting returns existing playlist with the same title and tracks
+// or nil if there is no such playlist. In resume mode a playlist with the
+// first tracks of the archived one is also returned, the one with the most
+// tracks is preferred.
+func (r *restorer) findExisting(
+	ctx context.Context,
+	existing []yamusic.PlaylistsResult,
+	playlist Playlist,
+) (*yamusic.PlaylistsResult, error) {
+	var found *yamusic.PlaylistsResult
+	for _, candidate := range existing {
+		if candidate.Title != playlist.Title || candidate.TrackCount > len(playlist.Tracks) {
+			continue
+		}
+		if !r.resume && candidate.TrackCount != len(playlist.Tracks) {
+			continue
+		}
+		if found != nil && candidate.TrackCount <= found.TrackCount {
+			continue
+		}
+
+		got, resp, err := r.client.Playlists().Get(ctx, 0, candidate.Kind)
+		if err == nil {
+			err = yamusic.CheckResponse(resp, got.Error)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("backup: get playlist %d: %w", candidate.Kind, err)
+		}
+
+		tracks := got.Result.Tracks
+		if !r.resume && len(tracks) != len(playlist.Tracks) {
+			continue
+		}
+
+		if prefixTracks(tracks, playlist.Tracks) {
+			result := got.Result.PlaylistsResult
+			result.TrackCount = len(tracks)
+			found = &result
+		}
+	}
+
+	return found, nil
+}
+
+// createPlaylist creates playlist with tracks of the archived one. If
+// existing playlist is not nil, only the rest of tracks are added to it.
+func (r *restorer) createPlaylist(ctx context.Context, playlist Playlist, existing *yamusic.PlaylistsResult) error {
+	tracks := make([]yamusic.PlaylistsTrack, 0, len(playlist.Tracks))
+	for _, track := range playlist.Tracks {
+		id, err := strconv.Atoi(track.ID)
+		if err != nil {
+			return fmt.Errorf("backup: playlist %q: invalid track id %q", playlist.Title, track.ID)
+		}
+		tracks = append(tracks, yamusic.PlaylistsTrack{ID: id, AlbumID: track.AlbumID})
+	}
+
+	action := Action{
+		Type:     ActionCreatePlaylist,
+		Playlist: playlist.Title,
+		Added:    len(tracks),
+	}
+	if existing != nil {
+		action.Type = ActionResumePlaylist
+		action.Kind = existing.Kind
+		action.Present = existing.TrackCount
+		action.Added = len(tracks) - existing.TrackCount
+		tracks = tracks[existing.TrackCount:]
+	}
+
+	if r.dryRun {
+		r.report.Actions = append(r.report.Actions, action)
+		return nil
+	}
+
+	var result yamusic.PlaylistsResult
+	if existing != nil {
+		result = *existing
+	} else {
+		created, resp, err := r.client.Playlists().Create(ctx, playlist.Title, playlist.Visibility == "public")
+		if err == nil {
+			err = yamusic.CheckResponse(resp, created.Error)
+		}
+		if err != nil {
+			return fmt.Errorf("backup: create playlist %q: %w", playlist.Title, err)
+		}
+		result = created.Result
+		action.Kind = result.Kind
+	}
+	action.Added = 0
+
+	err := batches(len(tracks), func(start, end int) error {
+		added, resp, err := r.client.Playlists().AddTracks(
+			ctx,
+			result.Kind,
+			result.Revision,
+			tracks[start:end],
+			&yamusic.PlaylistsAddTracksOptions{At: result.TrackCount},
+		)
+		if err == nil {
+			err = yamusic.CheckResponse(resp, added.Error)
+		}
+		if err == nil {
+			result = added.Result
+			action.Added = end
+		}
+		return err
+	})
+
+	r.report.Actions = append(r.report.Actions, action)
+	if err != nil {
+		return fmt.Errorf("backup: add tracks to playlist %q: %w", playlist.Title, err)
+	}
+
+	return nil
+}
+
+func (r *restorer) restoreTracks(ctx context.Context, actionType ActionType, tracks []Track) error {
+	if len(tracks) == 0 {
+		return nil
+	}
+
+	get, change := r.client.Likes().Tracks, r.client.Likes().LikeTracks
+	if actionType == ActionDislikeTracks {
+		get, change = r.client.Likes().DislikedTracks, r.client.Likes().DislikeTracks
+	}
+
+	library, resp, err := get(ctx)
+	if err == nil {
+		err = yamusic.CheckResponse(resp, library.Error)
+	}
+	if err != nil {
+		return fmt.Errorf("backup: %s: get library: %w", actionType, err)
+	}
+
+	present := make(map[string]bool, len(library.Result.Library.Tracks))
+	for _, track := range library.Result.Library.Tracks {
+		present[track.ID] = true
+	}
+
+	var missing []string
+	for _, track := range tracks {
+		if !present[track.ID] {
+			missing = append(missing, track.String())
+		}
+	}
+
+	action := Action{Type: actionType, Present: len(tracks) - len(missing)}
+	if r.dryRun {
+		action.Added = len(missing)
+		r.report.Actions = append(r.report.Actions, action)
+		return nil
+	}
+
+	err = batches(len(missing), func(start, end int) error {
+		changed, resp, err := change(ctx, missing[start:end])
+		if err == nil {
+			err = yamusic.CheckResponse(resp, changed.Error)
+		}
+		if err == nil {
+			action.Added = end
+		}
+		return err
+	})
+
+	r.report.Actions = append(r.report.Actions, action)
+	if err != nil {
+		return fmt.Errorf("backup: %s: %w", actionType, err)
+	}
+
+	return nil
+}
+
+func (r *restorer) restoreAlbums(ctx context.Context, albums []Album) error {
+	if len(albums) == 0 {
+		return nil
+	}
+
+	liked, resp, err := r.client.Likes().Albums(ctx)
+	if err == nil {
+		err = yamusic.CheckResponse(resp, liked.Error)
+	}
+	if err != nil {
+		return fmt.Errorf("backup: %s: get library: %w", ActionLikeAlbums, err)
+	}
+
+	present := make(map[int]bool, len(liked.Result))
+	for _, album := range liked.Result {
+		present[album.ID] = true
+	}
+
+	var missing []int
+	for _, album := range albums {
+		if !present[album.ID] {
+			missing = append(missing, album.ID)
+		}
+	}
+
+	return r.likeMissing(ctx, ActionLikeAlbums, len(albums), missing, r.client.Likes().LikeAlbums)
+}
+
+func (r *restorer) restoreArtists(ctx context.Context, artists []Artist) error {
+	if len(artists) == 0 {
+		return nil
+	}
+
+	liked, resp, err := r.client.Likes().Artists(ctx)
+	if err == nil {
+		err = yamusic.CheckResponse(resp, liked.Error)
+	}
+	if err != nil {
+		return fmt.Errorf("backup: %s: get library: %w", ActionLikeArtists, err)
+	}
+
+	present := make(map[int]bool, len(liked.Result))
+	for _, artist := range liked.Result {
+		present[artist.ID] = true
+	}
+
+	var missing []int
+	for _, artist := range artists {
+		if !present[artist.ID] {
+			missing = append(missing, artist.ID)
+		}
+	}
+
+	return r.likeMissing(ctx, ActionLikeArtists, len(artists), missing, r.client.Likes().LikeArtists)
+}
+
+// likeMissing likes missing albums or artists in batches.
+func (r *restorer) likeMissing(
+	ctx context.Context,
+	actionType ActionType,
+	total int,
+	missing []int,
+	like func(context.Context, []int, ...yamusic.RequestOption) (*yamusic.LikesChangeResp, *http.Response, error),
+) error {
+	action := Action{Type: actionType, Present: total - len(missing)}
+	if r.dryRun {
+		action.Added = len(missing)
+		r.report.Actions = append(r.report.Actions, action)
+		return nil
+	}
+
+	err := batches(len(missing), func(start, end int) error {
+		changed, resp, err := like(ctx, missing[start:end])
+		if err == nil {
+			err = yamusic.CheckResponse(resp, changed.Error)
+		}
+		if err == nil {
+			action.Added = end
+		}
+		return err
+	})
+
+	r.report.Actions = append(r.report.Actions, action)
+	if err != nil {
+		return fmt.Errorf("backup: %s: %w", actionType, err)
+	}
+
+	return nil
+}
+
+// WriteText writes human-readable list of actions.
+func (r *RestoreReport) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	if r.DryRun {
+		fmt.Fprintln(tw, "Dry run: no changes were made.")
+		fmt.Fprintln(tw)
+	}
+
+	fmt.Fprintln(tw, "ACTION\tPLAYLIST\tADDED\tPRESENT")
+	for _, action := range r.Actions {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\n", action.Type, action.Playlist, action.Added, action.Present)
+	}
+
+	return tw.Flush()
+}
+
+// prefixTracks reports whether playlist tracks are the first archived ones
+// in the same order.
+func prefixTracks(tracks yamusic.Tracks, archived []Track) bool {
+	if len(tracks) > len(archived) {
+		return false
+	}
+
+	for i, track := range tracks {
+		if track.Track.ID != archived[i].ID {
+			return false
+		}
+	}
+
+	return true
+}
+
+// batches calls fn with bounds of consecutive batches of n items.
+func batches(n int, fn func(start, end int) error) error {
+	for start := 0; start < n; start += batchSize {
+		end := start + batchSize
+		if end > n {
+			end = n
+		}
+
+		if err := fn(start, end); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testArchive = &Archive{
+	Version: Version,
+	Playlists: []Playlist{
+		{Kind: 1000, Title: "Road trip", Tracks: []Track{{ID: "1", AlbumID: 10}, {ID: "2", AlbumID: 20}}},
+		{Kind: 1001, Title: "Party", Visibility: "public", Tracks: []Track{{ID: "3", AlbumID: 30}}},
+	},
+	LikedTracks:    []Track{{ID: "1", AlbumID: 10}, {ID: "3", AlbumID: 30}},
+	DislikedTracks: []Track{{ID: "4", AlbumID: 40}},
+	LikedAlbums:    []Album{{ID: 10}, {ID: 20}},
+	LikedArtists:   []Artist{{ID: 5}},
+}
+
+// handleTargetLibrary registers handlers returning library of the target
+// account which already has "Road trip" playlist and some likes.
+func handleTargetLibrary(mux *http.ServeMux) {
+	mux.HandleFunc("/users/2000/playlists/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[
+			{"kind":7,"title":"Road trip","trackCount":2},
+			{"kind":8,"title":"Party","trackCount":1}
+		]}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/7", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"kind":7,"title":"Road trip","tracks":[{"track":{"id":"1"}},{"track":{"id":"2"}}]}}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/8", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"kind":8,"title":"Party","tracks":[{"track":{"id":"9"}}]}}`)
+	})
+	mux.HandleFunc("/users/2000/likes/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"library":{"tracks":[{"id":"1","albumId":"10"}]}}}`)
+	})
+	mux.HandleFunc("/users/2000/dislikes/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"library":{"tracks":[{"id":"4","albumId":"40"}]}}}`)
+	})
+	mux.HandleFunc("/users/2000/likes/albums", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[{"id":20}]}`)
+	})
+	mux.HandleFunc("/users/2000/likes/artists", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[]}`)
+	})
+}
+
+func TestRestore(t *testing.T) {
+	mux, client := setup(t)
+	handleTargetLibrary(mux)
+
+	mux.HandleFunc("/users/2000/playlists/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Party", r.FormValue("title"))
+		assert.Equal(t, "public", r.FormValue("visibility"))
+		fmt.Fprint(w, `{"result":{"kind":9,"title":"Party","revision":1}}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/9/change-relative", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.FormValue("revision"))
+		assert.JSONEq(t, `[{"op":"insert","at":0,"tracks":[{"id":3,"albumId":30}]}]`, r.FormValue("diff"))
+		fmt.Fprint(w, `{"result":{"kind":9,"revision":2,"trackCount":1}}`)
+	})
+	mux.HandleFunc("/users/2000/likes/tracks/add-multiple", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "3:30", r.FormValue("track-ids"))
+		fmt.Fprint(w, `{"result":{"revision":2}}`)
+	})
+	mux.HandleFunc("/users/2000/dislikes/tracks/add-multiple", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("disliked tracks are already present")
+	})
+	mux.HandleFunc("/users/2000/likes/albums/add-multiple", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "10", r.FormValue("album-ids"))
+		fmt.Fprint(w, `{"result":"ok"}`)
+	})
+	mux.HandleFunc("/users/2000/likes/artists/add-multiple", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "5", r.FormValue("artist-ids"))
+		fmt.Fprint(w, `{"result":"ok"}`)
+	})
+
+	report, err := Restore(context.Background(), client, testArchive, nil)
+	require.NoError(t, err)
+
+	assert.False(t, report.DryRun)
+	assert.Equal(t, []Action{
+		{Type: ActionSkipPlaylist, Playlist: "Road trip", Kind: 7, Present: 2},
+		{Type: ActionCreatePlaylist, Playlist: "Party", Kind: 9, Added: 1},
+		{Type: ActionLikeTracks, Added: 1, Present: 1},
+		{Type: ActionDislikeTracks, Present: 1},
+		{Type: ActionLikeAlbums, Added: 1, Present: 1},
+		{Type: ActionLikeArtists, Added: 1},
+	}, report.Actions)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteText(&buf))
+	assert.Contains(t, buf.String(), "skip-playlist    Road trip  0      2")
+	assert.NotContains(t, buf.String(), "Dry run")
+}
+
+func TestRestore_DryRun(t *testing.T) {
+	mux, client := setup(t)
+	handleTargetLibrary(mux)
+
+	for _, path := range []string{
+		"/users/2000/playlists/create",
+		"/users/2000/likes/tracks/add-multiple",
+		"/users/2000/likes/albums/add-multiple",
+		"/users/2000/likes/artists/add-multiple",
+	} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request in dry-run mode: %s", r.URL.Path)
+		})
+	}
+
+	report, err := Restore(context.Background(), client, testArchive, &RestoreOptions{DryRun: true})
+	require.NoError(t, err)
+
+	assert.True(t, report.DryRun)
+	assert.Equal(t, []Action{
+		{Type: ActionSkipPlaylist, Playlist: "Road trip", Kind: 7, Present: 2},
+		{Type: ActionCreatePlaylist, Playlist: "Party", Added: 1},
+		{Type: ActionLikeTracks, Added: 1, Present: 1},
+		{Type: ActionDislikeTracks, Present: 1},
+		{Type: ActionLikeAlbums, Added: 1, Present: 1},
+		{Type: ActionLikeArtists, Added: 1},
+	}, report.Actions)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteText(&buf))
+	assert.Contains(t, buf.String(), "Dry run: no changes were made.")
+}
+
+func TestRestore_Error(t *testing.T) {
+	mux, client := setup(t)
+	handleTargetLibrary(mux)
+
+	mux.HandleFunc("/users/2000/playlists/create", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"name":"validate","message":"too many playlists"}}`)
+	})
+
+	report, err := Restore(context.Background(), client, testArchive, nil)
+	assert.EqualError(t, err, `backup: create playlist "Party": 400 Bad Request: validate: too many playlists`)
+	assert.Equal(t, []Action{
+		{Type: ActionSkipPlaylist, Playlist: "Road trip", Kind: 7, Present: 2},
+	}, report.Actions)
+}
+
+func TestRestore_ResumePlaylist(t *testing.T) {
+	for _, resume := range []bool{false, true} {
+		mux, client := setup(t)
+
+		// Previous restore failed after adding the first track.
+		mux.HandleFunc("/users/2000/playlists/list", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"result":[{"kind":7,"title":"Road trip","trackCount":1,"revision":3}]}`)
+		})
+		mux.HandleFunc("/users/2000/playlists/7", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"result":{"kind":7,"title":"Road trip","revision":3,"tracks":[{"track":{"id":"1"}}]}}`)
+		})
+		mux.HandleFunc("/users/2000/playlists/create", func(w http.ResponseWriter, r *http.Request) {
+			assert.False(t, resume, "playlist must be resumed")
+			fmt.Fprint(w, `{"result":{"kind":9,"title":"Road trip","revision":1}}`)
+		})
+		mux.HandleFunc("/users/2000/playlists/9/change-relative", func(w http.ResponseWriter, r *http.Request) {
+			assert.JSONEq(t, `[{"op":"insert","at":0,"tracks":[{"id":1,"albumId":10},{"id":2,"albumId":20}]}]`, r.FormValue("diff"))
+			fmt.Fprint(w, `{"result":{"kind":9,"revision":2,"trackCount":2}}`)
+		})
+		mux.HandleFunc("/users/2000/playlists/7/change-relative", func(w http.ResponseWriter, r *http.Request) {
+			assert.True(t, resume, "existing playlist must not be changed")
+			assert.Equal(t, "3", r.FormValue("revision"))
+			assert.JSONEq(t, `[{"op":"insert","at":1,"tracks":[{"id":2,"albumId":20}]}]`, r.FormValue("diff"))
+			fmt.Fprint(w, `{"result":{"kind":7,"revision":4,"trackCount":2}}`)
+		})
+
+		archive := &Archive{Version: Version, Playlists: testArchive.Playlists[:1]}
+		report, err := Restore(context.Background(), client, archive, &RestoreOptions{Resume: resume})
+		require.NoError(t, err)
+
+		want := Action{Type: ActionCreatePlaylist, Playlist: "Road trip", Kind: 9, Added: 2}
+		if resume {
+			want = Action{Type: ActionResumePlaylist, Playlist: "Road trip", Kind: 7, Added: 1, Present: 1}
+		}
+		assert.Equal(t, []Action{want}, report.Actions)
+	}
+}
+
+func TestBatches(t *testing.T) {
+	var got [][2]int
+	err := batches(250, func(start, end int) error {
+		got = append(got, [2]int{start, end})
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, [][2]int{{0, 100}, {100, 200}, {200, 250}}, got)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ndrewnee/go-yamusic/backup"
+)
+
+var backupCommand = &command{
+	name:        "backup",
+	usage:       "backup create|restore [arguments]",
+	description: "Back up library of the user or restore it from archive.",
+	subcommands: []string{"create", "restore"},
+	run: func(a *app, ctx context.Context, args []string) error {
+		if len(args) == 0 {
+			return errUsage
+		}
+
+		subcommands := map[string]func(*app, context.Context, []string) error{
+			"create":  backupCreate,
+			"restore": backupRestore,
+		}
+
+		subcommand, ok := subcommands[args[0]]
+		if !ok {
+			return errUsage
+		}
+
+		return subcommand(a, ctx, args[1:])
+	},
+}
+
+// backupCreate writes archive of user's library: backup create [-o file]
+func backupCreate(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	output := flags.String("o", "", "output file (default stdout)")
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	archive, err := backup.Snapshot(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return backup.Write(a.stdout, archive)
+	}
+
+	file, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = backup.Write(file, archive)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(
+		a.stderr,
+		"Saved %d playlists, %d liked tracks, %d liked albums and %d liked artists to %s\n",
+		len(archive.Playlists),
+		len(archive.LikedTracks),
+		len(archive.LikedAlbums),
+		len(archive.LikedArtists),
+		*output,
+	)
+
+	return nil
+}
+
+// backupRestore restores library from archive: backup restore [--dry-run] [--resume] <file>
+func backupRestore(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	dryRun := flags.Bool("dry-run", false, "only print changes without making them")
+	resume := flags.Bool("resume", false, "add missing tracks to playlists left by a failed restore")
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	archive, err := backup.Read(file)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	report, err := backup.Restore(ctx, client, archive, &backup.RestoreOptions{DryRun: *dryRun, Resume: *resume})
+	if report != nil {
+		if a.jsonOutput {
+			if outputErr := a.output(report, nil, nil); outputErr != nil && err == nil {
+				err = outputErr
+			}
+		} else if writeErr := report.WriteText(a.stdout); writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+var authCommand = &command{
+	name:        "auth",
+	usage:       "auth [--token token] [--user-id id]",
+	description: "Save access token and user ID to the config file.",
+	run: func(a *app, ctx context.Context, args []string) error {
+		flags := a.newFlagSet()
+		token := flags.String("token", "", "OAuth access token (read from stdin if empty)")
+		userID := flags.Int("user-id", 0, "user ID (detected by the token if zero)")
+		if _, err := a.parseFlags(flags, args); err != nil {
+			return err
+		}
+
+		cfg, err := a.loadConfig()
+		if err != nil {
+			return err
+		}
+
+		if *token == "" {
+			fmt.Fprint(a.stderr, "Access token: ")
+			line, err := bufio.NewReader(a.stdin).ReadString('\n')
+			if err != nil && line == "" {
+				return fmt.Errorf("read token: %w", err)
+			}
+			*token = strings.TrimSpace(line)
+		}
+
+		if *token == "" {
+			return errors.New("empty access token")
+		}
+
+		cfg.AccessToken = *token
+		cfg.UserID = *userID
+
+		client, err := a.yamusic()
+		if err != nil {
+			return err
+		}
+
+		status, resp, err := client.Account().GetStatus(ctx)
+		if err != nil {
+			return err
+		}
+		if err := yamusic.CheckResponse(resp, status.Error); err != nil {
+			return err
+		}
+
+		if cfg.UserID == 0 {
+			cfg.UserID = status.Result.Account.UID
+		}
+
+		if cfg.UserID == 0 {
+			return errors.New("token is not authorized, pass --user-id explicitly")
+		}
+
+		if err := cfg.save(a.configPath); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(a.stdout, "Logged in as %s (%d)\n", status.Result.Account.Login, cfg.UserID)
+		return nil
+	},
+}
+
+var whoamiCommand = &command{
+	name:        "whoami",
+	usage:       "whoami",
+	description: "Print account status of the authorized user.",
+	run: func(a *app, ctx context.Context, args []string) error {
+		flags := a.newFlagSet()
+		if _, err := a.parseFlags(flags, args); err != nil {
+			return err
+		}
+
+		client, err := a.yamusic()
+		if err != nil {
+			return err
+		}
+
+		status, resp, err := client.Account().GetStatus(ctx)
+		if err != nil {
+			return err
+		}
+		if err := yamusic.CheckResponse(resp, status.Error); err != nil {
+			return err
+		}
+
+		account := status.Result.Account
+		return a.output(status.Result, nil, [][]string{
+			{"UID:", strconv.Itoa(account.UID)},
+			{"Login:", account.Login},
+			{"Name:", account.DisplayName},
+			{"Permissions:", strings.Join(status.Result.Permissions.Values, ", ")},
+			{"Until:", status.Result.Permissions.Until.Format("2006-01-02")},
+		})
+	},
+}
+
+var searchCommand = &command{
+	name:        "search",
+	usage:       "search [--type track|album|artist|playlist|podcast|all] [--page n] <query>",
+	description: "Search the catalog.",
+	run: func(a *app, ctx context.Context, args []string) error {
+		flags := a.newFlagSet()
+		searchType := flags.String("type", "track", "type of search: track, album, artist, playlist, podcast or all")
+		page := flags.Int("page", 0, "page of results")
+		args, err := a.parseFlags(flags, args)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			return errUsage
+		}
+
+		client, err := a.yamusic()
+		if err != nil {
+			return err
+		}
+
+		search := client.Search()
+		searchFuncs := map[string]func(
+			context.Context,
+			string,
+			*yamusic.SearchOptions,
+			...yamusic.RequestOption,
+		) (*yamusic.SearchResp, *http.Response, error){
+			"track":    search.Tracks,
+			"album":    search.Albums,
+			"artist":   search.Artists,
+			"playlist": search.Playlists,
+			"podcast":  search.Podcasts,
+			"all":      search.All,
+		}
+
+		searchFunc, ok := searchFuncs[*searchType]
+		if !ok {
+			return fmt.Errorf("unknown search type %q", *searchType)
+		}
+
+		result, resp, err := searchFunc(
+			ctx,
+			strings.Join(args, " "),
+			&yamusic.SearchOptions{Page: *page},
+		)
+		if err != nil {
+			return err
+		}
+		if err := yamusic.CheckResponse(resp, result.Error); err != nil {
+			return err
+		}
+
+		if a.jsonOutput {
+			return a.output(result.Result, nil, nil)
+		}
+
+		r := result.Result
+		var rows [][]string
+		for _, track := range r.Tracks.Results {
+			id := strconv.Itoa(track.ID)
+			if len(track.Albums) > 0 {
+				id += ":" + strconv.Itoa(track.Albums[0].ID)
+			}
+			rows = append(rows, []string{"track", id, track.Artists.Names(), track.Title})
+		}
+		for _, album := range r.Albums.Results {
+			rows = append(rows, []string{"album", strconv.Itoa(album.ID), album.Artists.Names(), album.Title})
+		}
+		for _, artist := range r.Artists.Results {
+			rows = append(rows, []string{"artist", strconv.Itoa(artist.ID), artist.Name, ""})
+		}
+		for _, playlist := range r.Playlists.Results {
+			id := yamusic.PlaylistID{UID: playlist.UID, Kind: playlist.Kind}
+			rows = append(rows, []string{"playlist", id.String(), playlist.Owner.Login, playlist.Title})
+		}
+		for _, podcast := range r.Podcasts.Results {
+			rows = append(rows, []string{"podcast", strconv.Itoa(podcast.ID), podcast.Artists.Names(), podcast.Title})
+		}
+
+		return a.output(nil, []string{"TYPE", "ID", "ARTISTS", "TITLE"}, rows)
+	},
+}
+
+var genresCommand = &command{
+	name:        "genres",
+	usage:       "genres [--lang code]",
+	description: "List genres.",
+	run: func(a *app, ctx context.Context, args []string) error {
+		flags := a.newFlagSet()
+		lang := flags.String("lang", "", "language of titles like en or ru")
+		if _, err := a.parseFlags(flags, args); err != nil {
+			return err
+		}
+
+		client, err := a.yamusic()
+		if err != nil {
+			return err
+		}
+
+		genres, resp, err := client.Genres().Tree(ctx)
+		if err != nil {
+			return err
+		}
+		if err := yamusic.CheckResponse(resp, genres.Error); err != nil {
+			return err
+		}
+
+		var rows [][]string
+		err = genres.Result.Walk(func(genre *yamusic.Genre, depth int) error {
+			indent := strings.Repeat("  ", depth)
+			rows = append(rows, []string{indent + genre.ID, genre.Title(*lang), strconv.Itoa(genre.TracksCount)})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return a.output(genres.Result, []string{"ID", "TITLE", "TRACKS"}, rows)
+	},
+}
+
+var feedCommand = &command{
+	name:        "feed",
+	usage:       "feed [--since day]",
+	description: "Print feed of the user.",
+	run: func(a *app, ctx context.Context, args []string) error {
+		flags := a.newFlagSet()
+		since := flags.String("since", "", "load days older than the day (YYYY-MM-DD)")
+		if _, err := a.parseFlags(flags, args); err != nil {
+			return err
+		}
+
+		client, err := a.yamusic()
+		if err != nil {
+			return err
+		}
+
+		var feed *yamusic.FeedResp
+		var resp *http.Response
+		if *since != "" {
+			feed, resp, err = client.Feed().GetMore(ctx, *since)
+		} else {
+			feed, resp, err = client.Feed().Get(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		if err := yamusic.CheckResponse(resp, feed.Error); err != nil {
+			return err
+		}
+
+		var rows [][]string
+		for _, day := range feed.Result.Days {
+			for _, event := range day.Events {
+				rows = append(rows, []string{day.Day, event.EventType(), feedEventSummary(event)})
+			}
+		}
+
+		return a.output(feed.Result, []string{"DAY", "TYPE", "SUMMARY"}, rows)
+	},
+}
+
+// feedEventSummary returns short description of the feed event.
+func feedEventSummary(event yamusic.FeedEvent) string {
+	switch e := event.(type) {
+	case *yamusic.TracksEvent:
+		return fmt.Sprintf("%s (%d tracks)", feedEventTitle(e.Title), len(e.Tracks))
+	case *yamusic.PromoEvent:
+		return e.Promo.Title
+	case *yamusic.GenreTopEvent:
+		return fmt.Sprintf("top of %s (%d tracks)", e.Genre, len(e.Tracks))
+	case *yamusic.NewAlbumsEvent:
+		return fmt.Sprintf("%s (%d albums)", feedEventTitle(e.Title), len(e.Albums))
+	case *yamusic.ArtistEvent:
+		return e.Artist.Name
+	case *yamusic.NotificationEvent:
+		return e.Message
+	case *yamusic.UnknownEvent:
+		return feedEventTitle(e.Title)
+	}
+
+	return ""
+}
+
+// feedEventTitle joins parts of the title.
+func feedEventTitle(title []yamusic.FeedEventTitle) string {
+	parts := make([]string, 0, len(title))
+	for _, part := range title {
+		parts = append(parts, part.Text)
+	}
+
+	return strings.Join(parts, " ")
+}
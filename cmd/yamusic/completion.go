@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+var completionCommand = &command{
+	name:        "completion",
+	usage:       "completion bash|zsh|fish",
+	description: "Print shell completion script.",
+	subcommands: []string{"bash", "zsh", "fish"},
+	run: func(a *app, ctx context.Context, args []string) error {
+		flags := a.newFlagSet()
+		args, err := a.parseFlags(flags, args)
+		if err != nil {
+			return err
+		}
+
+		if len(args) != 1 {
+			return errUsage
+		}
+
+		switch args[0] {
+		case "bash":
+			writeBashCompletion(a.stdout)
+		case "zsh":
+			// zsh can load bash completion scripts.
+			fmt.Fprintln(a.stdout, "autoload -U +X bashcompinit && bashcompinit")
+			writeBashCompletion(a.stdout)
+		case "fish":
+			writeFishCompletion(a.stdout)
+		default:
+			return errUsage
+		}
+
+		return nil
+	},
+}
+
+// commandNames returns sorted names of commands.
+func commandNames() []string {
+	names := make([]string, 0, len(registeredCommands))
+	for _, cmd := range registeredCommands {
+		names = append(names, cmd.name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func writeBashCompletion(w io.Writer) {
+	fmt.Fprintln(w, "_yamusic() {")
+	fmt.Fprintln(w, `	local cur="${COMP_WORDS[COMP_CWORD]}"`)
+	fmt.Fprintln(w, `	if [ "$COMP_CWORD" -eq 1 ]; then`)
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(commandNames(), " "))
+	fmt.Fprintln(w, "\t\treturn")
+	fmt.Fprintln(w, "\tfi")
+	fmt.Fprintln(w, `	if [ "$COMP_CWORD" -eq 2 ]; then`)
+	fmt.Fprintln(w, `		case "${COMP_WORDS[1]}" in`)
+	for _, cmd := range registeredCommands {
+		if len(cmd.subcommands) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\t\t%s) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", cmd.name, strings.Join(cmd.subcommands, " "))
+	}
+	fmt.Fprintln(w, "\t\tesac")
+	fmt.Fprintln(w, "\tfi")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "complete -o default -F _yamusic yamusic")
+}
+
+func writeFishCompletion(w io.Writer) {
+	names := strings.Join(commandNames(), " ")
+	fmt.Fprintf(w, "complete -c yamusic -f -n '__fish_use_subcommand' -a '%s'\n", names)
+	for _, cmd := range registeredCommands {
+		if len(cmd.subcommands) == 0 {
+			continue
+		}
+		fmt.Fprintf(
+			w,
+			"complete -c yamusic -f -n '__fish_seen_subcommand_from %s' -a '%s'\n",
+			cmd.name,
+			strings.Join(cmd.subcommands, " "),
+		)
+	}
+	fmt.Fprintln(w, "complete -c yamusic -l json -d 'print output as JSON'")
+	fmt.Fprintln(w, "complete -c yamusic -l config -r -d 'path to config file'")
+}
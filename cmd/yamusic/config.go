@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// config is a config file of the tool.
+type config struct {
+	UserID      int    `json:"userId"`
+	AccessToken string `json:"accessToken"`
+	APIURL      string `json:"apiUrl,omitempty"`
+}
+
+// defaultConfigPath returns path of config file in user's config directory.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+
+	return filepath.Join(dir, "yamusic", "config.json")
+}
+
+// loadConfig reads config from path. Missing file means empty config.
+// YANDEX_USER_ID and YANDEX_ACCESS_TOKEN environment variables override
+// values from the file.
+func loadConfig(path string) (*config, error) {
+	cfg := new(config)
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if err == nil {
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if userID := os.Getenv("YANDEX_USER_ID"); userID != "" {
+		cfg.UserID, err = strconv.Atoi(userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if accessToken := os.Getenv("YANDEX_ACCESS_TOKEN"); accessToken != "" {
+		cfg.AccessToken = accessToken
+	}
+
+	return cfg, nil
+}
+
+// save writes config to path readable only by the user.
+func (c *config) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
@@ -0,0 +1,256 @@
+// Command yamusic is a command-line client for Yandex.Music API.
+//
+// Usage:
+//
+//	yamusic [global flags] <command> [flags] [arguments]
+//
+// Run `yamusic help` to see all commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// errUsage is returned by commands when they were called with wrong arguments.
+var errUsage = errors.New("wrong usage")
+
+type (
+	// app is a state shared by commands.
+	app struct {
+		stdin  io.Reader
+		stdout io.Writer
+		stderr io.Writer
+
+		configPath string
+		config     *config
+		jsonOutput bool
+		apiURL     string
+		httpClient yamusic.Doer
+
+		// cmd is a command being run.
+		cmd    *command
+		client *yamusic.Client
+	}
+
+	// command is a subcommand of the tool.
+	command struct {
+		name        string
+		usage       string
+		description string
+		subcommands []string
+		run         func(a *app, ctx context.Context, args []string) error
+	}
+)
+
+// registeredCommands are all commands of the tool. It's filled in init
+// because some commands list other commands.
+var registeredCommands []*command
+
+func init() {
+	registeredCommands = []*command{
+		authCommand,
+		whoamiCommand,
+		searchCommand,
+		playlistCommand,
+		trackCommand,
+		genresCommand,
+		feedCommand,
+		backupCommand,
+		syncCommand,
+		subsonicCommand,
+		mpdCommand,
+		proxyCommand,
+		completionCommand,
+	}
+}
+
+// commands returns all commands by name.
+func commands() map[string]*command {
+	byName := make(map[string]*command, len(registeredCommands))
+	for _, cmd := range registeredCommands {
+		byName[cmd.name] = cmd
+	}
+
+	return byName
+}
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the tool with args and returns exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	a := &app{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}
+
+	flags := flag.NewFlagSet("yamusic", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { a.printUsage(stderr) }
+	a.globalFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	args = flags.Args()
+	if len(args) == 0 || args[0] == "help" {
+		a.printUsage(stdout)
+		return 0
+	}
+
+	cmd, ok := commands()[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "yamusic: unknown command %q\n", args[0])
+		a.printUsage(stderr)
+		return 2
+	}
+
+	a.cmd = cmd
+	if err := cmd.run(a, ctx, args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		if err == errUsage {
+			fmt.Fprintf(stderr, "usage: yamusic %s\n", cmd.usage)
+			return 2
+		}
+		fmt.Fprintf(stderr, "yamusic %s: %v\n", cmd.name, err)
+		return 1
+	}
+
+	return 0
+}
+
+// globalFlags registers flags accepted by the tool and every command.
+func (a *app) globalFlags(flags *flag.FlagSet) {
+	flags.BoolVar(&a.jsonOutput, "json", a.jsonOutput, "print output as JSON")
+	flags.StringVar(&a.configPath, "config", a.configPath, "path to config file (default "+defaultConfigPath()+")")
+	flags.StringVar(&a.apiURL, "api-url", a.apiURL, "Yandex.Music API URL")
+}
+
+// newFlagSet returns flag set of the running command with global flags.
+func (a *app) newFlagSet() *flag.FlagSet {
+	cmd := a.cmd
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(a.stderr, "usage: yamusic %s\n\n%s\n\nflags:\n", cmd.usage, cmd.description)
+		flags.PrintDefaults()
+	}
+	a.globalFlags(flags)
+	return flags
+}
+
+// parseFlags parses flags of the command. Flags and positional arguments
+// can be mixed.
+func (a *app) parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// printUsage prints list of commands.
+func (a *app) printUsage(w io.Writer) {
+	fmt.Fprintln(w, "yamusic is a command-line client for Yandex.Music API.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "usage: yamusic [--json] [--config path] <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+
+	cmds := commands()
+	names := make([]string, 0, len(cmds))
+	for name := range cmds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, []string{"  " + name, cmds[name].description})
+	}
+	printRows(w, rows)
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run `yamusic <command> --help` for command's flags.")
+}
+
+// loadConfig loads config once.
+func (a *app) loadConfig() (*config, error) {
+	if a.config != nil {
+		return a.config, nil
+	}
+
+	if a.configPath == "" {
+		a.configPath = defaultConfigPath()
+	}
+
+	cfg, err := loadConfig(a.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	a.config = cfg
+	return cfg, nil
+}
+
+// yamusic returns API client configured from the config.
+func (a *app) yamusic() (*yamusic.Client, error) {
+	if a.client != nil {
+		return a.client, nil
+	}
+
+	cfg, err := a.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	options := []func(*yamusic.Client){
+		yamusic.AccessToken(cfg.UserID, cfg.AccessToken),
+		yamusic.HTTPClient(a.httpClient),
+	}
+
+	apiURL := a.apiURL
+	if apiURL == "" {
+		apiURL = cfg.APIURL
+	}
+	if apiURL != "" {
+		if !strings.HasSuffix(apiURL, "/") {
+			apiURL += "/"
+		}
+		baseURL, err := url.Parse(apiURL)
+		if err != nil {
+			return nil, fmt.Errorf("parse api url: %w", err)
+		}
+		options = append(options, yamusic.BaseURL(baseURL))
+	}
+
+	a.client = yamusic.NewClient(options...)
+	return a.client, nil
+}
@@ -0,0 +1,450 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	userID      = 2000
+	accessToken = "accessToken"
+)
+
+// runTool runs the tool against the test server and returns exit code, stdout
+// and stderr.
+func runTool(t *testing.T, server *httptest.Server, configPath string, args ...string) (int, string, string) {
+	t.Helper()
+
+	t.Setenv("YANDEX_USER_ID", "")
+	t.Setenv("YANDEX_ACCESS_TOKEN", "")
+
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"--config", configPath, "--api-url", server.URL}, args...)
+	code := run(context.Background(), args, strings.NewReader(""), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// setupTool returns test server with mux and path of config file of an
+// authorized user.
+func setupTool(t *testing.T) (*http.ServeMux, *httptest.Server, string) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := &config{UserID: userID, AccessToken: accessToken}
+	require.NoError(t, cfg.save(configPath))
+
+	return mux, server, configPath
+}
+
+func TestRun_Help(t *testing.T) {
+	_, server, configPath := setupTool(t)
+
+	code, stdout, _ := runTool(t, server, configPath, "help")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "playlist")
+	assert.Contains(t, stdout, "whoami")
+
+	code, _, stderr := runTool(t, server, configPath, "unknown")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `unknown command "unknown"`)
+
+	code, _, stderr = runTool(t, server, configPath, "playlist")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "usage: yamusic playlist")
+}
+
+func TestAuth(t *testing.T) {
+	mux, server, _ := setupTool(t)
+	configPath := filepath.Join(t.TempDir(), "yamusic", "config.json")
+
+	mux.HandleFunc("/account/status", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "OAuth newToken", r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"result":{"account":{"uid":3000,"login":"user"}}}`)
+	})
+
+	code, stdout, stderr := runTool(t, server, configPath, "auth", "--token", "newToken")
+	require.Equal(t, 0, code, stderr)
+	assert.Equal(t, "Logged in as user (3000)\n", stdout)
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+
+	var cfg config
+	require.NoError(t, json.Unmarshal(data, &cfg))
+	assert.Equal(t, config{UserID: 3000, AccessToken: "newToken"}, cfg)
+
+	info, err := os.Stat(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestWhoami(t *testing.T) {
+	mux, server, configPath := setupTool(t)
+
+	mux.HandleFunc("/account/status", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"result":{"account":{"uid":2000,"login":"user","displayName":"User"},"permissions":{"values":["landing-play"]}}}`)
+	})
+
+	code, stdout, stderr := runTool(t, server, configPath, "whoami")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "Login:")
+	assert.Contains(t, stdout, "user")
+	assert.Contains(t, stdout, "landing-play")
+
+	code, stdout, stderr = runTool(t, server, configPath, "whoami", "--json")
+	require.Equal(t, 0, code, stderr)
+
+	var result struct {
+		Account struct {
+			UID int `json:"uid"`
+		} `json:"account"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	assert.Equal(t, userID, result.Account.UID)
+}
+
+func TestWhoami_Error(t *testing.T) {
+	mux, server, configPath := setupTool(t)
+
+	mux.HandleFunc("/account/status", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"name":"session-expired","message":"Your OAuth token is expired"}}`)
+	})
+
+	code, _, stderr := runTool(t, server, configPath, "whoami")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "session-expired")
+}
+
+func TestSearch(t *testing.T) {
+	mux, server, configPath := setupTool(t)
+
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "track", r.URL.Query().Get("type"))
+		assert.Equal(t, "the beatles", r.URL.Query().Get("text"))
+		fmt.Fprint(w, `{"result":{"tracks":{"results":[{"id":1,"title":"Yesterday","artists":[{"name":"The Beatles"}],"albums":[{"id":2}]}]}}}`)
+	})
+
+	code, stdout, stderr := runTool(t, server, configPath, "search", "the", "beatles", "--type", "track")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "1:2")
+	assert.Contains(t, stdout, "Yesterday")
+	assert.Contains(t, stdout, "The Beatles")
+}
+
+func TestPlaylistAdd(t *testing.T) {
+	mux, server, configPath := setupTool(t)
+
+	mux.HandleFunc("/users/2000/playlists/3", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"kind":3,"revision":5,"trackCount":2}}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/3/change-relative", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "5", r.FormValue("revision"))
+		assert.JSONEq(t,
+			`[{"op":"insert","at":2,"tracks":[{"id":10,"albumId":20},{"id":11,"albumId":21}]}]`,
+			r.FormValue("diff"),
+		)
+		fmt.Fprint(w, `{"result":{"kind":3,"revision":6,"trackCount":4}}`)
+	})
+
+	code, stdout, stderr := runTool(t, server, configPath, "playlist", "add", "3", "10:20", "11:21")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "Revision:")
+	assert.Contains(t, stdout, "6")
+}
+
+func TestPlaylistRemove(t *testing.T) {
+	mux, server, configPath := setupTool(t)
+
+	mux.HandleFunc("/users/2000/playlists/3", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"kind":3,"revision":5,"trackCount":3,"tracks":[
+			{"id":10,"track":{"id":"10","albums":[{"id":20}]}},
+			{"id":11,"track":{"id":"11","albums":[{"id":21}]}},
+			{"id":10,"track":{"id":"10","albums":[{"id":20}]}}
+		]}}`)
+	})
+
+	var diffs []string
+	revision := 5
+	mux.HandleFunc("/users/2000/playlists/3/change-relative", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, fmt.Sprint(revision), r.FormValue("revision"))
+		diffs = append(diffs, r.FormValue("diff"))
+		revision++
+		fmt.Fprintf(w, `{"result":{"kind":3,"revision":%d}}`, revision)
+	})
+
+	code, _, stderr := runTool(t, server, configPath, "playlist", "remove", "3", "10")
+	require.Equal(t, 0, code, stderr)
+	require.Len(t, diffs, 2)
+	assert.JSONEq(t, `[{"op":"delete","from":2,"to":3,"tracks":[{"id":10,"albumId":20}]}]`, diffs[0])
+	assert.JSONEq(t, `[{"op":"delete","from":0,"to":1,"tracks":[{"id":10,"albumId":20}]}]`, diffs[1])
+}
+
+func TestGenres(t *testing.T) {
+	mux, server, configPath := setupTool(t)
+
+	mux.HandleFunc("/genres", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[{"id":"rock","title":"Рок","tracksCount":10,"titles":{"en":{"title":"Rock"}},`+
+			`"subGenres":[{"id":"punk","title":"Панк","tracksCount":3,"subGenres":[{"id":"oi","title":"Ой!"}]}]}]}`)
+	})
+
+	code, stdout, stderr := runTool(t, server, configPath, "genres")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "rock")
+	assert.Contains(t, stdout, "Рок")
+	assert.Contains(t, stdout, "  punk")
+	assert.Contains(t, stdout, "    oi")
+
+	code, stdout, stderr = runTool(t, server, configPath, "genres", "--lang", "en")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "Rock")
+	assert.Contains(t, stdout, "Панк")
+
+	code, stdout, stderr = runTool(t, server, configPath, "--json", "genres")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, `"id": "oi"`)
+}
+
+func TestCompletion(t *testing.T) {
+	_, server, configPath := setupTool(t)
+
+	code, stdout, _ := runTool(t, server, configPath, "completion", "bash")
+	require.Equal(t, 0, code)
+	assert.Contains(t, stdout, "complete -o default -F _yamusic yamusic")
+	assert.Contains(t, stdout, "list show create rename delete add remove")
+
+	code, stdout, _ = runTool(t, server, configPath, "completion", "fish")
+	require.Equal(t, 0, code)
+	assert.Contains(t, stdout, "__fish_seen_subcommand_from track")
+
+	code, _, _ = runTool(t, server, configPath, "completion", "tcsh")
+	assert.Equal(t, 2, code)
+}
+
+func TestPlaylistExport(t *testing.T) {
+	mux, server, configPath := setupTool(t)
+
+	mux.HandleFunc("/users/2000/playlists/3", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"kind":3,"title":"Road trip","tracks":[
+			{"id":10,"track":{"id":"10","title":"Yesterday","durationMs":125000,"artists":[{"name":"The Beatles"}],"albums":[{"id":20}]}}
+		]}}`)
+	})
+
+	output := filepath.Join(t.TempDir(), "road.csv")
+	code, _, stderr := runTool(t, server, configPath, "playlist", "export", "3", "-o", output)
+	require.Equal(t, 0, code, stderr)
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "10,20,The Beatles,Yesterday,,2:05,https://music.yandex.ru/album/20/track/10\n")
+
+	code, stdout, stderr := runTool(t, server, configPath, "playlist", "export", "3")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "#EXTINF:125,The Beatles - Yesterday\n")
+}
+
+func TestPlaylistImport(t *testing.T) {
+	mux, server, configPath := setupTool(t)
+
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "The Beatles Yesterday", r.URL.Query().Get("text"))
+		fmt.Fprint(w, `{"result":{"tracks":{"results":[
+			{"id":10,"title":"Yesterday","durationMs":125000,"artists":[{"name":"The Beatles"}],"albums":[{"id":20}]}
+		]}}}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "road", r.FormValue("title"))
+		fmt.Fprint(w, `{"result":{"kind":5,"title":"road","revision":1}}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/5/change-relative", func(w http.ResponseWriter, r *http.Request) {
+		assert.JSONEq(t, `[{"op":"insert","at":0,"tracks":[{"id":10,"albumId":20}]}]`, r.FormValue("diff"))
+		fmt.Fprint(w, `{"result":{"kind":5,"title":"road","revision":2,"trackCount":1}}`)
+	})
+
+	input := filepath.Join(t.TempDir(), "road.m3u")
+	require.NoError(t, os.WriteFile(input, []byte("#EXTM3U\n#EXTINF:125,The Beatles - Yesterday\nyesterday.mp3\n"), 0o600))
+
+	code, stdout, stderr := runTool(t, server, configPath, "playlist", "import", input)
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "road (kind 5)")
+	assert.Contains(t, stdout, "Matched:         1")
+}
+
+func TestBackup(t *testing.T) {
+	mux, server, configPath := setupTool(t)
+
+	mux.HandleFunc("/account/status", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"account":{"uid":2000,"login":"john"}}}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[{"kind":1000,"title":"Road trip","trackCount":1}]}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/1000", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"kind":1000,"title":"Road trip","tracks":[{"track":{"id":"1","albums":[{"id":10}]}}]}}`)
+	})
+	mux.HandleFunc("/users/2000/likes/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"library":{"tracks":[{"id":"1","albumId":"10"}]}}}`)
+	})
+	mux.HandleFunc("/users/2000/dislikes/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"library":{"tracks":[]}}}`)
+	})
+	mux.HandleFunc("/users/2000/likes/albums", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[]}`)
+	})
+	mux.HandleFunc("/users/2000/likes/artists", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[]}`)
+	})
+
+	archive := filepath.Join(t.TempDir(), "library.json")
+	code, _, stderr := runTool(t, server, configPath, "backup", "create", "-o", archive)
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stderr, "Saved 1 playlists, 1 liked tracks")
+
+	// Restoring to the same library changes nothing.
+	code, stdout, stderr := runTool(t, server, configPath, "backup", "restore", "--dry-run", archive)
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "Dry run: no changes were made.")
+	assert.Contains(t, stdout, "skip-playlist  Road trip  0      1")
+	assert.Contains(t, stdout, "like-tracks               0      1")
+}
+
+func TestSync(t *testing.T) {
+	mux, server, configPath := setupTool(t)
+
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"tracks":{"results":[
+			{"id":2,"title":"Yesterday","artists":[{"name":"The Beatles"}],"albums":[{"id":20}]}
+		]}}}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[]}`)
+	})
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "The Beatles - Yesterday.mp3"), []byte("audio"), 0o600))
+
+	code, stdout, stderr := runTool(t, server, configPath, "sync", "--dry-run", "--title", "Music", dir)
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "Added to playlist:      1")
+	assert.Contains(t, stdout, "Unmatched files:        0")
+}
+
+// startServer runs server command in background and returns address from
+// its banner "... on <address>" and function which interrupts the command
+// and returns its exit code.
+func startServer(t *testing.T, server *httptest.Server, configPath string, args ...string) (string, func() int) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	stderrReader, stderrWriter := io.Pipe()
+	codes := make(chan int, 1)
+	go func() {
+		args = append([]string{"--config", configPath, "--api-url", server.URL}, args...)
+		codes <- run(ctx, args, strings.NewReader(""), io.Discard, stderrWriter)
+		stderrWriter.Close()
+	}()
+
+	line, err := bufio.NewReader(stderrReader).ReadString('\n')
+	require.NoError(t, err)
+	go io.Copy(io.Discard, stderrReader)
+
+	fields := strings.Fields(line)
+	return fields[len(fields)-1], func() int {
+		cancel()
+		return <-codes
+	}
+}
+
+func TestSubsonic(t *testing.T) {
+	_, server, configPath := setupTool(t)
+
+	code, _, stderr := runTool(t, server, configPath, "subsonic", "--username", "admin")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "usage: yamusic subsonic")
+
+	serverURL, stop := startServer(t, server, configPath,
+		"subsonic", "--addr", "127.0.0.1:0", "--username", "admin", "--password", "secret")
+
+	resp, err := http.Get(serverURL + "ping.view?u=admin&p=secret&f=json")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"status":"ok"`)
+
+	assert.Equal(t, 0, stop())
+}
+
+func TestMPD(t *testing.T) {
+	_, server, configPath := setupTool(t)
+
+	code, _, stderr := runTool(t, server, configPath, "mpd", "extra")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "usage: yamusic mpd")
+
+	addr, stop := startServer(t, server, configPath, "mpd", "--addr", "127.0.0.1:0")
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = io.WriteString(conn, "ping\n")
+	require.NoError(t, err)
+	reader := bufio.NewReader(conn)
+	greeting, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(greeting, "OK MPD "), greeting)
+	ok, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "OK\n", ok)
+
+	assert.Equal(t, 0, stop())
+}
+
+func TestProxy(t *testing.T) {
+	mux, server, configPath := setupTool(t)
+
+	mux.HandleFunc("/tracks/1/download-info", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"name":"not-found"}}`)
+	})
+
+	code, _, stderr := runTool(t, server, configPath, "proxy", "--cache-size", "0")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "usage: yamusic proxy")
+
+	cacheDir := filepath.Join(t.TempDir(), "tracks")
+	trackURL, stop := startServer(t, server, configPath,
+		"proxy", "--addr", "127.0.0.1:0", "--cache-dir", cacheDir)
+	trackURL = strings.Replace(trackURL, "{id}", "1", 1)
+
+	resp, err := http.Get(trackURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.DirExists(t, cacheDir)
+
+	assert.Equal(t, 0, stop())
+}
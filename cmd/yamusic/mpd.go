@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+
+	"github.com/ndrewnee/go-yamusic/mpd"
+)
+
+var mpdCommand = &command{
+	name:        "mpd",
+	usage:       "mpd [--addr address] [--output file]",
+	description: "Serve catalog and playlists over MPD protocol for MPD clients.",
+	run:         serveMPD,
+}
+
+// serveMPD runs MPD server until interrupted.
+func serveMPD(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	addr := flags.String("addr", "localhost:6600", "address to listen on")
+	output := flags.String("output", "", "file or FIFO to write audio of played songs to (default discard)")
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	opts := &mpd.Options{}
+	if *output != "" {
+		opts.Sink = mpd.FileSink(*output)
+	}
+	if a.httpClient != nil {
+		opts.HTTPClient = a.httpClient
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	fmt.Fprintf(a.stderr, "Serving MPD protocol on %s\n", listener.Addr())
+	return mpd.NewServer(client, opts).Serve(ctx, listener)
+}
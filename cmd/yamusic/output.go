@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// output prints v as JSON if --json flag is set or as a table otherwise.
+func (a *app) output(v interface{}, headers []string, rows [][]string) error {
+	if a.jsonOutput {
+		encoder := json.NewEncoder(a.stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+
+	if len(headers) > 0 {
+		rows = append([][]string{headers}, rows...)
+	}
+
+	printRows(a.stdout, rows)
+	return nil
+}
+
+// printRows prints rows aligned by columns.
+func printRows(w io.Writer, rows [][]string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	tw.Flush()
+}
+
+// formatDuration formats duration in milliseconds as M:SS.
+func formatDuration(durationMs int) string {
+	d := time.Duration(durationMs) * time.Millisecond
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// trackRow returns table row of the track.
+func trackRow(track yamusic.Track) []string {
+	var album string
+	if len(track.Albums) > 0 {
+		album = track.Albums[0].Title
+	}
+
+	return []string{
+		trackID(track),
+		track.ArtistNames(),
+		track.Title,
+		album,
+		formatDuration(track.DurationMs),
+	}
+}
+
+// trackID returns "trackID:albumID" of the track.
+func trackID(track yamusic.Track) string {
+	if len(track.Albums) == 0 {
+		return track.ID
+	}
+
+	return fmt.Sprintf("%s:%d", track.ID, track.Albums[0].ID)
+}
+
+var trackHeaders = []string{"ID", "ARTISTS", "TITLE", "ALBUM", "DURATION"}
@@ -0,0 +1,525 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ndrewnee/go-yamusic/export"
+	"github.com/ndrewnee/go-yamusic/importer"
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+var playlistCommand = &command{
+	name:        "playlist",
+	usage:       "playlist list|show|create|rename|delete|add|remove|export [arguments]",
+	description: "Manage playlists of the user.",
+	subcommands: []string{"list", "show", "create", "rename", "delete", "add", "remove", "export", "import"},
+	run: func(a *app, ctx context.Context, args []string) error {
+		if len(args) == 0 {
+			return errUsage
+		}
+
+		subcommands := map[string]func(*app, context.Context, []string) error{
+			"list":   playlistList,
+			"show":   playlistShow,
+			"create": playlistCreate,
+			"rename": playlistRename,
+			"delete": playlistDelete,
+			"add":    playlistAdd,
+			"remove": playlistRemove,
+			"export": playlistExport,
+			"import": playlistImport,
+		}
+
+		subcommand, ok := subcommands[args[0]]
+		if !ok {
+			return errUsage
+		}
+
+		return subcommand(a, ctx, args[1:])
+	},
+}
+
+// playlistList prints playlists: playlist list [--user-id id]
+func playlistList(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	userID := flags.Int("user-id", 0, "owner of playlists (default authorized user)")
+	if _, err := a.parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	playlists, resp, err := client.Playlists().List(ctx, *userID)
+	if err != nil {
+		return err
+	}
+	if err := yamusic.CheckResponse(resp, playlists.Error); err != nil {
+		return err
+	}
+
+	var rows [][]string
+	for _, playlist := range playlists.Result {
+		rows = append(rows, []string{
+			strconv.Itoa(playlist.Kind),
+			playlist.Title,
+			strconv.Itoa(playlist.TrackCount),
+			playlist.Visibility,
+		})
+	}
+
+	return a.output(playlists.Result, []string{"KIND", "TITLE", "TRACKS", "VISIBILITY"}, rows)
+}
+
+// playlistShow prints tracks of playlist: playlist show [--user-id id] <kind>
+func playlistShow(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	userID := flags.Int("user-id", 0, "owner of playlist (default authorized user)")
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	kind, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("parse kind: %w", err)
+	}
+
+	playlist, err := a.getPlaylist(ctx, *userID, kind)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(playlist.Result.Tracks))
+	for _, track := range playlist.Result.Tracks {
+		rows = append(rows, trackRow(track.Track))
+	}
+
+	return a.output(playlist.Result, trackHeaders, rows)
+}
+
+// playlistCreate creates playlist: playlist create [--public] <title>
+func playlistCreate(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	public := flags.Bool("public", false, "create public playlist")
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	playlist, resp, err := client.Playlists().Create(ctx, strings.Join(args, " "), *public)
+	if err != nil {
+		return err
+	}
+	if err := yamusic.CheckResponse(resp, playlist.Error); err != nil {
+		return err
+	}
+
+	return a.output(playlist.Result, nil, [][]string{
+		{"Created playlist", strconv.Itoa(playlist.Result.Kind), playlist.Result.Title},
+	})
+}
+
+// playlistRename renames playlist: playlist rename <kind> <title>
+func playlistRename(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) < 2 {
+		return errUsage
+	}
+
+	kind, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("parse kind: %w", err)
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	playlist, resp, err := client.Playlists().Rename(ctx, kind, strings.Join(args[1:], " "))
+	if err != nil {
+		return err
+	}
+	if err := yamusic.CheckResponse(resp, playlist.Error); err != nil {
+		return err
+	}
+
+	return a.output(playlist.Result, nil, [][]string{
+		{"Renamed playlist", strconv.Itoa(playlist.Result.Kind), playlist.Result.Title},
+	})
+}
+
+// playlistDelete deletes playlist: playlist delete <kind>
+func playlistDelete(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	kind, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("parse kind: %w", err)
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	deleted, resp, err := client.Playlists().Delete(ctx, kind)
+	if err != nil {
+		return err
+	}
+	if err := yamusic.CheckResponse(resp, deleted.Error); err != nil {
+		return err
+	}
+
+	return a.output(deleted.Result, nil, [][]string{{"Deleted playlist", strconv.Itoa(kind)}})
+}
+
+// playlistAdd appends tracks to the end of playlist:
+// playlist add <kind> <trackID:albumID>...
+func playlistAdd(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) < 2 {
+		return errUsage
+	}
+
+	kind, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("parse kind: %w", err)
+	}
+
+	tracks := make([]yamusic.PlaylistsTrack, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		track, err := parsePlaylistsTrack(arg)
+		if err != nil {
+			return err
+		}
+		if track.AlbumID == 0 {
+			return fmt.Errorf("track %q: album ID is required, use trackID:albumID", arg)
+		}
+		tracks = append(tracks, track)
+	}
+
+	playlist, err := a.getPlaylist(ctx, 0, kind)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	added, resp, err := client.Playlists().AddTracks(
+		ctx,
+		kind,
+		playlist.Result.Revision,
+		tracks,
+		&yamusic.PlaylistsAddTracksOptions{At: playlist.Result.TrackCount},
+	)
+	if err != nil {
+		return err
+	}
+	if err := yamusic.CheckResponse(resp, added.Error); err != nil {
+		return err
+	}
+
+	return a.output(added.Result, nil, [][]string{
+		{"Added tracks:", strconv.Itoa(len(tracks))},
+		{"Revision:", strconv.Itoa(added.Result.Revision)},
+	})
+}
+
+// playlistRemove removes all occurrences of tracks from playlist:
+// playlist remove <kind> <trackID>...
+func playlistRemove(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) < 2 {
+		return errUsage
+	}
+
+	kind, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("parse kind: %w", err)
+	}
+
+	remove := make(map[int]bool, len(args)-1)
+	for _, arg := range args[1:] {
+		track, err := parsePlaylistsTrack(arg)
+		if err != nil {
+			return err
+		}
+		remove[track.ID] = true
+	}
+
+	playlist, err := a.getPlaylist(ctx, 0, kind)
+	if err != nil {
+		return err
+	}
+
+	// Remove from the end so that indexes of remaining tracks don't shift.
+	var indexes []int
+	for i, track := range playlist.Result.Tracks {
+		if remove[track.ID] {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(indexes)))
+
+	if len(indexes) == 0 {
+		return errors.New("tracks are not found in the playlist")
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	result := playlist.Result.PlaylistsResult
+	for _, i := range indexes {
+		track := playlist.Result.Tracks[i].Track
+		playlistsTrack, err := parsePlaylistsTrack(trackID(track))
+		if err != nil {
+			return err
+		}
+
+		removed, resp, err := client.Playlists().RemoveTracks(
+			ctx,
+			kind,
+			result.Revision,
+			[]yamusic.PlaylistsTrack{playlistsTrack},
+			&yamusic.PlaylistsRemoveTracksOptions{From: i, To: i + 1},
+		)
+		if err != nil {
+			return err
+		}
+		if err := yamusic.CheckResponse(resp, removed.Error); err != nil {
+			return err
+		}
+
+		result = removed.Result
+	}
+
+	return a.output(result, nil, [][]string{
+		{"Removed tracks:", strconv.Itoa(len(indexes))},
+		{"Revision:", strconv.Itoa(result.Revision)},
+	})
+}
+
+// playlistExport writes playlist to file:
+// playlist export [--format m3u8|xspf|jspf|csv] [--manifest file] [-o file] <kind>
+func playlistExport(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	userID := flags.Int("user-id", 0, "owner of playlist (default authorized user)")
+	formatName := flags.String("format", "", "m3u8, xspf, jspf or csv (default by extension of output file or m3u8)")
+	manifestPath := flags.String("manifest", "", "JSON file of track IDs to local paths")
+	output := flags.String("o", "", "output file (default stdout)")
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	kind, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("parse kind: %w", err)
+	}
+
+	format := export.Format(*formatName)
+	if format == "" {
+		format = export.FormatM3U8
+		if *output != "" {
+			if format, err = export.FormatFromPath(*output); err != nil {
+				return err
+			}
+		}
+	}
+
+	opts := new(export.Options)
+	if *manifestPath != "" {
+		if opts.Manifest, err = export.LoadManifest(*manifestPath); err != nil {
+			return err
+		}
+	}
+
+	playlist, err := a.getPlaylist(ctx, *userID, kind)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return export.Write(a.stdout, format, export.FromPlaylist(playlist), opts)
+	}
+
+	if opts.Manifest != nil {
+		opts.BaseDir = filepath.Dir(*output)
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+
+	err = export.Write(file, format, export.FromPlaylist(playlist), opts)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// playlistImport creates playlist from file matching its tracks by search:
+// playlist import [--format m3u|xspf|jspf|csv|spotify] [--title title] [--public] [--dry-run] <file>
+func playlistImport(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	formatName := flags.String("format", "", "m3u, xspf, jspf, csv or spotify (default by extension of file)")
+	title := flags.String("title", "", "title of created playlist (default title from file or file name)")
+	public := flags.Bool("public", false, "create public playlist")
+	dryRun := flags.Bool("dry-run", false, "only match tracks without creating playlist")
+	skipLow := flags.Bool("skip-low-confidence", false, "don't add tracks matched with low confidence")
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	format := importer.Format(*formatName)
+	if format == "" {
+		if format, err = importer.FormatFromPath(args[0]); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	playlist, err := importer.Parse(file, format)
+	if err != nil {
+		return err
+	}
+
+	if *title == "" && playlist.Title == "" {
+		name := filepath.Base(args[0])
+		*title = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	report, err := importer.Import(ctx, client, playlist, &importer.Options{
+		Title:             *title,
+		Public:            *public,
+		DryRun:            *dryRun,
+		SkipLowConfidence: *skipLow,
+	})
+	if report != nil {
+		if a.jsonOutput {
+			if outputErr := a.output(report, nil, nil); outputErr != nil && err == nil {
+				err = outputErr
+			}
+		} else if writeErr := report.WriteText(a.stdout); writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
+
+	return err
+}
+
+// getPlaylist returns playlist with tracks.
+func (a *app) getPlaylist(ctx context.Context, userID, kind int) (*yamusic.PlaylistsGetResp, error) {
+	client, err := a.yamusic()
+	if err != nil {
+		return nil, err
+	}
+
+	playlist, resp, err := client.Playlists().Get(ctx, userID, kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := yamusic.CheckResponse(resp, playlist.Error); err != nil {
+		return nil, err
+	}
+
+	return playlist, nil
+}
+
+// parsePlaylistsTrack parses track in "trackID[:albumID]" form.
+func parsePlaylistsTrack(s string) (yamusic.PlaylistsTrack, error) {
+	var track yamusic.PlaylistsTrack
+
+	id, albumID := s, ""
+	if i := strings.Index(s, ":"); i >= 0 {
+		id, albumID = s[:i], s[i+1:]
+	}
+
+	var err error
+	track.ID, err = strconv.Atoi(id)
+	if err != nil {
+		return track, fmt.Errorf("parse track ID %q: %w", s, err)
+	}
+
+	if albumID != "" {
+		track.AlbumID, err = strconv.Atoi(albumID)
+		if err != nil {
+			return track, fmt.Errorf("parse album ID %q: %w", s, err)
+		}
+	}
+
+	return track, nil
+}
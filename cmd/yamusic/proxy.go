@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/ndrewnee/go-yamusic/proxy"
+)
+
+var proxyCommand = &command{
+	name:        "proxy",
+	usage:       "proxy [--addr address] [--cache-dir dir] [--cache-size megabytes] [--no-cache]",
+	description: "Serve tracks by stable URLs like /track/{id} for media players.",
+	run:         serveProxy,
+}
+
+// serveProxy runs streaming proxy until interrupted.
+func serveProxy(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	cacheDir := flags.String("cache-dir", defaultTracksCacheDir(), "directory of cached tracks")
+	cacheSize := flags.Int64("cache-size", proxy.DefaultMaxCacheSize>>20, "size limit of cached tracks in megabytes")
+	noCache := flags.Bool("no-cache", false, "don't cache tracks")
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 0 || *cacheSize <= 0 {
+		return errUsage
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	opts := &proxy.Options{MaxCacheSize: *cacheSize << 20}
+	if !*noCache {
+		opts.CacheDir = *cacheDir
+	}
+	if a.httpClient != nil {
+		opts.HTTPClient = a.httpClient
+	}
+
+	server, err := proxy.NewServer(client, opts)
+	if err != nil {
+		return err
+	}
+
+	return serveHTTP(a, ctx, *addr, server, "Serving tracks on http://%s/track/{id}\n")
+}
+
+// defaultTracksCacheDir returns directory of cached tracks in user's cache
+// directory.
+func defaultTracksCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = "."
+	}
+
+	return filepath.Join(dir, "yamusic", "tracks")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/ndrewnee/go-yamusic/subsonic"
+)
+
+// shutdownTimeout is how long subsonic command waits for active requests
+// to finish on interrupt.
+const shutdownTimeout = 5 * time.Second
+
+var subsonicCommand = &command{
+	name:        "subsonic",
+	usage:       "subsonic [--addr address] --username username --password password",
+	description: "Serve library over Subsonic API for Subsonic clients.",
+	run:         serveSubsonic,
+}
+
+// serveSubsonic runs Subsonic API server until interrupted.
+func serveSubsonic(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	addr := flags.String("addr", ":4040", "address to listen on")
+	username := flags.String("username", "", "username of Subsonic clients")
+	password := flags.String("password", "", "password of Subsonic clients")
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 0 || *username == "" || *password == "" {
+		return errUsage
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	opts := &subsonic.Options{Username: *username, Password: *password}
+	if a.httpClient != nil {
+		opts.HTTPClient = a.httpClient
+	}
+
+	return serveHTTP(a, ctx, *addr, subsonic.NewServer(client, opts), "Serving Subsonic API on http://%s/rest/\n")
+}
+
+// serveHTTP serves handler on addr until interrupted. Banner is printed
+// to stderr with the listener address once the server is ready.
+func serveHTTP(a *app, ctx context.Context, addr string, handler http.Handler, banner string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	server := &http.Server{Handler: handler}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(listener)
+	}()
+
+	fmt.Fprintf(a.stderr, banner, listener.Addr())
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+
+	"github.com/ndrewnee/go-yamusic/dirsync"
+)
+
+var syncCommand = &command{
+	name:        "sync",
+	usage:       "sync [--title title] [--public] [--dry-run] [--delete-removed] <dir>",
+	description: "Sync directory of audio files with a playlist.",
+	run:         syncDir,
+}
+
+// syncDir syncs directory with playlist in both directions.
+func syncDir(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	title := flags.String("title", "", "title of playlist created on the first sync (default directory name)")
+	public := flags.Bool("public", false, "create public playlist")
+	dryRun := flags.Bool("dry-run", false, "only print changes without making them")
+	deleteRemoved := flags.Bool("delete-removed", false, "delete files of tracks removed from playlist instead of adding them again")
+	skipLow := flags.Bool("skip-low-confidence", false, "don't sync files matched with low confidence")
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	opts := &dirsync.Options{
+		Title:             *title,
+		Public:            *public,
+		DryRun:            *dryRun,
+		DeleteRemoved:     *deleteRemoved,
+		SkipLowConfidence: *skipLow,
+	}
+	if a.httpClient != nil {
+		opts.HTTPClient = a.httpClient
+	}
+
+	report, err := dirsync.Sync(ctx, client, args[0], opts)
+	if report != nil {
+		if a.jsonOutput {
+			if outputErr := a.output(report, nil, nil); outputErr != nil && err == nil {
+				err = outputErr
+			}
+		} else if writeErr := report.WriteText(a.stdout); writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
+
+	return err
+}
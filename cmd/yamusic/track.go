@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+var trackCommand = &command{
+	name:        "track",
+	usage:       "track info|url|download [-o file] <trackID[:albumID]>",
+	description: "Show track info or download the track.",
+	subcommands: []string{"info", "url", "download"},
+	run: func(a *app, ctx context.Context, args []string) error {
+		if len(args) == 0 {
+			return errUsage
+		}
+
+		subcommands := map[string]func(*app, context.Context, []string) error{
+			"info":     trackInfo,
+			"url":      trackURL,
+			"download": trackDownload,
+		}
+
+		subcommand, ok := subcommands[args[0]]
+		if !ok {
+			return errUsage
+		}
+
+		return subcommand(a, ctx, args[1:])
+	},
+}
+
+// trackInfo prints track with its album: track info <id>
+func trackInfo(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseTrackArgs(args)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	info, err := client.Tracks().FullInfo(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{
+		{"ID:", trackID(info.Track)},
+		{"Title:", info.Track.Title},
+		{"Artists:", info.Track.ArtistNames()},
+		{"Duration:", formatDuration(info.Track.DurationMs)},
+	}
+	if info.Album != nil {
+		rows = append(rows,
+			[]string{"Album:", info.Album.Title},
+			[]string{"Year:", strconv.Itoa(info.Album.Year)},
+			[]string{"Genre:", info.Album.Genre},
+		)
+	}
+	rows = append(rows, []string{"Similar:", strconv.Itoa(len(info.Similar))})
+
+	return a.output(info, nil, rows)
+}
+
+// trackURL prints download URL of the track: track url <id>
+func trackURL(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseTrackArgs(args)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	downloadURL, err := client.Tracks().GetDownloadURL(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return a.output(map[string]string{"url": downloadURL}, nil, [][]string{{downloadURL}})
+}
+
+// trackDownload saves track to file: track download [-o file] <id>
+func trackDownload(a *app, ctx context.Context, args []string) error {
+	flags := a.newFlagSet()
+	output := flags.String("o", "", "output file, \"-\" for stdout (default <id>.mp3)")
+	args, err := a.parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseTrackArgs(args)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.yamusic()
+	if err != nil {
+		return err
+	}
+
+	downloadURL, err := client.Tracks().GetDownloadURL(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return err
+	}
+
+	var doer yamusic.Doer = http.DefaultClient
+	if a.httpClient != nil {
+		doer = a.httpClient
+	}
+
+	resp, err := doer.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download: %s", resp.Status)
+	}
+
+	path := *output
+	if path == "" {
+		path = strconv.Itoa(id) + ".mp3"
+	}
+
+	if path == "-" {
+		_, err = io.Copy(a.stdout, resp.Body)
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	n, err := io.Copy(file, resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stderr, "Saved %d bytes to %s\n", n, path)
+	return nil
+}
+
+// parseTrackArgs parses the only argument as track ID.
+func parseTrackArgs(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, errUsage
+	}
+
+	track, err := parsePlaylistsTrack(args[0])
+	if err != nil {
+		return 0, err
+	}
+
+	return track.ID, nil
+}
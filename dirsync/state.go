@@ -0,0 +1,94 @@
+package dirsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// StateFileName is a name of file in synced directory where state of sync
+// is saved.
+const StateFileName = ".yamusic-sync.json"
+
+// stateVersion is a version of state file format.
+const stateVersion = 1
+
+type (
+	// State is saved between syncs to avoid matching unchanged files again
+	// and to tell tracks added on one side from tracks removed on another.
+	State struct {
+		Version int `json:"version"`
+		// Kind is a kind of synced playlist.
+		Kind int `json:"kind,omitempty"`
+		// Files are matched files by path relative to the directory
+		// with slash separators.
+		Files map[string]FileState `json:"files"`
+		// Synced are IDs of tracks that were both in the directory and
+		// in the playlist after the last sync.
+		Synced []int `json:"synced"`
+	}
+
+	// FileState is a result of matching a file.
+	FileState struct {
+		Size    int64     `json:"size"`
+		ModTime time.Time `json:"modTime"`
+		// TrackID is zero if the file wasn't matched.
+		TrackID int `json:"trackId,omitempty"`
+		AlbumID int `json:"albumId,omitempty"`
+	}
+)
+
+// LoadState reads state of sync from the directory. It returns empty state
+// if the directory wasn't synced yet.
+func LoadState(dir string) (*State, error) {
+	state := &State{Version: stateVersion, Files: map[string]FileState{}}
+
+	b, err := os.ReadFile(filepath.Join(dir, StateFileName))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, state); err != nil {
+		return nil, fmt.Errorf("dirsync: decode state: %w", err)
+	}
+
+	if state.Version != stateVersion {
+		return nil, fmt.Errorf("dirsync: unsupported state version %d", state.Version)
+	}
+
+	if state.Files == nil {
+		state.Files = map[string]FileState{}
+	}
+
+	return state, nil
+}
+
+// Save writes state of sync to the directory.
+func (s *State) Save(dir string) error {
+	sort.Ints(s.Synced)
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to temporary file first so that interrupted save doesn't lose
+	// the previous state.
+	path := filepath.Join(dir, StateFileName)
+	if err := os.WriteFile(path+".tmp", b, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// unchanged reports whether the file is the same as when it was matched.
+func (f FileState) unchanged(info os.FileInfo) bool {
+	return f.Size == info.Size() && f.ModTime.Equal(info.ModTime())
+}
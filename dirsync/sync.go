@@ -0,0 +1,629 @@
+// Package dirsync keeps a playlist in sync with a directory of audio files.
+//
+// Files are matched to Yandex.Music tracks by their tags, or by file names
+// if they have no tags, using fuzzy search of the importer package. Tracks
+// of new files are added to the playlist and tracks of deleted files are
+// removed from it. Tracks added to the playlist are downloaded into the
+// directory. State of the sync is saved in the directory so that unchanged
+// files are not matched again.
+package dirsync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ndrewnee/go-yamusic/importer"
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// addTracksBatch is a maximum number of tracks added in one request.
+const addTracksBatch = 100
+
+// audioExtensions are extensions of synced files.
+var audioExtensions = map[string]bool{
+	".mp3":  true,
+	".flac": true,
+	".m4a":  true,
+	".ogg":  true,
+	".opus": true,
+}
+
+type (
+	// Options are options of Sync.
+	Options struct {
+		// Title is a title of playlist created on the first sync.
+		// Defaults to the directory name.
+		Title string
+		// Public makes created playlist public.
+		Public bool
+		// DryRun only reports changes without making them.
+		DryRun bool
+		// DeleteRemoved deletes files of tracks removed from the playlist.
+		// Otherwise such tracks are added to the playlist again.
+		DeleteRemoved bool
+		// SkipLowConfidence doesn't sync files matched with low confidence.
+		SkipLowConfidence bool
+		// Match are options of track matching.
+		Match *importer.MatchOptions
+		// HTTPClient downloads tracks. Defaults to http.DefaultClient.
+		HTTPClient yamusic.Doer
+		// DownloadURL returns download URL of the track.
+		// Defaults to TracksService.GetDownloadURL.
+		DownloadURL func(ctx context.Context, trackID int) (string, error)
+	}
+
+	// Report describes changes made by Sync.
+	Report struct {
+		// Playlist is the synced playlist. It's nil in dry-run mode if the
+		// playlist doesn't exist yet.
+		Playlist *yamusic.PlaylistsResult `json:"playlist"`
+		// Added are tracks of new files added to the playlist.
+		Added []Track `json:"added"`
+		// Removed are tracks of deleted files removed from the playlist.
+		Removed []Track `json:"removed"`
+		// Downloaded are tracks added to the playlist saved to the directory.
+		Downloaded []Track `json:"downloaded"`
+		// Deleted are files of tracks removed from the playlist.
+		Deleted []Track `json:"deleted"`
+		// Unmatched are paths of files which tracks weren't found.
+		Unmatched []string `json:"unmatched"`
+	}
+
+	// Track is a synced track.
+	Track struct {
+		ID      int `json:"id"`
+		AlbumID int `json:"albumId"`
+		// Path is a file path relative to the directory.
+		Path string `json:"path,omitempty"`
+	}
+)
+
+// Sync syncs the directory with the playlist saved in its state or, on the
+// first sync, with the playlist titled opts.Title which is created if
+// needed.
+//
+// On error Sync saves state of changes made so far and returns report of
+// them.
+func Sync(
+	ctx context.Context,
+	client *yamusic.Client,
+	dir string,
+	opts *Options,
+) (*Report, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	o := *opts
+	if o.Title == "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+		o.Title = filepath.Base(abs)
+	}
+	if o.HTTPClient == nil {
+		o.HTTPClient = http.DefaultClient
+	}
+	if o.DownloadURL == nil {
+		o.DownloadURL = func(ctx context.Context, trackID int) (string, error) {
+			return client.Tracks().GetDownloadURL(ctx, trackID)
+		}
+	}
+
+	state, err := LoadState(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &syncer{
+		client: client,
+		dir:    dir,
+		opts:   o,
+		state:  state,
+		report: &Report{},
+	}
+
+	err = s.sync(ctx)
+	if !o.DryRun {
+		if saveErr := s.state.Save(dir); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}
+
+	return s.report, err
+}
+
+type syncer struct {
+	client *yamusic.Client
+	dir    string
+	opts   Options
+	state  *State
+	report *Report
+	// playlist is the synced playlist with its tracks.
+	playlist *yamusic.PlaylistsGetResp
+	// local are paths of files by track ID.
+	local map[int][]string
+	// localOrder are IDs of local tracks in order of file paths.
+	localOrder []int
+	// remote are playlist tracks by ID.
+	remote map[int]yamusic.Track
+	// remoteOrder are IDs of playlist tracks in order of the playlist.
+	remoteOrder []int
+}
+
+func (s *syncer) sync(ctx context.Context) error {
+	if err := s.matchFiles(ctx); err != nil {
+		return err
+	}
+
+	if err := s.loadPlaylist(ctx); err != nil {
+		return err
+	}
+
+	synced := make(map[int]bool, len(s.state.Synced))
+	for _, id := range s.state.Synced {
+		synced[id] = true
+	}
+
+	var add, remove, download, del []Track
+	for _, id := range s.localOrder {
+		if _, ok := s.remote[id]; ok {
+			continue
+		}
+
+		track := Track{ID: id, AlbumID: s.state.Files[s.local[id][0]].AlbumID, Path: s.local[id][0]}
+		if synced[id] && s.opts.DeleteRemoved {
+			del = append(del, track)
+		} else {
+			add = append(add, track)
+		}
+	}
+
+	for _, id := range s.remoteOrder {
+		if _, ok := s.local[id]; ok {
+			continue
+		}
+
+		track := Track{ID: id, AlbumID: albumID(s.remote[id])}
+		if synced[id] {
+			remove = append(remove, track)
+		} else {
+			download = append(download, track)
+		}
+	}
+
+	if s.opts.DryRun {
+		s.report.Added = add
+		s.report.Removed = remove
+		s.report.Downloaded = download
+		s.report.Deleted = del
+		return nil
+	}
+
+	defer s.updateSynced()
+
+	if err := s.ensurePlaylist(ctx); err != nil {
+		return err
+	}
+
+	if err := s.removeTracks(ctx, remove); err != nil {
+		return err
+	}
+
+	if err := s.addTracks(ctx, add); err != nil {
+		return err
+	}
+
+	for _, track := range download {
+		if err := s.download(ctx, track); err != nil {
+			return err
+		}
+	}
+
+	for _, track := range del {
+		for _, path := range s.local[track.ID] {
+			if err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(path))); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			delete(s.state.Files, path)
+			s.report.Deleted = append(s.report.Deleted, Track{ID: track.ID, AlbumID: track.AlbumID, Path: path})
+		}
+		delete(s.local, track.ID)
+	}
+
+	return nil
+}
+
+// matchFiles scans the directory and matches new and changed files.
+func (s *syncer) matchFiles(ctx context.Context) error {
+	files := make(map[string]FileState)
+	var (
+		paths   []string
+		entries []importer.Entry
+	)
+
+	err := filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !audioExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if file, ok := s.state.Files[rel]; ok && file.unchanged(info) {
+			files[rel] = file
+			return nil
+		}
+
+		tags, err := ReadTags(path)
+		if err != nil || tags.Title == "" {
+			tags = tagsFromFileName(path)
+		}
+
+		files[rel] = FileState{Size: info.Size(), ModTime: info.ModTime()}
+		paths = append(paths, rel)
+		entries = append(entries, importer.Entry{
+			Position: len(entries) + 1,
+			Title:    tags.Title,
+			Artists:  tags.Artists,
+			Album:    tags.Album,
+			Duration: tags.Duration,
+			Location: rel,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	matches := importer.NewMatcher(s.client, s.opts.Match).MatchAll(ctx, entries)
+	for i, match := range matches {
+		if match.Err != nil {
+			// Match the file again next time.
+			delete(files, paths[i])
+			continue
+		}
+
+		if match.Status == importer.MatchStatusMatched ||
+			match.Status == importer.MatchStatusLowConfidence && !s.opts.SkipLowConfidence {
+			file := files[paths[i]]
+			file.TrackID = match.Best.TrackID
+			file.AlbumID = match.Best.AlbumID
+			files[paths[i]] = file
+		}
+	}
+
+	for i, match := range matches {
+		if match.Err != nil {
+			s.report.Unmatched = append(s.report.Unmatched, paths[i])
+		}
+	}
+
+	s.state.Files = files
+	s.local = make(map[int][]string)
+
+	sorted := make([]string, 0, len(files))
+	for path := range files {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+
+	for _, path := range sorted {
+		file := files[path]
+		if file.TrackID == 0 {
+			s.report.Unmatched = append(s.report.Unmatched, path)
+			continue
+		}
+
+		if _, ok := s.local[file.TrackID]; !ok {
+			s.localOrder = append(s.localOrder, file.TrackID)
+		}
+		s.local[file.TrackID] = append(s.local[file.TrackID], path)
+	}
+
+	sort.Strings(s.report.Unmatched)
+	return nil
+}
+
+// loadPlaylist gets the synced playlist and its tracks. On the first sync
+// it looks for playlist by title.
+func (s *syncer) loadPlaylist(ctx context.Context) error {
+	s.remote = make(map[int]yamusic.Track)
+
+	if s.state.Kind == 0 {
+		list, resp, err := s.client.Playlists().List(ctx, 0)
+		if err == nil {
+			err = yamusic.CheckResponse(resp, list.Error)
+		}
+		if err != nil {
+			return fmt.Errorf("dirsync: list playlists: %w", err)
+		}
+
+		for _, playlist := range list.Result {
+			if playlist.Title == s.opts.Title {
+				s.state.Kind = playlist.Kind
+				break
+			}
+		}
+
+		if s.state.Kind == 0 {
+			return nil
+		}
+	}
+
+	playlist, resp, err := s.client.Playlists().Get(ctx, 0, s.state.Kind)
+	if err == nil {
+		err = yamusic.CheckResponse(resp, playlist.Error)
+	}
+	if err != nil {
+		return fmt.Errorf("dirsync: get playlist %d: %w", s.state.Kind, err)
+	}
+
+	s.playlist = playlist
+	result := playlist.Result.PlaylistsResult
+	s.report.Playlist = &result
+
+	for _, track := range playlist.Result.Tracks {
+		id, err := strconv.Atoi(track.Track.ID)
+		if err != nil {
+			continue
+		}
+
+		if _, ok := s.remote[id]; !ok {
+			s.remoteOrder = append(s.remoteOrder, id)
+		}
+		s.remote[id] = track.Track
+	}
+
+	return nil
+}
+
+// ensurePlaylist creates playlist if it doesn't exist yet.
+func (s *syncer) ensurePlaylist(ctx context.Context) error {
+	if s.playlist != nil {
+		return nil
+	}
+
+	created, resp, err := s.client.Playlists().Create(ctx, s.opts.Title, s.opts.Public)
+	if err == nil {
+		err = yamusic.CheckResponse(resp, created.Error)
+	}
+	if err != nil {
+		return fmt.Errorf("dirsync: create playlist %q: %w", s.opts.Title, err)
+	}
+
+	s.state.Kind = created.Result.Kind
+	s.playlist = &yamusic.PlaylistsGetResp{}
+	s.playlist.Result.PlaylistsResult = created.Result
+	result := created.Result
+	s.report.Playlist = &result
+
+	return nil
+}
+
+// removeTracks removes all occurrences of tracks from the playlist.
+func (s *syncer) removeTracks(ctx context.Context, tracks []Track) error {
+	if len(tracks) == 0 {
+		return nil
+	}
+
+	remove := make(map[string]bool, len(tracks))
+	for _, track := range tracks {
+		remove[strconv.Itoa(track.ID)] = true
+	}
+
+	// Remove from the end so that indexes of remaining tracks don't shift.
+	var indexes []int
+	for i, track := range s.playlist.Result.Tracks {
+		if remove[track.Track.ID] {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(indexes)))
+
+	for _, i := range indexes {
+		track := s.playlist.Result.Tracks[i].Track
+		id, _ := strconv.Atoi(track.ID)
+		removed, resp, err := s.client.Playlists().RemoveTracks(
+			ctx,
+			s.state.Kind,
+			s.report.Playlist.Revision,
+			[]yamusic.PlaylistsTrack{{ID: id, AlbumID: albumID(track)}},
+			&yamusic.PlaylistsRemoveTracksOptions{From: i, To: i + 1},
+		)
+		if err == nil {
+			err = yamusic.CheckResponse(resp, removed.Error)
+		}
+		if err != nil {
+			return fmt.Errorf("dirsync: remove track %d from playlist: %w", id, err)
+		}
+
+		result := removed.Result
+		s.report.Playlist = &result
+		delete(s.remote, id)
+	}
+
+	s.report.Removed = tracks
+	return nil
+}
+
+// addTracks adds tracks to the end of the playlist.
+func (s *syncer) addTracks(ctx context.Context, tracks []Track) error {
+	for start := 0; start < len(tracks); start += addTracksBatch {
+		end := start + addTracksBatch
+		if end > len(tracks) {
+			end = len(tracks)
+		}
+
+		batch := make([]yamusic.PlaylistsTrack, 0, end-start)
+		for _, track := range tracks[start:end] {
+			batch = append(batch, yamusic.PlaylistsTrack{ID: track.ID, AlbumID: track.AlbumID})
+		}
+
+		added, resp, err := s.client.Playlists().AddTracks(
+			ctx,
+			s.state.Kind,
+			s.report.Playlist.Revision,
+			batch,
+			&yamusic.PlaylistsAddTracksOptions{At: s.report.Playlist.TrackCount},
+		)
+		if err == nil {
+			err = yamusic.CheckResponse(resp, added.Error)
+		}
+		if err != nil {
+			return fmt.Errorf("dirsync: add tracks to playlist: %w", err)
+		}
+
+		result := added.Result
+		s.report.Playlist = &result
+		for _, track := range tracks[start:end] {
+			s.remote[track.ID] = yamusic.Track{ID: strconv.Itoa(track.ID)}
+			s.report.Added = append(s.report.Added, track)
+		}
+	}
+
+	return nil
+}
+
+// download saves track of the playlist to the directory.
+func (s *syncer) download(ctx context.Context, track Track) error {
+	downloadURL, err := s.opts.DownloadURL(ctx, track.ID)
+	if err != nil {
+		return fmt.Errorf("dirsync: get download url of track %d: %w", track.ID, err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.opts.HTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("dirsync: download track %d: %w", track.ID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("dirsync: download track %d: %s", track.ID, resp.Status)
+	}
+
+	// Download to temporary file without audio extension so that partially
+	// downloaded file is never synced.
+	tmp, err := os.CreateTemp(s.dir, ".yamusic-download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("dirsync: download track %d: %w", track.ID, err)
+	}
+
+	name := s.fileName(s.remote[track.ID])
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, name)); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(filepath.Join(s.dir, name))
+	if err != nil {
+		return err
+	}
+
+	s.state.Files[name] = FileState{
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		TrackID: track.ID,
+		AlbumID: track.AlbumID,
+	}
+	s.local[track.ID] = []string{name}
+
+	track.Path = name
+	s.report.Downloaded = append(s.report.Downloaded, track)
+
+	return nil
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[<>:"/\\|?*\x00-\x1f]+`)
+
+// fileName returns unused "Artist - Title.mp3" file name for the track.
+func (s *syncer) fileName(track yamusic.Track) string {
+	name := track.Title
+	if artists := track.ArtistNames(); artists != "" {
+		name = artists + " - " + name
+	}
+
+	name = strings.TrimSpace(unsafeFileNameChars.ReplaceAllString(name, "_"))
+	if name == "" {
+		name = track.ID
+	}
+
+	if _, err := os.Stat(filepath.Join(s.dir, name+".mp3")); err == nil {
+		name += " (" + track.ID + ")"
+	}
+
+	return name + ".mp3"
+}
+
+// updateSynced saves tracks that are both in the directory and in the
+// playlist.
+func (s *syncer) updateSynced() {
+	s.state.Synced = s.state.Synced[:0]
+	for id := range s.local {
+		if _, ok := s.remote[id]; ok {
+			s.state.Synced = append(s.state.Synced, id)
+		}
+	}
+}
+
+// WriteText writes human-readable summary of changes.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	if r.Playlist != nil {
+		fmt.Fprintf(tw, "Playlist:\t%s (kind %d)\n", r.Playlist.Title, r.Playlist.Kind)
+	}
+	fmt.Fprintf(tw, "Added to playlist:\t%d\n", len(r.Added))
+	fmt.Fprintf(tw, "Removed from playlist:\t%d\n", len(r.Removed))
+	fmt.Fprintf(tw, "Downloaded:\t%d\n", len(r.Downloaded))
+	fmt.Fprintf(tw, "Deleted files:\t%d\n", len(r.Deleted))
+	fmt.Fprintf(tw, "Unmatched files:\t%d\n", len(r.Unmatched))
+
+	if len(r.Unmatched) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "UNMATCHED")
+		for _, path := range r.Unmatched {
+			fmt.Fprintln(tw, path)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func albumID(track yamusic.Track) int {
+	if len(track.Albums) == 0 {
+		return 0
+	}
+
+	return track.Albums[0].ID
+}
@@ -0,0 +1,230 @@
+package dirsync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setup returns mux of test server, client configured to use it and
+// options downloading tracks from "/download/{id}" of the server.
+func setup(t *testing.T) (*http.ServeMux, *yamusic.Client, *Options) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/download/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "audio of track "+strings.TrimPrefix(r.URL.Path, "/download/"))
+	})
+
+	opts := &Options{
+		Title: "Music",
+		DownloadURL: func(ctx context.Context, trackID int) (string, error) {
+			return fmt.Sprintf("%s/download/%d", server.URL, trackID), nil
+		},
+	}
+
+	baseURL, _ := url.Parse(server.URL + "/")
+	client := yamusic.NewClient(yamusic.BaseURL(baseURL), yamusic.AccessToken(2000, "accessToken"))
+	return mux, client, opts
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func TestSync(t *testing.T) {
+	mux, client, opts := setup(t)
+	dir := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, "Beatles", "01.mp3"), id3v2(3, "TIT2", "Yesterday", "TPE1", "The Beatles", "TLEN", "125000"))
+	writeFile(t, filepath.Join(dir, "unknown.mp3"), []byte("not tagged"))
+	writeFile(t, filepath.Join(dir, "cover.jpg"), []byte("not audio"))
+
+	var (
+		mu       sync.Mutex
+		searches int
+	)
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		searches++
+		mu.Unlock()
+
+		text := r.URL.Query().Get("text")
+		if strings.Contains(text, "Yesterday") {
+			fmt.Fprint(w, `{"result":{"tracks":{"results":[
+				{"id":2,"title":"Yesterday","durationMs":125000,"artists":[{"name":"The Beatles"}],"albums":[{"id":20}]}
+			]}}}`)
+			return
+		}
+		fmt.Fprint(w, `{"result":{"tracks":{"results":[]}}}`)
+	})
+
+	mux.HandleFunc("/users/2000/playlists/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[{"kind":4,"title":"Other"},{"kind":5,"title":"Music","revision":1,"trackCount":1}]}`)
+	})
+
+	playlistTracks := `{"track":{"id":"3","title":"Help!","artists":[{"name":"The Beatles"}],"albums":[{"id":30}]}}`
+	mux.HandleFunc("/users/2000/playlists/5", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"result":{"kind":5,"title":"Music","revision":1,"trackCount":1,"tracks":[%s]}}`, playlistTracks)
+	})
+
+	var diffs []string
+	mux.HandleFunc("/users/2000/playlists/5/change-relative", func(w http.ResponseWriter, r *http.Request) {
+		diffs = append(diffs, r.FormValue("diff"))
+		fmt.Fprint(w, `{"result":{"kind":5,"title":"Music","revision":2,"trackCount":2}}`)
+	})
+
+	report, err := Sync(context.Background(), client, dir, opts)
+	require.NoError(t, err)
+
+	require.NotNil(t, report.Playlist)
+	assert.Equal(t, 5, report.Playlist.Kind)
+	assert.Equal(t, []Track{{ID: 2, AlbumID: 20, Path: "Beatles/01.mp3"}}, report.Added)
+	assert.Equal(t, []Track{{ID: 3, AlbumID: 30, Path: "The Beatles - Help!.mp3"}}, report.Downloaded)
+	assert.Empty(t, report.Removed)
+	assert.Empty(t, report.Deleted)
+	assert.Equal(t, []string{"unknown.mp3"}, report.Unmatched)
+	require.Len(t, diffs, 1)
+	assert.JSONEq(t, `[{"op":"insert","at":1,"tracks":[{"id":2,"albumId":20}]}]`, diffs[0])
+
+	data, err := os.ReadFile(filepath.Join(dir, "The Beatles - Help!.mp3"))
+	require.NoError(t, err)
+	assert.Equal(t, "audio of track 3", string(data))
+
+	state, err := LoadState(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 5, state.Kind)
+	assert.Equal(t, []int{2, 3}, state.Synced)
+	assert.Len(t, state.Files, 3)
+	assert.Equal(t, 0, state.Files["unknown.mp3"].TrackID)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteText(&buf))
+	assert.Contains(t, buf.String(), "Playlist:               Music (kind 5)")
+	assert.Contains(t, buf.String(), "Downloaded:             1")
+
+	// Deleted file is removed from the playlist and unchanged files are not
+	// matched again.
+	searchCount := searches
+	require.NoError(t, os.Remove(filepath.Join(dir, "Beatles", "01.mp3")))
+	playlistTracks += `,{"track":{"id":"2","albums":[{"id":20}]}}`
+	diffs = nil
+
+	report, err = Sync(context.Background(), client, dir, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, searchCount, searches)
+	assert.Equal(t, []Track{{ID: 2, AlbumID: 20}}, report.Removed)
+	assert.Empty(t, report.Added)
+	assert.Empty(t, report.Downloaded)
+	require.Len(t, diffs, 1)
+	assert.JSONEq(t, `[{"op":"delete","from":1,"to":2,"tracks":[{"id":2,"albumId":20}]}]`, diffs[0])
+
+	state, err = LoadState(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, state.Synced)
+}
+
+func TestSync_RemovedFromPlaylist(t *testing.T) {
+	mux, client, opts := setup(t)
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "yesterday.mp3")
+	writeFile(t, path, []byte("audio"))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+
+	state := &State{
+		Version: stateVersion,
+		Kind:    5,
+		Files: map[string]FileState{
+			"yesterday.mp3": {Size: info.Size(), ModTime: info.ModTime(), TrackID: 2, AlbumID: 20},
+		},
+		Synced: []int{2},
+	}
+	require.NoError(t, state.Save(dir))
+
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("matched file must not be matched again")
+	})
+	mux.HandleFunc("/users/2000/playlists/5", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"kind":5,"title":"Music","revision":3,"tracks":[]}}`)
+	})
+
+	// By default track is added to the playlist again.
+	dryRun := *opts
+	dryRun.DryRun = true
+	report, err := Sync(context.Background(), client, dir, &dryRun)
+	require.NoError(t, err)
+	assert.Equal(t, []Track{{ID: 2, AlbumID: 20, Path: "yesterday.mp3"}}, report.Added)
+	assert.Empty(t, report.Deleted)
+
+	deleteRemoved := *opts
+	deleteRemoved.DeleteRemoved = true
+	report, err = Sync(context.Background(), client, dir, &deleteRemoved)
+	require.NoError(t, err)
+	assert.Empty(t, report.Added)
+	assert.Equal(t, []Track{{ID: 2, AlbumID: 20, Path: "yesterday.mp3"}}, report.Deleted)
+
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	state, err = LoadState(dir)
+	require.NoError(t, err)
+	assert.Empty(t, state.Files)
+	assert.Empty(t, state.Synced)
+}
+
+func TestSync_DryRun(t *testing.T) {
+	mux, client, opts := setup(t)
+	dir := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, "The Beatles - Yesterday.mp3"), []byte("audio"))
+
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"tracks":{"results":[
+			{"id":2,"title":"Yesterday","artists":[{"name":"The Beatles"}],"albums":[{"id":20}]}
+		]}}}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[]}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/create", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("playlist must not be created in dry-run mode")
+	})
+
+	opts.DryRun = true
+	report, err := Sync(context.Background(), client, dir, opts)
+	require.NoError(t, err)
+
+	assert.Nil(t, report.Playlist)
+	assert.Equal(t, []Track{{ID: 2, AlbumID: 20, Path: "The Beatles - Yesterday.mp3"}}, report.Added)
+
+	_, err = os.Stat(filepath.Join(dir, StateFileName))
+	assert.True(t, os.IsNotExist(err), "state must not be saved in dry-run mode")
+}
+
+func TestLoadState_Unsupported(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, StateFileName), []byte(`{"version":2}`))
+
+	_, err := LoadState(dir)
+	assert.EqualError(t, err, "dirsync: unsupported state version 2")
+}
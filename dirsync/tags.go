@@ -0,0 +1,376 @@
+package dirsync
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Tags are metadata of an audio file.
+type Tags struct {
+	Title   string
+	Artists []string
+	Album   string
+	// Duration is zero if the file doesn't have it in tags.
+	Duration time.Duration
+}
+
+var errInvalidTag = errors.New("dirsync: invalid tag")
+
+// ReadTags reads ID3 tags of MP3 files or Vorbis comments of FLAC files.
+// It returns empty tags for files of other formats or without tags.
+func ReadTags(path string) (*Tags, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(file, magic); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return &Tags{}, nil
+		}
+		return nil, err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case string(magic) == "fLaC":
+		return readFLAC(file)
+	case string(magic[:3]) == "ID3":
+		return readID3v2(file)
+	default:
+		return readID3v1(file)
+	}
+}
+
+// readID3v2 reads ID3v2.2, ID3v2.3 or ID3v2.4 tag at the start of file.
+func readID3v2(r io.Reader) (*Tags, error) {
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	version, flags := header[3], header[5]
+	if version < 2 || version > 4 {
+		return &Tags{}, nil
+	}
+
+	tag := make([]byte, syncsafe(header[6:10]))
+	if _, err := io.ReadFull(r, tag); err != nil {
+		return nil, errInvalidTag
+	}
+
+	// Whole tag unsynchronisation is used only by ID3v2.2 and ID3v2.3,
+	// ID3v2.4 sets it per frame.
+	if flags&0x80 != 0 && version < 4 {
+		tag = bytes.Replace(tag, []byte{0xff, 0x00}, []byte{0xff}, -1)
+	}
+
+	if flags&0x40 != 0 && version > 2 {
+		if len(tag) < 4 {
+			return nil, errInvalidTag
+		}
+		size := int(binary.BigEndian.Uint32(tag)) + 4
+		if version == 4 {
+			size = syncsafe(tag[:4])
+		}
+		if size > len(tag) {
+			return nil, errInvalidTag
+		}
+		tag = tag[size:]
+	}
+
+	idLen, headerLen := 4, 10
+	if version == 2 {
+		idLen, headerLen = 3, 6
+	}
+
+	tags := new(Tags)
+	for len(tag) >= headerLen && tag[0] != 0 {
+		id := string(tag[:idLen])
+
+		var size int
+		switch version {
+		case 2:
+			size = int(tag[3])<<16 | int(tag[4])<<8 | int(tag[5])
+		case 3:
+			size = int(binary.BigEndian.Uint32(tag[4:8]))
+		default:
+			size = syncsafe(tag[4:8])
+		}
+
+		if size > len(tag)-headerLen {
+			return nil, errInvalidTag
+		}
+
+		frame := tag[headerLen : headerLen+size]
+		tag = tag[headerLen+size:]
+
+		switch id {
+		case "TIT2", "TT2":
+			tags.Title = firstValue(decodeText(frame))
+		case "TPE1", "TP1":
+			tags.Artists = splitArtists(decodeText(frame))
+		case "TALB", "TAL":
+			tags.Album = firstValue(decodeText(frame))
+		case "TLEN", "TLE":
+			if ms, err := strconv.Atoi(firstValue(decodeText(frame))); err == nil {
+				tags.Duration = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+
+	return tags, nil
+}
+
+// readID3v1 reads ID3v1 tag at the end of file.
+func readID3v1(r io.ReadSeeker) (*Tags, error) {
+	if _, err := r.Seek(-128, io.SeekEnd); err != nil {
+		// File is smaller than the tag.
+		return &Tags{}, nil
+	}
+
+	tag := make([]byte, 128)
+	if _, err := io.ReadFull(r, tag); err != nil {
+		return nil, err
+	}
+
+	if string(tag[:3]) != "TAG" {
+		return &Tags{}, nil
+	}
+
+	field := func(b []byte) string {
+		if i := bytes.IndexByte(b, 0); i >= 0 {
+			b = b[:i]
+		}
+		return strings.TrimSpace(decodeLatin1(b))
+	}
+
+	tags := &Tags{
+		Title: field(tag[3:33]),
+		Album: field(tag[63:93]),
+	}
+	if artist := field(tag[33:63]); artist != "" {
+		tags.Artists = []string{artist}
+	}
+
+	return tags, nil
+}
+
+// readFLAC reads Vorbis comments and duration of FLAC file.
+func readFLAC(r io.Reader) (*Tags, error) {
+	if _, err := io.ReadFull(r, make([]byte, 4)); err != nil {
+		return nil, err
+	}
+
+	tags := new(Tags)
+	for {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, errInvalidTag
+		}
+
+		last, blockType := header[0]&0x80 != 0, header[0]&0x7f
+		block := make([]byte, int(header[1])<<16|int(header[2])<<8|int(header[3]))
+		if _, err := io.ReadFull(r, block); err != nil {
+			return nil, errInvalidTag
+		}
+
+		switch blockType {
+		case 0: // STREAMINFO
+			if len(block) < 18 {
+				return nil, errInvalidTag
+			}
+			sampleRate := uint64(block[10])<<12 | uint64(block[11])<<4 | uint64(block[12])>>4
+			samples := uint64(block[13]&0x0f)<<32 | uint64(binary.BigEndian.Uint32(block[14:18]))
+			if sampleRate > 0 {
+				tags.Duration = time.Duration(samples * uint64(time.Second) / sampleRate)
+			}
+		case 4: // VORBIS_COMMENT
+			if err := readVorbisComments(block, tags); err != nil {
+				return nil, err
+			}
+		}
+
+		if last {
+			return tags, nil
+		}
+	}
+}
+
+// readVorbisComments reads title, artists and album from Vorbis comments.
+func readVorbisComments(block []byte, tags *Tags) error {
+	next := func() ([]byte, error) {
+		if len(block) < 4 {
+			return nil, errInvalidTag
+		}
+		size := int(binary.LittleEndian.Uint32(block))
+		if size > len(block)-4 {
+			return nil, errInvalidTag
+		}
+		value := block[4 : 4+size]
+		block = block[4+size:]
+		return value, nil
+	}
+
+	// Vendor string.
+	if _, err := next(); err != nil {
+		return err
+	}
+
+	if len(block) < 4 {
+		return errInvalidTag
+	}
+	count := int(binary.LittleEndian.Uint32(block))
+	block = block[4:]
+
+	for i := 0; i < count; i++ {
+		comment, err := next()
+		if err != nil {
+			return err
+		}
+
+		eq := bytes.IndexByte(comment, '=')
+		if eq < 0 {
+			continue
+		}
+
+		value := strings.TrimSpace(string(comment[eq+1:]))
+		switch strings.ToUpper(string(comment[:eq])) {
+		case "TITLE":
+			tags.Title = value
+		case "ARTIST":
+			tags.Artists = append(tags.Artists, splitArtists([]string{value})...)
+		case "ALBUM":
+			tags.Album = value
+		}
+	}
+
+	return nil
+}
+
+// decodeText decodes ID3v2 text frame into values separated by NUL.
+func decodeText(frame []byte) []string {
+	if len(frame) == 0 {
+		return nil
+	}
+
+	var text string
+	switch encoding, data := frame[0], frame[1:]; encoding {
+	case 0:
+		text = decodeLatin1(data)
+	case 1:
+		text = decodeUTF16(data, nil)
+	case 2:
+		text = decodeUTF16(data, binary.BigEndian)
+	default:
+		text = string(data)
+	}
+
+	var values []string
+	for _, value := range strings.Split(text, "\x00") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func decodeLatin1(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+
+	return string(runes)
+}
+
+// decodeUTF16 decodes UTF-16 text. If order is nil, it's detected by BOM
+// of each string, ID3v2.3 allows several strings with own BOMs.
+func decodeUTF16(b []byte, order binary.ByteOrder) string {
+	detect := order == nil
+	if detect {
+		order = binary.LittleEndian
+	}
+
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		unit := order.Uint16(b[i:])
+		if detect && (unit == 0xfeff || unit == 0xfffe) {
+			if unit == 0xfffe {
+				if order == binary.ByteOrder(binary.LittleEndian) {
+					order = binary.BigEndian
+				} else {
+					order = binary.LittleEndian
+				}
+			}
+			continue
+		}
+		units = append(units, unit)
+	}
+
+	return string(utf16.Decode(units))
+}
+
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+var artistsSeparator = regexp.MustCompile(`\s*(?:;|\s+feat\.?\s+|\s+ft\.\s+)\s*`)
+
+// splitArtists splits artists joined with separators into names.
+func splitArtists(values []string) []string {
+	var artists []string
+	for _, value := range values {
+		for _, artist := range artistsSeparator.Split(value, -1) {
+			if artist = strings.TrimSpace(artist); artist != "" {
+				artists = append(artists, artist)
+			}
+		}
+	}
+
+	return artists
+}
+
+var fileNameNumber = regexp.MustCompile(`^\d+\s*(?:[.\-_]\s*)?`)
+
+// tagsFromFileName guesses artist and title from "Artist - Title" file name.
+func tagsFromFileName(path string) *Tags {
+	name := filepath.Base(path)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	name = strings.Replace(name, "_", " ", -1)
+	name = fileNameNumber.ReplaceAllString(name, "")
+
+	tags := new(Tags)
+	if i := strings.Index(name, " - "); i >= 0 {
+		tags.Artists = splitArtists([]string{name[:i]})
+		tags.Title = strings.TrimSpace(name[i+3:])
+	} else {
+		tags.Title = strings.TrimSpace(name)
+	}
+
+	return tags
+}
+
+// syncsafe decodes 28-bit integer stored in 4 bytes with 7 bits each.
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7f)<<21 | int(b[1]&0x7f)<<14 | int(b[2]&0x7f)<<7 | int(b[3]&0x7f)
+}
@@ -0,0 +1,153 @@
+package dirsync
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// id3v2 returns ID3v2 tag of the version with text frames. Frame values
+// are encoded in UTF-8 for ID3v2.4 and in UTF-16 with BOM otherwise.
+func id3v2(version byte, frames ...string) []byte {
+	var body bytes.Buffer
+	for i := 0; i+1 < len(frames); i += 2 {
+		var data []byte
+		if version == 4 {
+			data = append([]byte{3}, frames[i+1]...)
+		} else {
+			data = []byte{1, 0xff, 0xfe}
+			for _, unit := range utf16.Encode([]rune(frames[i+1])) {
+				data = append(data, byte(unit), byte(unit>>8))
+			}
+		}
+
+		body.WriteString(frames[i])
+		size := make([]byte, 4)
+		if version == 4 {
+			size = syncsafeBytes(len(data))
+		} else {
+			binary.BigEndian.PutUint32(size, uint32(len(data)))
+		}
+		body.Write(size)
+		body.Write([]byte{0, 0})
+		body.Write(data)
+	}
+	// Padding.
+	body.Write(make([]byte, 16))
+
+	tag := append([]byte{'I', 'D', '3', version, 0, 0}, syncsafeBytes(body.Len())...)
+	return append(tag, body.Bytes()...)
+}
+
+func syncsafeBytes(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7f), byte(n >> 14 & 0x7f), byte(n >> 7 & 0x7f), byte(n & 0x7f)}
+}
+
+// flac returns FLAC metadata with STREAMINFO and Vorbis comments.
+func flac(seconds int, comments ...string) []byte {
+	streamInfo := make([]byte, 34)
+	// 44100 Hz in 20 bits, then channels and bits per sample.
+	streamInfo[10], streamInfo[11], streamInfo[12] = 0x0a, 0xc4, 0x40
+	binary.BigEndian.PutUint32(streamInfo[14:18], uint32(seconds*44100))
+
+	var vorbis bytes.Buffer
+	writeString := func(s string) {
+		_ = binary.Write(&vorbis, binary.LittleEndian, uint32(len(s)))
+		vorbis.WriteString(s)
+	}
+	writeString("reference libFLAC 1.3.2")
+	_ = binary.Write(&vorbis, binary.LittleEndian, uint32(len(comments)))
+	for _, comment := range comments {
+		writeString(comment)
+	}
+
+	b := []byte("fLaC")
+	b = append(b, 0, 0, 0, byte(len(streamInfo)))
+	b = append(b, streamInfo...)
+	b = append(b, 0x84, byte(vorbis.Len()>>16), byte(vorbis.Len()>>8), byte(vorbis.Len()))
+	return append(b, vorbis.Bytes()...)
+}
+
+// id3v1 returns audio data with ID3v1 tag at the end.
+func id3v1(title, artist, album string) []byte {
+	tag := make([]byte, 128)
+	copy(tag, "TAG")
+	copy(tag[3:33], title)
+	copy(tag[33:63], artist)
+	copy(tag[63:93], album)
+	return append(bytes.Repeat([]byte{0xff, 0xfb}, 100), tag...)
+}
+
+func TestReadTags(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want Tags
+	}{
+		{
+			name: "id3v2.3.mp3",
+			data: id3v2(3, "TIT2", "Восьмиклассница", "TPE1", "Кино", "TALB", "Ночь", "TLEN", "200000"),
+			want: Tags{Title: "Восьмиклассница", Artists: []string{"Кино"}, Album: "Ночь", Duration: 200 * time.Second},
+		},
+		{
+			name: "id3v2.4.mp3",
+			data: id3v2(4, "TIT2", "Under Pressure", "TPE1", "Queen\x00David Bowie", "TALB", "Hot Space"),
+			want: Tags{Title: "Under Pressure", Artists: []string{"Queen", "David Bowie"}, Album: "Hot Space"},
+		},
+		{
+			name: "id3v1.mp3",
+			data: id3v1("Yesterday", "The Beatles", "Help!"),
+			want: Tags{Title: "Yesterday", Artists: []string{"The Beatles"}, Album: "Help!"},
+		},
+		{
+			name: "vorbis.flac",
+			data: flac(125, "TITLE=Yesterday", "artist=The Beatles", "ALBUM=Help!"),
+			want: Tags{Title: "Yesterday", Artists: []string{"The Beatles"}, Album: "Help!", Duration: 125 * time.Second},
+		},
+		{
+			name: "empty.m4a",
+			data: []byte("...."),
+			want: Tags{},
+		},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		require.NoError(t, os.WriteFile(path, tt.data, 0o600))
+
+		tags, err := ReadTags(path)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, *tags, tt.name)
+	}
+}
+
+func TestReadTags_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.mp3")
+	tag := id3v2(3, "TIT2", "Yesterday")
+	require.NoError(t, os.WriteFile(path, tag[:len(tag)-30], 0o600))
+
+	_, err := ReadTags(path)
+	assert.Equal(t, errInvalidTag, err)
+}
+
+func TestTagsFromFileName(t *testing.T) {
+	tests := map[string]Tags{
+		"The Beatles - Yesterday.mp3":          {Title: "Yesterday", Artists: []string{"The Beatles"}},
+		"01 - Yesterday.mp3":                   {Title: "Yesterday"},
+		"03. Queen feat. Bowie - Pressure.mp3": {Title: "Pressure", Artists: []string{"Queen", "Bowie"}},
+		"dir/AC_DC - Highway to Hell.flac":     {Title: "Highway to Hell", Artists: []string{"AC DC"}},
+		"Yesterday.mp3":                        {Title: "Yesterday"},
+	}
+
+	for name, want := range tests {
+		assert.Equal(t, want, *tagsFromFileName(name), name)
+	}
+}
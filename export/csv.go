@@ -0,0 +1,132 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// Columns of CSV.
+const (
+	ColumnID         Column = "id"
+	ColumnAlbumID    Column = "album_id"
+	ColumnTitle      Column = "title"
+	ColumnArtists    Column = "artists"
+	ColumnAlbum      Column = "album"
+	ColumnYear       Column = "year"
+	ColumnGenre      Column = "genre"
+	ColumnDuration   Column = "duration"
+	ColumnDurationMs Column = "duration_ms"
+	ColumnLocation   Column = "location"
+	ColumnURL        Column = "url"
+	ColumnImage      Column = "image"
+)
+
+// Column is a column of CSV.
+type Column string
+
+// DefaultColumns are columns of CSV by default.
+var DefaultColumns = []Column{
+	ColumnID,
+	ColumnAlbumID,
+	ColumnArtists,
+	ColumnTitle,
+	ColumnAlbum,
+	ColumnDuration,
+	ColumnLocation,
+}
+
+var knownColumns = map[Column]bool{
+	ColumnID:         true,
+	ColumnAlbumID:    true,
+	ColumnTitle:      true,
+	ColumnArtists:    true,
+	ColumnAlbum:      true,
+	ColumnYear:       true,
+	ColumnGenre:      true,
+	ColumnDuration:   true,
+	ColumnDurationMs: true,
+	ColumnLocation:   true,
+	ColumnURL:        true,
+	ColumnImage:      true,
+}
+
+// WriteCSV writes playlist as CSV with header row. Columns are set by
+// Options.Columns.
+func WriteCSV(w io.Writer, playlist *Playlist, opts *Options) error {
+	o := opts.withDefaults()
+
+	header := make([]string, 0, len(o.Columns))
+	for _, column := range o.Columns {
+		if !knownColumns[column] {
+			return fmt.Errorf("export: unknown column %q", column)
+		}
+		header = append(header, string(column))
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, track := range playlist.Tracks {
+		record := make([]string, 0, len(o.Columns))
+		for _, column := range o.Columns {
+			record = append(record, o.csvValue(column, track))
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvValue returns value of the column for the track.
+func (o Options) csvValue(column Column, track yamusic.Track) string {
+	var album yamusic.Album
+	if len(track.Albums) > 0 {
+		album = track.Albums[0]
+	}
+
+	switch column {
+	case ColumnID:
+		return track.ID
+	case ColumnAlbumID:
+		if album.ID == 0 {
+			return ""
+		}
+		return strconv.Itoa(album.ID)
+	case ColumnTitle:
+		return track.Title
+	case ColumnArtists:
+		return track.ArtistNames()
+	case ColumnAlbum:
+		return album.Title
+	case ColumnYear:
+		if album.Year == 0 {
+			return ""
+		}
+		return strconv.Itoa(album.Year)
+	case ColumnGenre:
+		return album.Genre
+	case ColumnDuration:
+		seconds := track.DurationMs / 1000
+		return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+	case ColumnDurationMs:
+		return strconv.Itoa(track.DurationMs)
+	case ColumnLocation:
+		return o.location(track)
+	case ColumnURL:
+		return TrackURL(track)
+	case ColumnImage:
+		return o.image(track)
+	}
+
+	return ""
+}
@@ -0,0 +1,39 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, testPlaylist(), nil)
+	require.NoError(t, err)
+
+	want := `id,album_id,artists,title,album,duration,location
+10,20,The Beatles,Yesterday,Help!,2:05,https://music.yandex.ru/album/20/track/10
+11,,,"Unknown, track",,0:00,https://music.yandex.ru/track/11
+`
+	assert.Equal(t, want, buf.String())
+}
+
+func TestWriteCSV_Columns(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, testPlaylist(), &Options{
+		Columns:  []Column{ColumnTitle, ColumnYear, ColumnGenre, ColumnDurationMs, ColumnLocation},
+		Manifest: Manifest{"10:20": "/music/10.mp3"},
+	})
+	require.NoError(t, err)
+
+	want := `title,year,genre,duration_ms,location
+Yesterday,1965,rock,125600,/music/10.mp3
+"Unknown, track",,,0,https://music.yandex.ru/track/11
+`
+	assert.Equal(t, want, buf.String())
+
+	err = WriteCSV(&buf, testPlaylist(), &Options{Columns: []Column{"bpm"}})
+	assert.EqualError(t, err, `export: unknown column "bpm"`)
+}
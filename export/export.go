@@ -0,0 +1,227 @@
+// Package export writes Yandex.Music playlists to M3U8, XSPF, JSPF and CSV
+// so they can be imported into other players and services.
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// ErrUnknownFormat is returned for unsupported export formats.
+var ErrUnknownFormat = errors.New("export: unknown format")
+
+// Export formats.
+const (
+	FormatM3U8 Format = "m3u8"
+	FormatXSPF Format = "xspf"
+	FormatJSPF Format = "jspf"
+	FormatCSV  Format = "csv"
+)
+
+type (
+	// Format is a format of exported playlist.
+	Format string
+
+	// Playlist is a list of tracks with metadata to export.
+	Playlist struct {
+		Title      string
+		Creator    string
+		Annotation string
+		// Image is absolute URL of the cover.
+		Image  string
+		Tracks []yamusic.Track
+	}
+
+	// Options are options of writers.
+	Options struct {
+		// Manifest maps tracks to local files. Tracks found in the manifest
+		// have file path as location.
+		Manifest Manifest
+		// BaseDir makes paths from Manifest relative to it.
+		BaseDir string
+		// URL returns location of tracks not found in Manifest. Defaults to
+		// track's page on music.yandex.ru.
+		URL func(track yamusic.Track) string
+		// ImageSize is a size of covers like "400x400". Defaults to "400x400".
+		ImageSize string
+		// Columns are columns of CSV. Defaults to DefaultColumns.
+		Columns []Column
+	}
+
+	// Manifest maps track IDs to paths of downloaded files. Keys are either
+	// "trackID:albumID" or "trackID".
+	Manifest map[string]string
+)
+
+// FromPlaylist returns playlist to export from the API response.
+func FromPlaylist(playlist *yamusic.PlaylistsGetResp) *Playlist {
+	result := playlist.Result
+
+	creator := result.Owner.Name
+	if creator == "" {
+		creator = result.Owner.Login
+	}
+
+	image := yamusic.CoverURL(result.OgImage, "400x400")
+	if result.Cover.URI != "" {
+		image = yamusic.CoverURL(result.Cover.URI, "400x400")
+	}
+
+	tracks := make([]yamusic.Track, 0, len(result.Tracks))
+	for _, track := range result.Tracks {
+		tracks = append(tracks, track.Track)
+	}
+
+	return &Playlist{
+		Title:      result.Title,
+		Creator:    creator,
+		Annotation: result.Description,
+		Image:      image,
+		Tracks:     tracks,
+	}
+}
+
+// FromTracks returns playlist to export from any list of tracks.
+func FromTracks(title string, tracks []yamusic.Track) *Playlist {
+	return &Playlist{
+		Title:  title,
+		Tracks: tracks,
+	}
+}
+
+// FormatFromPath returns format by extension of the file.
+func FormatFromPath(path string) (Format, error) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	switch ext {
+	case "m3u", "m3u8":
+		return FormatM3U8, nil
+	case "xspf":
+		return FormatXSPF, nil
+	case "jspf", "json":
+		return FormatJSPF, nil
+	case "csv":
+		return FormatCSV, nil
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnknownFormat, ext)
+}
+
+// Write writes playlist to w in the format.
+func Write(w io.Writer, format Format, playlist *Playlist, opts *Options) error {
+	switch format {
+	case FormatM3U8:
+		return WriteM3U8(w, playlist, opts)
+	case FormatXSPF:
+		return WriteXSPF(w, playlist, opts)
+	case FormatJSPF:
+		return WriteJSPF(w, playlist, opts)
+	case FormatCSV:
+		return WriteCSV(w, playlist, opts)
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+// ReadManifest reads manifest from JSON object of track IDs to paths.
+func ReadManifest(r io.Reader) (Manifest, error) {
+	var manifest Manifest
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("export: decode manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// LoadManifest reads manifest from the file.
+func LoadManifest(path string) (Manifest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadManifest(file)
+}
+
+// Path returns path of downloaded track.
+func (m Manifest) Path(track yamusic.Track) (string, bool) {
+	if len(track.Albums) > 0 {
+		if path, ok := m[fmt.Sprintf("%s:%d", track.ID, track.Albums[0].ID)]; ok {
+			return path, true
+		}
+	}
+
+	path, ok := m[track.ID]
+	return path, ok
+}
+
+// withDefaults returns copy of options with default values set.
+func (o *Options) withDefaults() Options {
+	var opts Options
+	if o != nil {
+		opts = *o
+	}
+
+	if opts.URL == nil {
+		opts.URL = TrackURL
+	}
+	if opts.ImageSize == "" {
+		opts.ImageSize = "400x400"
+	}
+	if len(opts.Columns) == 0 {
+		opts.Columns = DefaultColumns
+	}
+
+	return opts
+}
+
+// location returns path of the track from manifest or its URL.
+func (o Options) location(track yamusic.Track) string {
+	path, ok := o.Manifest.Path(track)
+	if !ok {
+		return o.URL(track)
+	}
+
+	if o.BaseDir != "" {
+		if rel, err := filepath.Rel(o.BaseDir, path); err == nil {
+			path = rel
+		}
+	}
+
+	return filepath.ToSlash(path)
+}
+
+// image returns cover URL of the track.
+func (o Options) image(track yamusic.Track) string {
+	uri := track.CoverURI
+	if uri == "" && len(track.Albums) > 0 {
+		uri = track.Albums[0].CoverURI
+	}
+
+	return yamusic.CoverURL(uri, o.ImageSize)
+}
+
+// TrackURL returns page of the track on music.yandex.ru.
+func TrackURL(track yamusic.Track) string {
+	if len(track.Albums) == 0 {
+		return fmt.Sprintf("https://music.yandex.ru/track/%s", track.ID)
+	}
+
+	return fmt.Sprintf("https://music.yandex.ru/album/%d/track/%s", track.Albums[0].ID, track.ID)
+}
+
+// albumTitle returns title of the first album of the track.
+func albumTitle(track yamusic.Track) string {
+	if len(track.Albums) == 0 {
+		return ""
+	}
+
+	return track.Albums[0].Title
+}
@@ -0,0 +1,106 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPlaylist returns playlist with a full track and a track without album.
+func testPlaylist() *Playlist {
+	return &Playlist{
+		Title:      "Road trip",
+		Creator:    "user",
+		Annotation: "Songs for the road",
+		Image:      "https://avatars.yandex.net/playlist/400x400",
+		Tracks: []yamusic.Track{
+			{
+				ID:         "10",
+				Title:      "Yesterday",
+				DurationMs: 125600,
+				CoverURI:   "avatars.yandex.net/track/%%",
+				Artists:    yamusic.Artists{{Name: "The Beatles"}},
+				Albums: yamusic.Albums{{
+					ID:    20,
+					Title: "Help!",
+					Year:  1965,
+					Genre: "rock",
+				}},
+			},
+			{
+				ID:    "11",
+				Title: "Unknown, track",
+			},
+		},
+	}
+}
+
+func TestFromPlaylist(t *testing.T) {
+	resp := new(yamusic.PlaylistsGetResp)
+	resp.Result.Title = "Road trip"
+	resp.Result.Description = "Songs for the road"
+	resp.Result.Owner.Login = "user"
+	resp.Result.Cover.URI = "avatars.yandex.net/playlist/%%"
+	resp.Result.Tracks = yamusic.Tracks{
+		{Track: yamusic.Track{ID: "10"}},
+		{Track: yamusic.Track{ID: "11"}},
+	}
+
+	playlist := FromPlaylist(resp)
+	assert.Equal(t, "Road trip", playlist.Title)
+	assert.Equal(t, "user", playlist.Creator)
+	assert.Equal(t, "Songs for the road", playlist.Annotation)
+	assert.Equal(t, "https://avatars.yandex.net/playlist/400x400", playlist.Image)
+	assert.Equal(t, []yamusic.Track{{ID: "10"}, {ID: "11"}}, playlist.Tracks)
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]Format{
+		"list.m3u":   FormatM3U8,
+		"list.M3U8":  FormatM3U8,
+		"list.xspf":  FormatXSPF,
+		"list.jspf":  FormatJSPF,
+		"list.json":  FormatJSPF,
+		"a/list.csv": FormatCSV,
+	}
+
+	for path, want := range tests {
+		format, err := FormatFromPath(path)
+		assert.NoError(t, err, path)
+		assert.Equal(t, want, format, path)
+	}
+
+	_, err := FormatFromPath("list.txt")
+	assert.True(t, errors.Is(err, ErrUnknownFormat))
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	err := Write(new(bytes.Buffer), "pls", testPlaylist(), nil)
+	assert.True(t, errors.Is(err, ErrUnknownFormat))
+}
+
+func TestReadManifest(t *testing.T) {
+	manifest, err := ReadManifest(strings.NewReader(`{"10:20":"/music/10.mp3","11":"/music/11.mp3"}`))
+	require.NoError(t, err)
+
+	tracks := testPlaylist().Tracks
+
+	path, ok := manifest.Path(tracks[0])
+	assert.True(t, ok)
+	assert.Equal(t, "/music/10.mp3", path)
+
+	path, ok = manifest.Path(tracks[1])
+	assert.True(t, ok)
+	assert.Equal(t, "/music/11.mp3", path)
+
+	_, ok = manifest.Path(yamusic.Track{ID: "12"})
+	assert.False(t, ok)
+
+	_, err = ReadManifest(strings.NewReader(`[`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteM3U8 writes playlist as extended M3U in UTF-8.
+func WriteM3U8(w io.Writer, playlist *Playlist, opts *Options) error {
+	o := opts.withDefaults()
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "#EXTM3U")
+	if playlist.Title != "" {
+		fmt.Fprintf(bw, "#PLAYLIST:%s\n", oneLine(playlist.Title))
+	}
+
+	for _, track := range playlist.Tracks {
+		// Duration is in seconds, -1 means unknown.
+		duration := -1
+		if track.DurationMs > 0 {
+			duration = (track.DurationMs + 500) / 1000
+		}
+
+		title := track.Title
+		if artists := track.ArtistNames(); artists != "" {
+			title = artists + " - " + title
+		}
+
+		fmt.Fprintf(bw, "#EXTINF:%d,%s\n", duration, oneLine(title))
+		if album := albumTitle(track); album != "" {
+			fmt.Fprintf(bw, "#EXTALB:%s\n", oneLine(album))
+		}
+		fmt.Fprintln(bw, o.location(track))
+	}
+
+	return bw.Flush()
+}
+
+// oneLine replaces line breaks which would break M3U.
+func oneLine(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+}
@@ -0,0 +1,37 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteM3U8(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteM3U8(&buf, testPlaylist(), nil)
+	require.NoError(t, err)
+
+	want := `#EXTM3U
+#PLAYLIST:Road trip
+#EXTINF:126,The Beatles - Yesterday
+#EXTALB:Help!
+https://music.yandex.ru/album/20/track/10
+#EXTINF:-1,Unknown, track
+https://music.yandex.ru/track/11
+`
+	assert.Equal(t, want, buf.String())
+}
+
+func TestWriteM3U8_Manifest(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteM3U8(&buf, testPlaylist(), &Options{
+		Manifest: Manifest{"10": "/music/road/10.mp3"},
+		BaseDir:  "/music",
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "\nroad/10.mp3\n")
+	assert.Contains(t, buf.String(), "\nhttps://music.yandex.ru/track/11\n")
+}
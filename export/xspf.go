@@ -0,0 +1,125 @@
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+type (
+	xspfPlaylist struct {
+		XMLName    xml.Name    `xml:"playlist"`
+		Version    string      `xml:"version,attr"`
+		Namespace  string      `xml:"xmlns,attr"`
+		Title      string      `xml:"title,omitempty"`
+		Creator    string      `xml:"creator,omitempty"`
+		Annotation string      `xml:"annotation,omitempty"`
+		Image      string      `xml:"image,omitempty"`
+		Tracks     []xspfTrack `xml:"trackList>track"`
+	}
+
+	xspfTrack struct {
+		Location   string `xml:"location,omitempty" json:"-"`
+		Identifier string `xml:"identifier,omitempty" json:"-"`
+		Title      string `xml:"title,omitempty" json:"title,omitempty"`
+		Creator    string `xml:"creator,omitempty" json:"creator,omitempty"`
+		Album      string `xml:"album,omitempty" json:"album,omitempty"`
+		Duration   int    `xml:"duration,omitempty" json:"duration,omitempty"`
+		Image      string `xml:"image,omitempty" json:"image,omitempty"`
+	}
+
+	jspfFile struct {
+		Playlist jspfPlaylist `json:"playlist"`
+	}
+
+	jspfPlaylist struct {
+		Title      string      `json:"title,omitempty"`
+		Creator    string      `json:"creator,omitempty"`
+		Annotation string      `json:"annotation,omitempty"`
+		Image      string      `json:"image,omitempty"`
+		Tracks     []jspfTrack `json:"track"`
+	}
+
+	// jspfTrack differs from XSPF track by location and identifier being
+	// arrays.
+	jspfTrack struct {
+		Location   []string `json:"location,omitempty"`
+		Identifier []string `json:"identifier,omitempty"`
+		xspfTrack
+	}
+)
+
+// WriteXSPF writes playlist as XSPF (XML Shareable Playlist Format).
+func WriteXSPF(w io.Writer, playlist *Playlist, opts *Options) error {
+	o := opts.withDefaults()
+
+	xspf := xspfPlaylist{
+		Version:    "1",
+		Namespace:  "http://xspf.org/ns/0/",
+		Title:      playlist.Title,
+		Creator:    playlist.Creator,
+		Annotation: playlist.Annotation,
+		Image:      playlist.Image,
+		Tracks:     make([]xspfTrack, 0, len(playlist.Tracks)),
+	}
+
+	for _, track := range playlist.Tracks {
+		xspf.Tracks = append(xspf.Tracks, o.xspfTrack(track))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(xspf); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteJSPF writes playlist as JSPF (JSON version of XSPF).
+func WriteJSPF(w io.Writer, playlist *Playlist, opts *Options) error {
+	o := opts.withDefaults()
+
+	jspf := jspfFile{
+		Playlist: jspfPlaylist{
+			Title:      playlist.Title,
+			Creator:    playlist.Creator,
+			Annotation: playlist.Annotation,
+			Image:      playlist.Image,
+			Tracks:     make([]jspfTrack, 0, len(playlist.Tracks)),
+		},
+	}
+
+	for _, track := range playlist.Tracks {
+		xspf := o.xspfTrack(track)
+		jspf.Playlist.Tracks = append(jspf.Playlist.Tracks, jspfTrack{
+			Location:   []string{xspf.Location},
+			Identifier: []string{xspf.Identifier},
+			xspfTrack:  xspf,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jspf)
+}
+
+// xspfTrack converts track to XSPF track.
+func (o Options) xspfTrack(track yamusic.Track) xspfTrack {
+	return xspfTrack{
+		Location:   o.location(track),
+		Identifier: TrackURL(track),
+		Title:      track.Title,
+		Creator:    track.ArtistNames(),
+		Album:      albumTitle(track),
+		Duration:   track.DurationMs,
+		Image:      o.image(track),
+	}
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteXSPF(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteXSPF(&buf, testPlaylist(), &Options{
+		URL: func(track yamusic.Track) string { return "https://example.com/" + track.ID },
+	})
+	require.NoError(t, err)
+
+	var got xspfPlaylist
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &got))
+
+	assert.Equal(t, "1", got.Version)
+	assert.Equal(t, "Road trip", got.Title)
+	assert.Equal(t, "user", got.Creator)
+	assert.Equal(t, "https://avatars.yandex.net/playlist/400x400", got.Image)
+	require.Len(t, got.Tracks, 2)
+	assert.Equal(t, xspfTrack{
+		Location:   "https://example.com/10",
+		Identifier: "https://music.yandex.ru/album/20/track/10",
+		Title:      "Yesterday",
+		Creator:    "The Beatles",
+		Album:      "Help!",
+		Duration:   125600,
+		Image:      "https://avatars.yandex.net/track/400x400",
+	}, got.Tracks[0])
+	assert.Contains(t, buf.String(), `<playlist version="1" xmlns="http://xspf.org/ns/0/">`)
+}
+
+func TestWriteJSPF(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteJSPF(&buf, testPlaylist(), nil)
+	require.NoError(t, err)
+
+	want := `{
+		"playlist": {
+			"title": "Road trip",
+			"creator": "user",
+			"annotation": "Songs for the road",
+			"image": "https://avatars.yandex.net/playlist/400x400",
+			"track": [
+				{
+					"location": ["https://music.yandex.ru/album/20/track/10"],
+					"identifier": ["https://music.yandex.ru/album/20/track/10"],
+					"title": "Yesterday",
+					"creator": "The Beatles",
+					"album": "Help!",
+					"duration": 125600,
+					"image": "https://avatars.yandex.net/track/400x400"
+				},
+				{
+					"location": ["https://music.yandex.ru/track/11"],
+					"identifier": ["https://music.yandex.ru/track/11"],
+					"title": "Unknown, track"
+				}
+			]
+		}
+	}`
+	assert.JSONEq(t, want, buf.String())
+}
@@ -0,0 +1,189 @@
+// Package importer imports playlists from M3U, XSPF, JSPF, CSV and Spotify
+// exports to Yandex.Music. Entries are matched to tracks by search with
+// fuzzy comparison of titles, artists, durations and albums.
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// addTracksBatch is max count of tracks added to playlist by one request.
+const addTracksBatch = 100
+
+type (
+	// Options are options of Import.
+	Options struct {
+		// Title of created playlist. Defaults to title of imported playlist.
+		Title string
+		// Public makes created playlist public.
+		Public bool
+		// DryRun only matches entries without creating playlist.
+		DryRun bool
+		// SkipLowConfidence doesn't add low-confidence matches to playlist.
+		SkipLowConfidence bool
+		// Match are options of matching.
+		Match *MatchOptions
+	}
+
+	// Report is a result of import.
+	Report struct {
+		// Playlist is created playlist. It's nil for dry run.
+		Playlist *yamusic.PlaylistsResult
+		// Matches are results of matching in order of entries.
+		Matches []Match
+		// Added is count of tracks added to the playlist.
+		Added int
+	}
+)
+
+// Import matches entries of the playlist to tracks, creates playlist and
+// adds matched tracks to it. Report is returned even if creating the
+// playlist failed so matches are not lost.
+func Import(
+	ctx context.Context,
+	client *yamusic.Client,
+	playlist *Playlist,
+	opts *Options,
+) (*Report, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	report := &Report{
+		Matches: NewMatcher(client, opts.Match).MatchAll(ctx, playlist.Entries),
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	var tracks []yamusic.PlaylistsTrack
+	for _, match := range report.Matches {
+		switch match.Status {
+		case MatchStatusMatched:
+		case MatchStatusLowConfidence:
+			if opts.SkipLowConfidence {
+				continue
+			}
+		default:
+			continue
+		}
+
+		tracks = append(tracks, yamusic.PlaylistsTrack{
+			ID:      match.Best.TrackID,
+			AlbumID: match.Best.AlbumID,
+		})
+	}
+
+	title := opts.Title
+	if title == "" {
+		title = playlist.Title
+	}
+	if title == "" {
+		title = "Imported playlist"
+	}
+
+	created, resp, err := client.Playlists().Create(ctx, title, opts.Public)
+	if err != nil {
+		return report, err
+	}
+	if err := yamusic.CheckResponse(resp, created.Error); err != nil {
+		return report, err
+	}
+
+	result := created.Result
+	report.Playlist = &result
+
+	for start := 0; start < len(tracks); start += addTracksBatch {
+		end := start + addTracksBatch
+		if end > len(tracks) {
+			end = len(tracks)
+		}
+
+		added, resp, err := client.Playlists().AddTracks(
+			ctx,
+			result.Kind,
+			result.Revision,
+			tracks[start:end],
+			&yamusic.PlaylistsAddTracksOptions{At: result.TrackCount},
+		)
+		if err != nil {
+			return report, err
+		}
+		if err := yamusic.CheckResponse(resp, added.Error); err != nil {
+			return report, err
+		}
+
+		result = added.Result
+		report.Playlist = &result
+		report.Added = end
+	}
+
+	return report, nil
+}
+
+// Filter returns matches with the status.
+func (r *Report) Filter(status MatchStatus) []Match {
+	var matches []Match
+	for _, match := range r.Matches {
+		if match.Status == status {
+			matches = append(matches, match)
+		}
+	}
+
+	return matches
+}
+
+// WriteText writes human-readable summary with unmatched and low-confidence
+// entries.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	if r.Playlist != nil {
+		fmt.Fprintf(tw, "Playlist:\t%s (kind %d)\n", r.Playlist.Title, r.Playlist.Kind)
+		fmt.Fprintf(tw, "Added:\t%d\n", r.Added)
+	}
+	fmt.Fprintf(tw, "Entries:\t%d\n", len(r.Matches))
+	fmt.Fprintf(tw, "Matched:\t%d\n", len(r.Filter(MatchStatusMatched)))
+	fmt.Fprintf(tw, "Low confidence:\t%d\n", len(r.Filter(MatchStatusLowConfidence)))
+	fmt.Fprintf(tw, "Unmatched:\t%d\n", len(r.Filter(MatchStatusUnmatched)))
+
+	if low := r.Filter(MatchStatusLowConfidence); len(low) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "LOW CONFIDENCE\tSCORE\tMATCHED TRACK")
+		for _, match := range low {
+			best := match.Best
+			fmt.Fprintf(
+				tw,
+				"%d. %s\t%.2f\t%s (%d:%d)\n",
+				match.Entry.Position,
+				match.Entry,
+				best.Score,
+				Entry{Title: best.Title, Artists: best.Artists},
+				best.TrackID,
+				best.AlbumID,
+			)
+		}
+	}
+
+	if unmatched := r.Filter(MatchStatusUnmatched); len(unmatched) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "UNMATCHED\tREASON")
+		for _, match := range unmatched {
+			reason := "not found"
+			if match.Err != nil {
+				reason = match.Err.Error()
+			} else if match.Best != nil {
+				reason = fmt.Sprintf("best score %.2f", match.Best.Score)
+			}
+			fmt.Fprintf(tw, "%d. %s\t%s\n", match.Entry.Position, match.Entry, reason)
+		}
+	}
+
+	return tw.Flush()
+}
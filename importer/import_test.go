@@ -0,0 +1,121 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImport(t *testing.T) {
+	mux, client := setup(t)
+
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		switch text := r.URL.Query().Get("text"); {
+		case strings.Contains(text, "Yesterday"):
+			fmt.Fprint(w, searchYesterdayResponse)
+		case strings.Contains(text, "Help"):
+			fmt.Fprint(w, `{"result":{"tracks":{"results":[
+				{"id":3,"title":"Help Me","artists":[{"name":"Joni Mitchell"}],"albums":[{"id":30}]}
+			]}}}`)
+		default:
+			fmt.Fprint(w, `{"result":{"tracks":{"results":[]}}}`)
+		}
+	})
+	mux.HandleFunc("/users/2000/playlists/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Road trip", r.FormValue("title"))
+		assert.Equal(t, "private", r.FormValue("visibility"))
+		fmt.Fprint(w, `{"result":{"kind":5,"title":"Road trip","revision":1}}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/5/change-relative", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.FormValue("revision"))
+		assert.JSONEq(t,
+			`[{"op":"insert","at":0,"tracks":[{"id":2,"albumId":20},{"id":3,"albumId":30}]}]`,
+			r.FormValue("diff"),
+		)
+		fmt.Fprint(w, `{"result":{"kind":5,"title":"Road trip","revision":2,"trackCount":2}}`)
+	})
+
+	playlist := &Playlist{
+		Title: "Road trip",
+		Entries: []Entry{
+			{Position: 1, Title: "Yesterday", Artists: []string{"The Beatles"}, Duration: 125 * time.Second},
+			{Position: 2, Title: "Help", Artists: []string{"Joni Mitchel"}},
+			{Position: 3, Title: "Unknown", Artists: []string{"Nobody"}},
+		},
+	}
+
+	report, err := Import(context.Background(), client, playlist, nil)
+	require.NoError(t, err)
+
+	require.NotNil(t, report.Playlist)
+	assert.Equal(t, 5, report.Playlist.Kind)
+	assert.Equal(t, 2, report.Playlist.Revision)
+	assert.Equal(t, 2, report.Added)
+	assert.Len(t, report.Filter(MatchStatusMatched), 1)
+	assert.Len(t, report.Filter(MatchStatusLowConfidence), 1)
+	assert.Len(t, report.Filter(MatchStatusUnmatched), 1)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteText(&buf))
+	assert.Contains(t, buf.String(), "Playlist:        Road trip (kind 5)")
+	assert.Contains(t, buf.String(), "2. Joni Mitchel - Help")
+	assert.Contains(t, buf.String(), "Joni Mitchell - Help Me (3:30)")
+	assert.Contains(t, buf.String(), "3. Nobody - Unknown")
+	assert.Contains(t, buf.String(), "not found")
+}
+
+func TestImport_DryRun(t *testing.T) {
+	mux, client := setup(t)
+
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, searchYesterdayResponse)
+	})
+	mux.HandleFunc("/users/2000/playlists/create", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("playlist must not be created")
+	})
+
+	playlist := &Playlist{Entries: []Entry{{Position: 1, Title: "Yesterday", Artists: []string{"The Beatles"}}}}
+
+	report, err := Import(context.Background(), client, playlist, &Options{DryRun: true})
+	require.NoError(t, err)
+
+	assert.Nil(t, report.Playlist)
+	assert.Equal(t, 0, report.Added)
+	require.Len(t, report.Matches, 1)
+	assert.Equal(t, MatchStatusMatched, report.Matches[0].Status)
+}
+
+func TestImport_SkipLowConfidence(t *testing.T) {
+	mux, client := setup(t)
+
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, searchYesterdayResponse)
+	})
+	mux.HandleFunc("/users/2000/playlists/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Imported", r.FormValue("title"))
+		assert.Equal(t, "public", r.FormValue("visibility"))
+		fmt.Fprint(w, `{"result":{"kind":5,"revision":1}}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/5/change-relative", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("low-confidence tracks must not be added")
+	})
+
+	playlist := &Playlist{Entries: []Entry{{Position: 1, Title: "Yesterday", Artists: []string{"Tribute Band"}}}}
+
+	report, err := Import(context.Background(), client, playlist, &Options{
+		Title:             "Imported",
+		Public:            true,
+		SkipLowConfidence: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, report.Added)
+	assert.Len(t, report.Filter(MatchStatusLowConfidence), 1)
+}
@@ -0,0 +1,443 @@
+package importer
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// Match statuses.
+const (
+	// MatchStatusMatched means the best candidate is a confident match.
+	MatchStatusMatched MatchStatus = "matched"
+	// MatchStatusLowConfidence means the best candidate is likely a match
+	// but should be checked by a human.
+	MatchStatusLowConfidence MatchStatus = "low-confidence"
+	// MatchStatusUnmatched means no candidate scored high enough.
+	MatchStatusUnmatched MatchStatus = "unmatched"
+)
+
+// Weights of score components. Weights of unknown components (like album
+// of an entry without album) are not counted.
+const (
+	titleWeight    = 0.45
+	artistsWeight  = 0.35
+	durationWeight = 0.15
+	albumWeight    = 0.05
+
+	// noArtistsPenalty is applied to score of entries without artists
+	// because title alone is weak evidence.
+	noArtistsPenalty = 0.85
+)
+
+type (
+	// MatchStatus is a status of matching an entry.
+	MatchStatus string
+
+	// Candidate is a Yandex.Music track found for an entry.
+	Candidate struct {
+		TrackID  int
+		AlbumID  int
+		Title    string
+		Artists  []string
+		Album    string
+		Duration time.Duration
+		// Score is a similarity to the entry from 0 to 1.
+		Score float64
+	}
+
+	// Match is a result of matching an entry.
+	Match struct {
+		Entry  Entry
+		Status MatchStatus
+		// Best is the best candidate. It's nil if nothing was found.
+		Best *Candidate
+		// Candidates are found tracks sorted by score.
+		Candidates []Candidate
+		// Err is an error of search.
+		Err error
+	}
+
+	// MatchOptions are options of Matcher.
+	MatchOptions struct {
+		// MinScore is a score below which entry is unmatched.
+		// Defaults to 0.5.
+		MinScore float64
+		// ConfidentScore is a score from which match is confident.
+		// Defaults to 0.8.
+		ConfidentScore float64
+		// DurationTolerance is a difference of durations which is still an
+		// exact match. Defaults to 3s.
+		DurationTolerance time.Duration
+		// Concurrency is count of concurrent searches. Defaults to 4.
+		Concurrency int
+	}
+
+	// Matcher matches entries to Yandex.Music tracks using search.
+	Matcher struct {
+		client *yamusic.Client
+		opts   MatchOptions
+	}
+)
+
+// NewMatcher returns matcher which searches tracks with the client.
+func NewMatcher(client *yamusic.Client, opts *MatchOptions) *Matcher {
+	if opts == nil {
+		opts = &MatchOptions{}
+	}
+
+	o := *opts
+	if o.MinScore <= 0 {
+		o.MinScore = 0.5
+	}
+	if o.ConfidentScore <= 0 {
+		o.ConfidentScore = 0.8
+	}
+	if o.DurationTolerance <= 0 {
+		o.DurationTolerance = 3 * time.Second
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+
+	return &Matcher{client: client, opts: o}
+}
+
+// MatchAll matches entries concurrently. Matches are in order of entries.
+func (m *Matcher) MatchAll(ctx context.Context, entries []Entry) []Match {
+	matches := make([]Match, len(entries))
+	sem := make(chan struct{}, m.opts.Concurrency)
+
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		wg.Add(1)
+		go func(i int, entry Entry) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			matches[i] = m.Match(ctx, entry)
+		}(i, entry)
+	}
+	wg.Wait()
+
+	return matches
+}
+
+// Match searches tracks by artist and title of the entry and scores them.
+// If there is no confident match by artist and title, it also searches by
+// title only.
+func (m *Matcher) Match(ctx context.Context, entry Entry) Match {
+	match := Match{Entry: entry, Status: MatchStatusUnmatched}
+
+	title := cleanTitle(entry.Title)
+	queries := []string{title}
+	if len(entry.Artists) > 0 {
+		queries = []string{entry.Artists[0] + " " + title, title}
+	}
+
+	seen := make(map[int]bool)
+	for _, query := range queries {
+		candidates, err := m.search(ctx, query)
+		if err != nil {
+			match.Err = err
+			return match
+		}
+
+		for _, candidate := range candidates {
+			if seen[candidate.TrackID] {
+				continue
+			}
+			seen[candidate.TrackID] = true
+
+			candidate.Score = score(entry, candidate, m.opts.DurationTolerance)
+			match.Candidates = append(match.Candidates, candidate)
+		}
+
+		sort.SliceStable(match.Candidates, func(i, j int) bool {
+			return match.Candidates[i].Score > match.Candidates[j].Score
+		})
+
+		if len(match.Candidates) > 0 && match.Candidates[0].Score >= m.opts.ConfidentScore {
+			break
+		}
+	}
+
+	if len(match.Candidates) == 0 {
+		return match
+	}
+
+	best := match.Candidates[0]
+	match.Best = &best
+
+	switch {
+	case best.Score >= m.opts.ConfidentScore:
+		match.Status = MatchStatusMatched
+	case best.Score >= m.opts.MinScore:
+		match.Status = MatchStatusLowConfidence
+	}
+
+	return match
+}
+
+// search returns tracks found by query.
+func (m *Matcher) search(ctx context.Context, query string) ([]Candidate, error) {
+	result, resp, err := m.client.Search().Tracks(ctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := yamusic.CheckResponse(resp, result.Error); err != nil {
+		return nil, err
+	}
+
+	tracks := result.Result.Tracks.Results
+	candidates := make([]Candidate, 0, len(tracks))
+	for _, track := range tracks {
+		candidate := Candidate{
+			TrackID:  track.ID,
+			Title:    track.Title,
+			Duration: time.Duration(track.DurationMs) * time.Millisecond,
+		}
+		if track.Version != "" {
+			candidate.Title += " (" + track.Version + ")"
+		}
+		for _, artist := range track.Artists {
+			candidate.Artists = append(candidate.Artists, artist.Name)
+		}
+		if len(track.Albums) > 0 {
+			candidate.AlbumID = track.Albums[0].ID
+			candidate.Album = track.Albums[0].Title
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+// score returns similarity of the candidate to the entry from 0 to 1.
+func score(entry Entry, candidate Candidate, tolerance time.Duration) float64 {
+	total := titleWeight * similarity(normalize(entry.Title), normalize(candidate.Title))
+	weights := titleWeight
+
+	if len(entry.Artists) > 0 {
+		total += artistsWeight * artistsSimilarity(entry.Artists, candidate.Artists)
+		weights += artistsWeight
+	}
+
+	if entry.Duration > 0 && candidate.Duration > 0 {
+		total += durationWeight * durationSimilarity(entry.Duration, candidate.Duration, tolerance)
+		weights += durationWeight
+	}
+
+	if entry.Album != "" && candidate.Album != "" {
+		total += albumWeight * similarity(normalize(entry.Album), normalize(candidate.Album))
+		weights += albumWeight
+	}
+
+	result := total / weights
+	if len(entry.Artists) == 0 {
+		result *= noArtistsPenalty
+	}
+
+	return result
+}
+
+// artistsSimilarity returns the best similarity of any pair of artists or of
+// all artists joined.
+func artistsSimilarity(entryArtists, candidateArtists []string) float64 {
+	best := similarity(
+		normalize(strings.Join(entryArtists, " ")),
+		normalize(strings.Join(candidateArtists, " ")),
+	)
+
+	for _, a := range entryArtists {
+		for _, b := range candidateArtists {
+			if s := similarity(normalize(a), normalize(b)); s > best {
+				best = s
+			}
+		}
+	}
+
+	return best
+}
+
+// durationSimilarity is 1 within tolerance and decreases linearly to 0 at
+// four tolerances.
+func durationSimilarity(a, b, tolerance time.Duration) float64 {
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+
+	if diff <= tolerance {
+		return 1
+	}
+	if diff >= 4*tolerance {
+		return 0
+	}
+
+	return 1 - float64(diff-tolerance)/float64(3*tolerance)
+}
+
+// similarity returns the best of edit distance ratio and word overlap of
+// normalized strings.
+func similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if a == "" || b == "" {
+		return 0
+	}
+
+	ratio := levenshteinRatio(a, b)
+	if overlap := wordOverlap(a, b); overlap > ratio {
+		return overlap
+	}
+
+	return ratio
+}
+
+// levenshteinRatio returns 1 - distance / length of the longest string.
+func levenshteinRatio(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
+
+// wordOverlap returns count of common words divided by word count of the
+// longer string.
+func wordOverlap(a, b string) float64 {
+	wa, wb := strings.Fields(a), strings.Fields(b)
+	if len(wa) > len(wb) {
+		wa, wb = wb, wa
+	}
+
+	words := make(map[string]bool, len(wb))
+	for _, w := range wb {
+		words[w] = true
+	}
+
+	var common int
+	for _, w := range wa {
+		if words[w] {
+			common++
+		}
+	}
+
+	return float64(common) / float64(len(wb))
+}
+
+// versionWords mark title suffixes after " - " which describe version of the
+// track like "Yesterday - Remastered 2009".
+var versionWords = []string{"remaster", "live", "version", "edit", "mix", "mono", "stereo", "acoustic", "demo", "bonus"}
+
+// cleanTitle removes version suffixes and featured artists from the title.
+func cleanTitle(title string) string {
+	if i := lastIndexFold(title, " - "); i >= 0 {
+		for _, word := range versionWords {
+			if indexFold(title[i:], word) >= 0 {
+				title = title[:i]
+				break
+			}
+		}
+	}
+
+	for _, feat := range []string{" feat. ", " feat ", " ft. ", " featuring "} {
+		if i := indexFold(title, feat); i >= 0 {
+			title = title[:i]
+		}
+	}
+
+	return strings.TrimSpace(title)
+}
+
+// indexFold is strings.Index ignoring case. substr must be ASCII, so that
+// matches start at rune boundaries of s, unlike offsets in strings.ToLower(s)
+// which may have different length.
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// lastIndexFold is strings.LastIndex ignoring case. substr must be ASCII.
+func lastIndexFold(s, substr string) int {
+	for i := len(s) - len(substr); i >= 0; i-- {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalize converts string to lower case words without punctuation and
+// bracketed parts.
+func normalize(s string) string {
+	s = strings.ToLower(cleanTitle(s))
+	s = strings.Replace(s, "ё", "е", -1)
+
+	var b strings.Builder
+	depth := 0
+	for _, r := range s {
+		switch {
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			if depth > 0 {
+				depth--
+			}
+			b.WriteRune(' ')
+		case depth > 0:
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+
+	return min
+}
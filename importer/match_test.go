@@ -0,0 +1,164 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setup returns mux of test server and client configured to use it.
+func setup(t *testing.T) (*http.ServeMux, *yamusic.Client) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	baseURL, _ := url.Parse(server.URL + "/")
+	return mux, yamusic.NewClient(yamusic.BaseURL(baseURL), yamusic.AccessToken(2000, "accessToken"))
+}
+
+const searchYesterdayResponse = `{"result":{"tracks":{"results":[
+	{"id":1,"title":"Yesterday","version":"Live","durationMs":160000,"artists":[{"name":"Paul McCartney"}],"albums":[{"id":10,"title":"Live"}]},
+	{"id":2,"title":"Yesterday","version":"Remastered 2009","durationMs":125000,"artists":[{"name":"The Beatles"}],"albums":[{"id":20,"title":"Help!"}]}
+]}}}`
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Yesterday - Remastered 2009":     "yesterday",
+		"Under Pressure (feat. Bowie)":    "under pressure",
+		"Don't Stop Me Now [Live]":        "don t stop me now",
+		"Ёлка":                            "елка",
+		"Song feat. Someone":              "song",
+		"Highway - To - Hell":             "highway to hell",
+		"  Spaces   and\tTabs  ":          "spaces and tabs",
+		"AC/DC":                           "ac dc",
+		"Love Song (Acoustic) [2011 Mix]": "love song",
+		"ȺȺȺȺȺȺȺȺ feat X":                 "ⱥⱥⱥⱥⱥⱥⱥⱥ",
+		"İstanbul FEAT. Someone":          "istanbul",
+	}
+
+	for in, want := range tests {
+		assert.Equal(t, want, normalize(in), in)
+	}
+}
+
+func TestScore(t *testing.T) {
+	entry := Entry{
+		Title:    "Yesterday - Remastered 2009",
+		Artists:  []string{"The Beatles"},
+		Album:    "Help!",
+		Duration: 126 * time.Second,
+	}
+
+	exact := Candidate{
+		Title:    "Yesterday (Remastered 2009)",
+		Artists:  []string{"The Beatles"},
+		Album:    "Help!",
+		Duration: 125 * time.Second,
+	}
+	assert.InDelta(t, 1, score(entry, exact, 3*time.Second), 0.001)
+
+	cover := exact
+	cover.Artists = []string{"Paul McCartney"}
+	cover.Duration = 160 * time.Second
+	assert.True(t, score(entry, cover, 3*time.Second) < 0.7)
+
+	other := exact
+	other.Title = "Help!"
+	assert.True(t, score(entry, other, 3*time.Second) < 0.7)
+
+	// Without artists the same title scores lower.
+	noArtists := entry
+	noArtists.Artists = nil
+	assert.InDelta(t, noArtistsPenalty, score(noArtists, exact, 3*time.Second), 0.001)
+}
+
+func TestDurationSimilarity(t *testing.T) {
+	tolerance := 3 * time.Second
+	assert.Equal(t, 1.0, durationSimilarity(100*time.Second, 103*time.Second, tolerance))
+	assert.InDelta(t, 0.5, durationSimilarity(100*time.Second, 107500*time.Millisecond, tolerance), 0.001)
+	assert.Equal(t, 0.0, durationSimilarity(100*time.Second, 112*time.Second, tolerance))
+}
+
+func TestMatcher_Match(t *testing.T) {
+	mux, client := setup(t)
+
+	var queries []string
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("text"))
+		assert.Equal(t, "track", r.URL.Query().Get("type"))
+		fmt.Fprint(w, searchYesterdayResponse)
+	})
+
+	matcher := NewMatcher(client, nil)
+	match := matcher.Match(context.Background(), Entry{
+		Title:    "Yesterday - Remastered 2009",
+		Artists:  []string{"The Beatles"},
+		Duration: 126 * time.Second,
+	})
+
+	assert.Equal(t, []string{"The Beatles Yesterday"}, queries)
+	assert.NoError(t, match.Err)
+	assert.Equal(t, MatchStatusMatched, match.Status)
+	require.NotNil(t, match.Best)
+	assert.Equal(t, 2, match.Best.TrackID)
+	assert.Equal(t, 20, match.Best.AlbumID)
+	assert.Equal(t, []string{"The Beatles"}, match.Best.Artists)
+	require.Len(t, match.Candidates, 2)
+	assert.Equal(t, 1, match.Candidates[1].TrackID)
+}
+
+func TestMatcher_Match_LowConfidence(t *testing.T) {
+	mux, client := setup(t)
+
+	var queries []string
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("text"))
+		fmt.Fprint(w, searchYesterdayResponse)
+	})
+
+	match := NewMatcher(client, nil).Match(context.Background(), Entry{
+		Title:   "Yesterday",
+		Artists: []string{"Beatles Tribute Band"},
+	})
+
+	assert.Equal(t, []string{"Beatles Tribute Band Yesterday", "Yesterday"}, queries)
+	assert.Equal(t, MatchStatusLowConfidence, match.Status)
+	require.NotNil(t, match.Best)
+	assert.Equal(t, 2, match.Best.TrackID)
+}
+
+func TestMatcher_Match_Unmatched(t *testing.T) {
+	mux, client := setup(t)
+
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"tracks":{"results":[]}}}`)
+	})
+
+	match := NewMatcher(client, nil).Match(context.Background(), Entry{Title: "Unknown"})
+	assert.Equal(t, MatchStatusUnmatched, match.Status)
+	assert.Nil(t, match.Best)
+	assert.NoError(t, match.Err)
+}
+
+func TestMatcher_Match_Error(t *testing.T) {
+	mux, client := setup(t)
+
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"name":"validate","message":"bad text"}}`)
+	})
+
+	match := NewMatcher(client, nil).Match(context.Background(), Entry{Title: "Unknown"})
+	assert.Equal(t, MatchStatusUnmatched, match.Status)
+	assert.EqualError(t, match.Err, "400 Bad Request: validate: bad text")
+}
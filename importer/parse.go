@@ -0,0 +1,503 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnknownFormat is returned for unsupported import formats.
+var ErrUnknownFormat = errors.New("importer: unknown format")
+
+// Import formats.
+const (
+	FormatM3U  Format = "m3u"
+	FormatXSPF Format = "xspf"
+	FormatJSPF Format = "jspf"
+	FormatCSV  Format = "csv"
+	// FormatSpotify is JSON of Spotify Web API playlist tracks or
+	// playlists from Spotify account data export.
+	FormatSpotify Format = "spotify"
+)
+
+type (
+	// Format is a format of imported playlist.
+	Format string
+
+	// Playlist is a parsed playlist to import.
+	Playlist struct {
+		Title   string
+		Entries []Entry
+	}
+
+	// Entry is a track of imported playlist.
+	Entry struct {
+		// Position is a position of the entry in the playlist starting from 1.
+		Position int
+		Title    string
+		Artists  []string
+		Album    string
+		// Duration is zero if unknown.
+		Duration time.Duration
+		Location string
+	}
+)
+
+// String returns "Artists - Title" of the entry.
+func (e Entry) String() string {
+	if len(e.Artists) == 0 {
+		return e.Title
+	}
+
+	return strings.Join(e.Artists, ", ") + " - " + e.Title
+}
+
+// FormatFromPath returns format by extension of the file. JSON files are
+// considered Spotify exports.
+func FormatFromPath(path string) (Format, error) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	switch ext {
+	case "m3u", "m3u8":
+		return FormatM3U, nil
+	case "xspf":
+		return FormatXSPF, nil
+	case "jspf":
+		return FormatJSPF, nil
+	case "csv":
+		return FormatCSV, nil
+	case "json":
+		return FormatSpotify, nil
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnknownFormat, ext)
+}
+
+// Parse parses playlist in the format.
+func Parse(r io.Reader, format Format) (*Playlist, error) {
+	switch format {
+	case FormatM3U:
+		return ParseM3U(r)
+	case FormatXSPF:
+		return ParseXSPF(r)
+	case FormatJSPF:
+		return ParseJSPF(r)
+	case FormatCSV:
+		return ParseCSV(r)
+	case FormatSpotify:
+		return ParseSpotify(r)
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+// ParseM3U parses M3U or extended M3U playlist. Artist and title are taken
+// from #EXTINF in "Artist - Title" form or from the file name if there is
+// no #EXTINF.
+func ParseM3U(r io.Reader) (*Playlist, error) {
+	playlist := new(Playlist)
+
+	var entry Entry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			playlist.Title = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+		case strings.HasPrefix(line, "#EXTINF:"):
+			info := strings.TrimPrefix(line, "#EXTINF:")
+			i := strings.Index(info, ",")
+			if i < 0 {
+				continue
+			}
+
+			// Duration may be followed by attributes like tvg-id="...".
+			fields := strings.Fields(info[:i])
+			if len(fields) > 0 {
+				if seconds, err := strconv.Atoi(fields[0]); err == nil && seconds > 0 {
+					entry.Duration = time.Duration(seconds) * time.Second
+				}
+			}
+
+			entry.Artists, entry.Title = splitArtistTitle(info[i+1:])
+		case strings.HasPrefix(line, "#EXTALB:"):
+			entry.Album = strings.TrimSpace(strings.TrimPrefix(line, "#EXTALB:"))
+		case strings.HasPrefix(line, "#EXTART:"):
+			if len(entry.Artists) == 0 {
+				entry.Artists = splitArtists(strings.TrimPrefix(line, "#EXTART:"))
+			}
+		case strings.HasPrefix(line, "#"):
+		default:
+			entry.Location = line
+			if entry.Title == "" {
+				name := path.Base(filepath.ToSlash(line))
+				name = strings.TrimSuffix(name, path.Ext(name))
+				entry.Artists, entry.Title = splitArtistTitle(name)
+			}
+
+			playlist.addEntry(entry)
+			entry = Entry{}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("importer: parse m3u: %w", err)
+	}
+
+	return playlist, nil
+}
+
+type (
+	xspfPlaylist struct {
+		Title  string      `xml:"title"`
+		Tracks []xspfTrack `xml:"trackList>track"`
+	}
+
+	xspfTrack struct {
+		Location string `xml:"location"`
+		Title    string `xml:"title"`
+		Creator  string `xml:"creator"`
+		Album    string `xml:"album"`
+		Duration int    `xml:"duration"`
+	}
+)
+
+// ParseXSPF parses XSPF playlist.
+func ParseXSPF(r io.Reader) (*Playlist, error) {
+	var xspf xspfPlaylist
+	if err := xml.NewDecoder(r).Decode(&xspf); err != nil {
+		return nil, fmt.Errorf("importer: parse xspf: %w", err)
+	}
+
+	playlist := &Playlist{Title: xspf.Title}
+	for _, track := range xspf.Tracks {
+		playlist.addEntry(Entry{
+			Title:    track.Title,
+			Artists:  splitArtists(track.Creator),
+			Album:    track.Album,
+			Duration: time.Duration(track.Duration) * time.Millisecond,
+			Location: strings.TrimSpace(track.Location),
+		})
+	}
+
+	return playlist, nil
+}
+
+type (
+	jspfFile struct {
+		Playlist struct {
+			Title  string      `json:"title"`
+			Tracks []jspfTrack `json:"track"`
+		} `json:"playlist"`
+	}
+
+	jspfTrack struct {
+		Location jspfStrings `json:"location"`
+		Title    string      `json:"title"`
+		Creator  string      `json:"creator"`
+		Album    string      `json:"album"`
+		Duration int         `json:"duration"`
+	}
+
+	// jspfStrings is a string or array of strings.
+	jspfStrings []string
+)
+
+func (s *jspfStrings) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*s = jspfStrings{value}
+		return nil
+	}
+
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	*s = values
+	return nil
+}
+
+// ParseJSPF parses JSPF playlist.
+func ParseJSPF(r io.Reader) (*Playlist, error) {
+	var jspf jspfFile
+	if err := json.NewDecoder(r).Decode(&jspf); err != nil {
+		return nil, fmt.Errorf("importer: parse jspf: %w", err)
+	}
+
+	playlist := &Playlist{Title: jspf.Playlist.Title}
+	for _, track := range jspf.Playlist.Tracks {
+		entry := Entry{
+			Title:    track.Title,
+			Artists:  splitArtists(track.Creator),
+			Album:    track.Album,
+			Duration: time.Duration(track.Duration) * time.Millisecond,
+		}
+		if len(track.Location) > 0 {
+			entry.Location = track.Location[0]
+		}
+		playlist.addEntry(entry)
+	}
+
+	return playlist, nil
+}
+
+// CSV headers by field. Headers are compared in lower case.
+var (
+	csvTitleHeaders      = []string{"title", "track", "track name", "name", "song"}
+	csvArtistsHeaders    = []string{"artists", "artist", "artist name(s)", "artist name", "creator"}
+	csvAlbumHeaders      = []string{"album", "album name", "album title"}
+	csvDurationMsHeaders = []string{"duration_ms", "duration (ms)", "durationms"}
+	csvDurationHeaders   = []string{"duration", "length", "time"}
+	csvLocationHeaders   = []string{"location", "path", "file", "url", "uri", "spotify uri", "track uri"}
+)
+
+// ParseCSV parses CSV with header row. Columns are detected by header names
+// like "title", "artist", "album", "duration" (M:SS or seconds) and
+// "duration_ms", including column names of Exportify.
+func ParseCSV(r io.Reader) (*Playlist, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("importer: parse csv: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+
+	titleColumn := csvColumn(columns, csvTitleHeaders)
+	if titleColumn < 0 {
+		return nil, errors.New("importer: parse csv: no title column")
+	}
+	artistsColumn := csvColumn(columns, csvArtistsHeaders)
+	albumColumn := csvColumn(columns, csvAlbumHeaders)
+	durationMsColumn := csvColumn(columns, csvDurationMsHeaders)
+	durationColumn := csvColumn(columns, csvDurationHeaders)
+	locationColumn := csvColumn(columns, csvLocationHeaders)
+
+	playlist := new(Playlist)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("importer: parse csv: %w", err)
+		}
+
+		field := func(column int) string {
+			if column < 0 || column >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[column])
+		}
+
+		entry := Entry{
+			Title:    field(titleColumn),
+			Artists:  splitArtists(field(artistsColumn)),
+			Album:    field(albumColumn),
+			Location: field(locationColumn),
+		}
+
+		if ms, err := strconv.Atoi(field(durationMsColumn)); err == nil {
+			entry.Duration = time.Duration(ms) * time.Millisecond
+		} else {
+			entry.Duration = parseDuration(field(durationColumn))
+		}
+
+		if entry.Title == "" {
+			continue
+		}
+
+		playlist.addEntry(entry)
+	}
+
+	return playlist, nil
+}
+
+type (
+	// spotifyFile is either a page of Web API playlist tracks or account
+	// data export with playlists.
+	spotifyFile struct {
+		Name      string            `json:"name"`
+		Items     []spotifyItem     `json:"items"`
+		Tracks    *spotifyFile      `json:"tracks"`
+		Playlists []spotifyPlaylist `json:"playlists"`
+	}
+
+	spotifyPlaylist struct {
+		Name  string        `json:"name"`
+		Items []spotifyItem `json:"items"`
+	}
+
+	spotifyItem struct {
+		Track spotifyTrack `json:"track"`
+	}
+
+	spotifyTrack struct {
+		// Web API fields.
+		Name    string `json:"name"`
+		Artists []struct {
+			Name string `json:"name"`
+		} `json:"artists"`
+		Album struct {
+			Name string `json:"name"`
+		} `json:"album"`
+		DurationMs int    `json:"duration_ms"`
+		URI        string `json:"uri"`
+
+		// Account data export fields.
+		TrackName  string `json:"trackName"`
+		ArtistName string `json:"artistName"`
+		AlbumName  string `json:"albumName"`
+		TrackURI   string `json:"trackUri"`
+	}
+)
+
+// ParseSpotify parses JSON of Spotify Web API playlist (or its tracks page)
+// or Playlist.json of Spotify account data export. Only the first playlist
+// of the export is returned, use ParseSpotifyAll to get all of them.
+func ParseSpotify(r io.Reader) (*Playlist, error) {
+	playlists, err := ParseSpotifyAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(playlists) == 0 {
+		return new(Playlist), nil
+	}
+
+	return playlists[0], nil
+}
+
+// ParseSpotifyAll parses all playlists of Spotify JSON.
+func ParseSpotifyAll(r io.Reader) ([]*Playlist, error) {
+	var file spotifyFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("importer: parse spotify: %w", err)
+	}
+
+	if len(file.Playlists) > 0 {
+		playlists := make([]*Playlist, 0, len(file.Playlists))
+		for _, p := range file.Playlists {
+			playlists = append(playlists, spotifyPlaylistEntries(p.Name, p.Items))
+		}
+		return playlists, nil
+	}
+
+	items := file.Items
+	if file.Tracks != nil {
+		items = file.Tracks.Items
+	}
+
+	return []*Playlist{spotifyPlaylistEntries(file.Name, items)}, nil
+}
+
+func spotifyPlaylistEntries(title string, items []spotifyItem) *Playlist {
+	playlist := &Playlist{Title: title}
+	for _, item := range items {
+		track := item.Track
+
+		entry := Entry{
+			Title:    track.Name,
+			Album:    track.Album.Name,
+			Duration: time.Duration(track.DurationMs) * time.Millisecond,
+			Location: track.URI,
+		}
+		for _, artist := range track.Artists {
+			entry.Artists = append(entry.Artists, artist.Name)
+		}
+
+		if entry.Title == "" {
+			entry.Title = track.TrackName
+			entry.Artists = splitArtists(track.ArtistName)
+			entry.Album = track.AlbumName
+			entry.Location = track.TrackURI
+		}
+
+		if entry.Title == "" {
+			continue
+		}
+
+		playlist.addEntry(entry)
+	}
+
+	return playlist
+}
+
+// addEntry appends entry setting its position.
+func (p *Playlist) addEntry(entry Entry) {
+	entry.Position = len(p.Entries) + 1
+	p.Entries = append(p.Entries, entry)
+}
+
+// csvColumn returns index of the first found header or -1.
+func csvColumn(columns map[string]int, headers []string) int {
+	for _, header := range headers {
+		if i, ok := columns[header]; ok {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// splitArtistTitle splits "Artist - Title". Without separator the whole
+// string is a title.
+func splitArtistTitle(s string) ([]string, string) {
+	s = strings.TrimSpace(s)
+	for _, sep := range []string{" - ", " – ", " — "} {
+		if i := strings.Index(s, sep); i >= 0 {
+			return splitArtists(s[:i]), strings.TrimSpace(s[i+len(sep):])
+		}
+	}
+
+	return nil, s
+}
+
+// splitArtists splits list of artists separated by comma or semicolon.
+func splitArtists(s string) []string {
+	var artists []string
+	for _, artist := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }) {
+		if artist = strings.TrimSpace(artist); artist != "" {
+			artists = append(artists, artist)
+		}
+	}
+
+	return artists
+}
+
+// parseDuration parses duration as seconds, M:SS or H:MM:SS.
+func parseDuration(s string) time.Duration {
+	if s == "" {
+		return 0
+	}
+
+	var seconds int
+	for _, part := range strings.Split(s, ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+
+	return time.Duration(seconds) * time.Second
+}
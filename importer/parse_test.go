@@ -0,0 +1,212 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]Format{
+		"a.m3u":   FormatM3U,
+		"a.m3u8":  FormatM3U,
+		"a.XSPF":  FormatXSPF,
+		"a.jspf":  FormatJSPF,
+		"a.csv":   FormatCSV,
+		"a.json":  FormatSpotify,
+		"b/c.csv": FormatCSV,
+	}
+
+	for path, want := range tests {
+		format, err := FormatFromPath(path)
+		assert.NoError(t, err, path)
+		assert.Equal(t, want, format, path)
+	}
+
+	_, err := FormatFromPath("a.pls")
+	assert.True(t, errors.Is(err, ErrUnknownFormat))
+
+	_, err = Parse(strings.NewReader(""), "pls")
+	assert.True(t, errors.Is(err, ErrUnknownFormat))
+}
+
+func TestParseM3U(t *testing.T) {
+	m3u := "\ufeff#EXTM3U\n" +
+		"#PLAYLIST:Road trip\n" +
+		"#EXTINF:126,The Beatles - Yesterday\n" +
+		"#EXTALB:Help!\n" +
+		"/music/yesterday.mp3\n" +
+		"\n" +
+		"# comment\n" +
+		"/music/Queen, David Bowie - Under Pressure.mp3\n" +
+		"#EXTINF:-1,Untitled\n" +
+		"https://example.com/stream\n"
+
+	playlist, err := ParseM3U(strings.NewReader(m3u))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Road trip", playlist.Title)
+	assert.Equal(t, []Entry{
+		{
+			Position: 1,
+			Title:    "Yesterday",
+			Artists:  []string{"The Beatles"},
+			Album:    "Help!",
+			Duration: 126 * time.Second,
+			Location: "/music/yesterday.mp3",
+		},
+		{
+			Position: 2,
+			Title:    "Under Pressure",
+			Artists:  []string{"Queen", "David Bowie"},
+			Location: "/music/Queen, David Bowie - Under Pressure.mp3",
+		},
+		{
+			Position: 3,
+			Title:    "Untitled",
+			Location: "https://example.com/stream",
+		},
+	}, playlist.Entries)
+}
+
+func TestParseXSPF(t *testing.T) {
+	xspf := `<?xml version="1.0" encoding="UTF-8"?>
+<playlist version="1" xmlns="http://xspf.org/ns/0/">
+  <title>Road trip</title>
+  <trackList>
+    <track>
+      <location>file:///music/yesterday.mp3</location>
+      <title>Yesterday</title>
+      <creator>The Beatles</creator>
+      <album>Help!</album>
+      <duration>125600</duration>
+    </track>
+  </trackList>
+</playlist>`
+
+	playlist, err := ParseXSPF(strings.NewReader(xspf))
+	require.NoError(t, err)
+
+	assert.Equal(t, &Playlist{
+		Title: "Road trip",
+		Entries: []Entry{{
+			Position: 1,
+			Title:    "Yesterday",
+			Artists:  []string{"The Beatles"},
+			Album:    "Help!",
+			Duration: 125600 * time.Millisecond,
+			Location: "file:///music/yesterday.mp3",
+		}},
+	}, playlist)
+}
+
+func TestParseJSPF(t *testing.T) {
+	jspf := `{"playlist":{"title":"Road trip","track":[
+		{"location":["https://example.com/1"],"title":"Yesterday","creator":"The Beatles","duration":125600},
+		{"location":"https://example.com/2","title":"Help!","creator":"The Beatles"}
+	]}}`
+
+	playlist, err := ParseJSPF(strings.NewReader(jspf))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Road trip", playlist.Title)
+	require.Len(t, playlist.Entries, 2)
+	assert.Equal(t, "https://example.com/1", playlist.Entries[0].Location)
+	assert.Equal(t, 125600*time.Millisecond, playlist.Entries[0].Duration)
+	assert.Equal(t, "https://example.com/2", playlist.Entries[1].Location)
+	assert.Equal(t, 2, playlist.Entries[1].Position)
+}
+
+func TestParseCSV(t *testing.T) {
+	t.Run("exportify", func(t *testing.T) {
+		csv := "Track URI,Track Name,Artist Name(s),Album Name,Duration (ms)\n" +
+			"spotify:track:1,Under Pressure,\"Queen, David Bowie\",Hot Space,248440\n" +
+			",,,,\n"
+
+		playlist, err := ParseCSV(strings.NewReader(csv))
+		require.NoError(t, err)
+
+		assert.Equal(t, []Entry{{
+			Position: 1,
+			Title:    "Under Pressure",
+			Artists:  []string{"Queen", "David Bowie"},
+			Album:    "Hot Space",
+			Duration: 248440 * time.Millisecond,
+			Location: "spotify:track:1",
+		}}, playlist.Entries)
+	})
+
+	t.Run("duration", func(t *testing.T) {
+		csv := "title,artists,duration\n" +
+			"Yesterday,The Beatles,2:05\n" +
+			"Help!,The Beatles,138\n" +
+			"Girl,The Beatles\n"
+
+		playlist, err := ParseCSV(strings.NewReader(csv))
+		require.NoError(t, err)
+
+		require.Len(t, playlist.Entries, 3)
+		assert.Equal(t, 125*time.Second, playlist.Entries[0].Duration)
+		assert.Equal(t, 138*time.Second, playlist.Entries[1].Duration)
+		assert.Equal(t, time.Duration(0), playlist.Entries[2].Duration)
+	})
+
+	t.Run("no title", func(t *testing.T) {
+		_, err := ParseCSV(strings.NewReader("artist,album\n"))
+		assert.EqualError(t, err, "importer: parse csv: no title column")
+	})
+}
+
+func TestParseSpotify(t *testing.T) {
+	t.Run("web api", func(t *testing.T) {
+		json := `{"name":"Road trip","tracks":{"items":[{"track":{
+			"name":"Under Pressure",
+			"artists":[{"name":"Queen"},{"name":"David Bowie"}],
+			"album":{"name":"Hot Space"},
+			"duration_ms":248440,
+			"uri":"spotify:track:1"
+		}}]}}`
+
+		playlist, err := ParseSpotify(strings.NewReader(json))
+		require.NoError(t, err)
+
+		assert.Equal(t, &Playlist{
+			Title: "Road trip",
+			Entries: []Entry{{
+				Position: 1,
+				Title:    "Under Pressure",
+				Artists:  []string{"Queen", "David Bowie"},
+				Album:    "Hot Space",
+				Duration: 248440 * time.Millisecond,
+				Location: "spotify:track:1",
+			}},
+		}, playlist)
+	})
+
+	t.Run("account data", func(t *testing.T) {
+		json := `{"playlists":[
+			{"name":"First","items":[{"track":{"trackName":"Yesterday","artistName":"The Beatles","albumName":"Help!","trackUri":"spotify:track:2"}}]},
+			{"name":"Second","items":[{"track":null},{"track":{"trackName":"Help!","artistName":"The Beatles"}}]}
+		]}`
+
+		playlists, err := ParseSpotifyAll(strings.NewReader(json))
+		require.NoError(t, err)
+
+		require.Len(t, playlists, 2)
+		assert.Equal(t, "First", playlists[0].Title)
+		assert.Equal(t, []Entry{{
+			Position: 1,
+			Title:    "Yesterday",
+			Artists:  []string{"The Beatles"},
+			Album:    "Help!",
+			Location: "spotify:track:2",
+		}}, playlists[0].Entries)
+		assert.Equal(t, "Second", playlists[1].Title)
+		require.Len(t, playlists[1].Entries, 1)
+		assert.Equal(t, "Help!", playlists[1].Entries[0].Title)
+	})
+}
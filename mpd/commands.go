@@ -0,0 +1,633 @@
+package mpd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// expressionRegexp matches a condition of filter expression like
+// (artist == 'The Beatles').
+var expressionRegexp = regexp.MustCompile(`\(\s*(\w+)\s+(==|!=|contains|=~|!~)\s+(?:'((?:\\.|[^'\\])*)'|"((?:\\.|[^"\\])*)")\s*\)`)
+
+// backslashRegexp matches escaped characters of filter expression values.
+var backslashRegexp = regexp.MustCompile(`\\(.)`)
+
+// filter is a condition of search and find commands.
+type filter struct {
+	tag   string
+	value string
+	// exact requires tag to be equal to value, otherwise to contain it.
+	exact bool
+	// fold ignores case.
+	fold bool
+}
+
+func (s *Server) ping(ctx context.Context, c *conn, args []string, resp *response) error {
+	return nil
+}
+
+// closeConn closes the connection after the response.
+func (s *Server) closeConn(ctx context.Context, c *conn, args []string, resp *response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c.closed = true
+	return nil
+}
+
+func (s *Server) listCommands(ctx context.Context, c *conn, args []string, resp *response) error {
+	names := make([]string, 0, len(s.commands))
+	for name := range s.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		resp.field("command", name)
+	}
+
+	return nil
+}
+
+func (s *Server) status(ctx context.Context, c *conn, args []string, resp *response) error {
+	st := s.player.status()
+
+	resp.field("repeat", false)
+	resp.field("random", false)
+	resp.field("single", false)
+	resp.field("consume", false)
+	resp.field("playlist", st.version)
+	resp.field("playlistlength", st.length)
+	resp.field("state", st.state)
+
+	if st.current != nil {
+		resp.field("song", st.pos)
+		resp.field("songid", st.current.id)
+
+		if st.state != stateStop {
+			duration := st.current.song.Duration
+			resp.field("time", fmt.Sprintf("%d:%d", int(st.elapsed.Seconds()), int(duration.Seconds())))
+			resp.field("elapsed", st.elapsed)
+			resp.field("duration", duration)
+		}
+	}
+
+	if st.err != "" {
+		resp.field("error", st.err)
+	}
+
+	return nil
+}
+
+func (s *Server) currentSong(ctx context.Context, c *conn, args []string, resp *response) error {
+	st := s.player.status()
+	if st.current != nil {
+		writeEntry(resp, st.pos, *st.current)
+	}
+
+	return nil
+}
+
+// playlistInfo lists songs of the queue or the song at position.
+func (s *Server) playlistInfo(ctx context.Context, c *conn, args []string, resp *response) error {
+	entries := s.player.entries()
+
+	if len(args) > 0 {
+		pos, err := intArg(args, 0)
+		if err != nil {
+			return err
+		}
+		if pos < 0 || pos >= len(entries) {
+			return &Error{Code: ErrorArg, Message: "bad song index"}
+		}
+
+		writeEntry(resp, pos, entries[pos])
+		return nil
+	}
+
+	for pos, e := range entries {
+		writeEntry(resp, pos, e)
+	}
+
+	return nil
+}
+
+// add adds songs of track, album or playlist URI to the queue.
+func (s *Server) add(ctx context.Context, c *conn, args []string, resp *response) error {
+	if len(args) != 1 {
+		return &Error{Code: ErrorArg, Message: "wrong number of arguments"}
+	}
+
+	songs, err := s.resolve(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	s.player.add(songs)
+	return nil
+}
+
+// addID adds a track to the queue and returns its ID.
+func (s *Server) addID(ctx context.Context, c *conn, args []string, resp *response) error {
+	if len(args) != 1 {
+		return &Error{Code: ErrorArg, Message: "wrong number of arguments"}
+	}
+	if !strings.HasPrefix(args[0], "tracks/") {
+		return &Error{Code: ErrorArg, Message: "track URI expected"}
+	}
+
+	songs, err := s.resolve(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	ids := s.player.add(songs)
+	resp.field("Id", ids[0])
+	return nil
+}
+
+func (s *Server) clear(ctx context.Context, c *conn, args []string, resp *response) error {
+	s.player.clear()
+	return nil
+}
+
+func (s *Server) delete(ctx context.Context, c *conn, args []string, resp *response) error {
+	pos, err := intArg(args, 0)
+	if err != nil {
+		return err
+	}
+
+	return s.player.delete(pos)
+}
+
+func (s *Server) deleteID(ctx context.Context, c *conn, args []string, resp *response) error {
+	id, err := intArg(args, 0)
+	if err != nil {
+		return err
+	}
+
+	pos, err := s.player.position(id)
+	if err != nil {
+		return err
+	}
+
+	return s.player.delete(pos)
+}
+
+func (s *Server) play(ctx context.Context, c *conn, args []string, resp *response) error {
+	pos := -1
+	if len(args) > 0 {
+		var err error
+		if pos, err = intArg(args, 0); err != nil {
+			return err
+		}
+		if pos < 0 {
+			return &Error{Code: ErrorArg, Message: "bad song index"}
+		}
+	}
+
+	return s.player.play(pos)
+}
+
+func (s *Server) playID(ctx context.Context, c *conn, args []string, resp *response) error {
+	if len(args) == 0 {
+		return s.player.play(-1)
+	}
+
+	id, err := intArg(args, 0)
+	if err != nil {
+		return err
+	}
+
+	pos, err := s.player.position(id)
+	if err != nil {
+		return err
+	}
+
+	return s.player.play(pos)
+}
+
+// pause pauses (1) or resumes (0) playback. Without argument it toggles.
+func (s *Server) pause(ctx context.Context, c *conn, args []string, resp *response) error {
+	if len(args) == 0 {
+		s.player.pause(s.player.status().state == statePlay)
+		return nil
+	}
+
+	switch args[0] {
+	case "0":
+		s.player.pause(false)
+	case "1":
+		s.player.pause(true)
+	default:
+		return &Error{Code: ErrorArg, Message: "boolean (0/1) expected: " + args[0]}
+	}
+
+	return nil
+}
+
+func (s *Server) stop(ctx context.Context, c *conn, args []string, resp *response) error {
+	s.player.stop()
+	return nil
+}
+
+func (s *Server) next(ctx context.Context, c *conn, args []string, resp *response) error {
+	return s.player.skip(1)
+}
+
+func (s *Server) previous(ctx context.Context, c *conn, args []string, resp *response) error {
+	return s.player.skip(-1)
+}
+
+// search searches tracks with tags containing values ignoring case.
+func (s *Server) search(ctx context.Context, c *conn, args []string, resp *response) error {
+	return s.searchTracks(ctx, args, false, resp)
+}
+
+// find searches tracks with tags equal to values.
+func (s *Server) find(ctx context.Context, c *conn, args []string, resp *response) error {
+	return s.searchTracks(ctx, args, true, resp)
+}
+
+// searchTracks searches catalog by all filter values and returns tracks
+// matching filters.
+func (s *Server) searchTracks(ctx context.Context, args []string, exact bool, resp *response) error {
+	filters, err := parseFilters(args, exact)
+	if err != nil {
+		return err
+	}
+
+	values := make([]string, 0, len(filters))
+	for _, f := range filters {
+		values = append(values, f.value)
+	}
+
+	found, httpResp, err := s.client.Search().Tracks(ctx, strings.Join(values, " "), &yamusic.SearchOptions{
+		PageSize: s.opts.SearchLimit,
+	})
+	if err == nil {
+		err = checkResponse(httpResp, found.Error)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, track := range found.Result.Tracks.Results {
+		song := Song{
+			ID:       strconv.Itoa(track.ID),
+			Title:    track.Title,
+			Artist:   track.Artists.Names(),
+			Duration: time.Duration(track.DurationMs) * time.Millisecond,
+		}
+
+		if len(track.Albums) > 0 {
+			album := track.Albums[0]
+			song.AlbumID = album.ID
+			song.Album = album.Title
+			song.Genre = album.Genre
+			song.Year = album.Year
+			song.Track = album.TrackPosition.Index
+		}
+
+		if matchesFilters(song, filters) {
+			writeSong(resp, song)
+		}
+	}
+
+	return nil
+}
+
+// listPlaylists lists playlists of the user.
+func (s *Server) listPlaylists(ctx context.Context, c *conn, args []string, resp *response) error {
+	list, httpResp, err := s.client.Playlists().List(ctx, 0)
+	if err == nil {
+		err = checkResponse(httpResp, list.Error)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, playlist := range list.Result {
+		resp.field("playlist", playlist.Title)
+		if !playlist.Modified.IsZero() {
+			resp.field("Last-Modified", playlist.Modified.UTC().Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) listPlaylistInfo(ctx context.Context, c *conn, args []string, resp *response) error {
+	songs, err := s.playlistSongs(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	for _, song := range songs {
+		writeSong(resp, song)
+	}
+
+	return nil
+}
+
+// load adds songs of the playlist to the queue.
+func (s *Server) load(ctx context.Context, c *conn, args []string, resp *response) error {
+	songs, err := s.playlistSongs(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	s.player.add(songs)
+	return nil
+}
+
+// lsinfo lists playlists at the root or songs of the URI.
+func (s *Server) lsinfo(ctx context.Context, c *conn, args []string, resp *response) error {
+	if len(args) == 0 || args[0] == "" || args[0] == "/" {
+		return s.listPlaylists(ctx, c, args, resp)
+	}
+
+	songs, err := s.resolve(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	for _, song := range songs {
+		writeSong(resp, song)
+	}
+
+	return nil
+}
+
+// idle waits until any of subsystems changes or client sends noidle.
+func (s *Server) idle(ctx context.Context, c *conn, args []string, resp *response) error {
+	for {
+		changed := s.takeChanged(c, args)
+		if len(changed) > 0 {
+			for _, subsystem := range changed {
+				resp.field("changed", subsystem)
+			}
+			return nil
+		}
+
+		select {
+		case <-c.wake:
+		case line, ok := <-c.lines:
+			if !ok {
+				return &Error{Code: ErrorSystem, Message: "connection closed"}
+			}
+			if line != "noidle" {
+				return &Error{Code: ErrorArg, Message: "only noidle is allowed during idle"}
+			}
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// resolve returns songs of URI like "tracks/1", "albums/2" or
+// "playlists/1003".
+func (s *Server) resolve(ctx context.Context, uri string) ([]Song, error) {
+	parts := strings.SplitN(strings.Trim(uri, "/"), "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, &Error{Code: ErrorNoExist, Message: "no such song or directory: " + uri}
+	}
+
+	switch parts[0] {
+	case "tracks":
+		tracks, httpResp, err := s.client.Tracks().GetMany(ctx, []string{parts[1]})
+		if err == nil {
+			err = checkResponse(httpResp, tracks.Error)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(tracks.Result) == 0 {
+			return nil, &Error{Code: ErrorNoExist, Message: "no such song: " + uri}
+		}
+
+		return []Song{newSong(tracks.Result[0])}, nil
+	case "albums":
+		id, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, &Error{Code: ErrorNoExist, Message: "no such album: " + uri}
+		}
+
+		album, httpResp, err := s.client.Albums().GetWithTracks(ctx, id)
+		if err == nil {
+			err = checkResponse(httpResp, album.Error)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var songs []Song
+		for _, volume := range album.Result.Volumes {
+			for _, track := range volume {
+				songs = append(songs, newSong(track))
+			}
+		}
+
+		return songs, nil
+	case "playlists":
+		kind, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, &Error{Code: ErrorNoExist, Message: "no such playlist: " + uri}
+		}
+
+		return s.playlistTracks(ctx, kind)
+	default:
+		return nil, &Error{Code: ErrorNoExist, Message: "no such song or directory: " + uri}
+	}
+}
+
+// playlistSongs returns songs of the playlist by its title.
+func (s *Server) playlistSongs(ctx context.Context, args []string) ([]Song, error) {
+	if len(args) != 1 {
+		return nil, &Error{Code: ErrorArg, Message: "wrong number of arguments"}
+	}
+
+	list, httpResp, err := s.client.Playlists().List(ctx, 0)
+	if err == nil {
+		err = checkResponse(httpResp, list.Error)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, playlist := range list.Result {
+		if playlist.Title == args[0] {
+			return s.playlistTracks(ctx, playlist.Kind)
+		}
+	}
+
+	return nil, &Error{Code: ErrorNoExist, Message: "no such playlist"}
+}
+
+func (s *Server) playlistTracks(ctx context.Context, kind int) ([]Song, error) {
+	playlist, httpResp, err := s.client.Playlists().Get(ctx, 0, kind)
+	if err == nil {
+		err = checkResponse(httpResp, playlist.Error)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	songs := make([]Song, 0, len(playlist.Result.Tracks))
+	for _, track := range playlist.Result.Tracks {
+		songs = append(songs, newSong(track.Track))
+	}
+
+	return songs, nil
+}
+
+// parseFilters parses either filter expression like
+// ((artist == 'Queen') AND (title contains 'love')) or pairs of tags and
+// values.
+func parseFilters(args []string, exact bool) ([]filter, error) {
+	if len(args) == 1 && strings.HasPrefix(args[0], "(") {
+		var filters []filter
+		for _, match := range expressionRegexp.FindAllStringSubmatch(args[0], -1) {
+			value := match[3] + match[4]
+			filters = append(filters, filter{
+				tag:   strings.ToLower(match[1]),
+				value: backslashRegexp.ReplaceAllString(value, "$1"),
+				exact: match[2] == "==",
+				fold:  !exact,
+			})
+		}
+		if len(filters) == 0 {
+			return nil, &Error{Code: ErrorArg, Message: "invalid filter expression"}
+		}
+
+		return filters, checkTags(filters)
+	}
+
+	if len(args) == 0 || len(args)%2 != 0 {
+		return nil, &Error{Code: ErrorArg, Message: "incorrect number of filter arguments"}
+	}
+
+	filters := make([]filter, 0, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		filters = append(filters, filter{
+			tag:   strings.ToLower(args[i]),
+			value: args[i+1],
+			exact: exact,
+			fold:  !exact,
+		})
+	}
+
+	return filters, checkTags(filters)
+}
+
+func checkTags(filters []filter) error {
+	for _, f := range filters {
+		if _, ok := tagValues(Song{}, f.tag); !ok {
+			return &Error{Code: ErrorArg, Message: "unknown filter type: " + f.tag}
+		}
+	}
+
+	return nil
+}
+
+func matchesFilters(song Song, filters []filter) bool {
+	for _, f := range filters {
+		values, _ := tagValues(song, f.tag)
+
+		matched := false
+		for _, value := range values {
+			want := f.value
+			if f.fold {
+				value, want = strings.ToLower(value), strings.ToLower(want)
+			}
+			if (f.exact && value == want) || (!f.exact && strings.Contains(value, want)) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tagValues returns values of the song's tag. Tag "any" matches any of
+// them.
+func tagValues(song Song, tag string) ([]string, bool) {
+	switch tag {
+	case "any":
+		return []string{song.Title, song.Artist, song.Album, song.Genre}, true
+	case "title":
+		return []string{song.Title}, true
+	case "artist", "albumartist":
+		return []string{song.Artist}, true
+	case "album":
+		return []string{song.Album}, true
+	case "genre":
+		return []string{song.Genre}, true
+	case "file":
+		return []string{song.File()}, true
+	case "date":
+		return []string{strconv.Itoa(song.Year)}, true
+	default:
+		return nil, false
+	}
+}
+
+func writeSong(resp *response, song Song) {
+	resp.field("file", song.File())
+	if song.Title != "" {
+		resp.field("Title", song.Title)
+	}
+	if song.Artist != "" {
+		resp.field("Artist", song.Artist)
+	}
+	if song.Album != "" {
+		resp.field("Album", song.Album)
+	}
+	if song.Genre != "" {
+		resp.field("Genre", song.Genre)
+	}
+	if song.Year != 0 {
+		resp.field("Date", song.Year)
+	}
+	if song.Track != 0 {
+		resp.field("Track", song.Track)
+	}
+	resp.field("Time", int(song.Duration.Seconds()))
+	resp.field("duration", song.Duration)
+}
+
+func writeEntry(resp *response, pos int, e entry) {
+	writeSong(resp, e.song)
+	resp.field("Pos", pos)
+	resp.field("Id", e.id)
+}
+
+// checkResponse converts error of Yandex.Music API to MPD error.
+func checkResponse(resp *http.Response, apiErr yamusic.Error) error {
+	var respErr *yamusic.ResponseError
+	if err := yamusic.CheckResponse(resp, apiErr); !errors.As(err, &respErr) {
+		return err
+	}
+
+	code := ErrorSystem
+	if respErr.StatusCode == http.StatusNotFound {
+		code = ErrorNoExist
+	}
+
+	return &Error{Code: code, Message: respErr.Error()}
+}
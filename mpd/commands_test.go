@@ -0,0 +1,129 @@
+package mpd
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// searchResults are tracks found by the fake API for any query.
+const searchResults = `{"result":{"tracks":{"results":[
+	{"id":1,"title":"Yesterday","durationMs":125000,"artists":[{"name":"The Beatles"}],
+	 "albums":[{"id":20,"title":"Help!","year":1965,"genre":"rock","trackPosition":{"index":13}}]},
+	{"id":2,"title":"Yesterday Once More","durationMs":230000,"artists":[{"name":"Carpenters"}],
+	 "albums":[{"id":30,"title":"Now & Then","year":1973}]}
+]}}}`
+
+func TestServer_Search(t *testing.T) {
+	mux, _, _, addr := setup(t)
+	c := dial(t, addr)
+
+	var queries []string
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "track", r.URL.Query().Get("type"))
+		assert.Equal(t, "50", r.URL.Query().Get("page-size"))
+		queries = append(queries, r.URL.Query().Get("text"))
+		fmt.Fprint(w, searchResults)
+	})
+
+	assert.Equal(t, []string{
+		"file: tracks/1",
+		"Title: Yesterday",
+		"Artist: The Beatles",
+		"Album: Help!",
+		"Genre: rock",
+		"Date: 1965",
+		"Track: 13",
+		"Time: 125",
+		"duration: 125.000",
+		"file: tracks/2",
+		"Title: Yesterday Once More",
+		"Artist: Carpenters",
+		"Album: Now & Then",
+		"Date: 1973",
+		"Time: 230",
+		"duration: 230.000",
+	}, c.send("search any yesterday"))
+
+	assert.Equal(t, []string{"tracks/1"}, fields(c.send(`search title yesterday artist beatles`), "file"))
+	assert.Equal(t, []string{"tracks/1"}, fields(c.send(`find title Yesterday`), "file"))
+	assert.Empty(t, c.send(`find title yesterday`))
+	assert.Equal(t, []string{"tracks/2"}, fields(c.send(`search "(title contains 'once')"`), "file"))
+	assert.Equal(t, []string{"tracks/1"},
+		fields(c.send(`find "((artist == 'The Beatles') AND (album == \"Help!\"))"`), "file"))
+
+	assert.Equal(t, []string{
+		"yesterday",
+		"yesterday beatles",
+		"Yesterday",
+		"yesterday",
+		"once",
+		"The Beatles Help!",
+	}, queries)
+
+	assert.Equal(t, []string{"ACK [2@0] {search} incorrect number of filter arguments"}, c.send("search title"))
+	assert.Equal(t, []string{"ACK [2@0] {find} unknown filter type: mood"}, c.send("find mood happy"))
+}
+
+func TestServer_Playlists(t *testing.T) {
+	mux, _, _, addr := setup(t)
+	c := dial(t, addr)
+
+	mux.HandleFunc("/users/2000/playlists/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[
+			{"kind":1003,"title":"Road trip","modified":"2020-01-02T03:04:05+03:00"},
+			{"kind":1004,"title":"Chill"}
+		]}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/1003", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"kind":1003,"title":"Road trip","tracks":[
+			{"track":{"id":"1","title":"Yesterday","durationMs":125000}},
+			{"track":{"id":"2","title":"Help!","durationMs":140000}}
+		]}}`)
+	})
+
+	assert.Equal(t, []string{
+		"playlist: Road trip",
+		"Last-Modified: 2020-01-02T00:04:05Z",
+		"playlist: Chill",
+	}, c.send("listplaylists"))
+	assert.Equal(t, c.send("listplaylists"), c.send("lsinfo"))
+
+	assert.Equal(t, []string{"Yesterday", "Help!"}, fields(c.send(`listplaylistinfo "Road trip"`), "Title"))
+	assert.Equal(t, []string{"tracks/1", "tracks/2"}, fields(c.send("lsinfo playlists/1003"), "file"))
+
+	assert.Empty(t, c.send(`load "Road trip"`))
+	resp := c.send("playlistinfo")
+	assert.Equal(t, []string{"tracks/1", "tracks/2"}, fields(resp, "file"))
+	assert.Equal(t, []string{"0", "1"}, fields(resp, "Pos"))
+	assert.Equal(t, []string{"1", "2"}, fields(resp, "Id"))
+	assert.Equal(t, []string{"Help!"}, fields(c.send("playlistinfo 1"), "Title"))
+
+	assert.Equal(t, []string{"ACK [50@0] {load} no such playlist"}, c.send("load Unknown"))
+}
+
+func TestServer_AddAlbum(t *testing.T) {
+	mux, _, _, addr := setup(t)
+	c := dial(t, addr)
+
+	mux.HandleFunc("/albums/20/with-tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"id":20,"title":"Help!","volumes":[
+			[{"id":"1","title":"Help!"},{"id":"2","title":"The Night Before"}],
+			[{"id":"3","title":"Yesterday"}]
+		]}}`)
+	})
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[{"id":"4","title":"Something"}]}`)
+	})
+
+	assert.Empty(t, c.send("add albums/20"))
+	assert.Equal(t, []string{"Id: 4"}, c.send(`addid "tracks/4"`))
+	assert.Equal(t, []string{"ACK [2@0] {addid} track URI expected"}, c.send("addid albums/20"))
+
+	assert.Empty(t, c.send("delete 1"))
+	assert.Empty(t, c.send("deleteid 3"))
+	assert.Equal(t, []string{"Help!", "Something"}, fields(c.send("playlistinfo"), "Title"))
+	assert.Equal(t, []string{"ACK [50@0] {deleteid} no such song"}, c.send("deleteid 3"))
+}
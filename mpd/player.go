@@ -0,0 +1,557 @@
+package mpd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// Player states reported by status command.
+const (
+	stateStop  = "stop"
+	statePlay  = "play"
+	statePause = "pause"
+)
+
+type (
+	// Sink receives audio of played tracks.
+	Sink interface {
+		// Open returns writer for MP3 stream of the song. Writer is closed
+		// when the song ends or playback is stopped.
+		Open(ctx context.Context, song Song) (io.WriteCloser, error)
+	}
+
+	// FileSink writes audio to the file, e.g. FIFO read by an audio player.
+	// Songs are appended one after another.
+	FileSink string
+
+	// Song is a track in the queue or search results.
+	Song struct {
+		ID       string
+		AlbumID  int
+		Title    string
+		Artist   string
+		Album    string
+		Genre    string
+		Year     int
+		Track    int
+		Duration time.Duration
+	}
+
+	// entry is a song in the queue with its ID unique within the queue.
+	entry struct {
+		id   int
+		song Song
+	}
+
+	// playback is a song being streamed to the sink.
+	playback struct {
+		cancel context.CancelFunc
+		// written is count of bytes written to the sink.
+		written int64
+	}
+
+	// player is a queue of songs and state of playback.
+	player struct {
+		client     *yamusic.Client
+		httpClient yamusic.Doer
+		sink       Sink
+		// notify reports changed subsystems to idle clients.
+		notify func(subsystems ...string)
+
+		// sinkMu is held while writing to the sink so that songs don't
+		// overlap when one replaces another.
+		sinkMu sync.Mutex
+
+		mu      sync.Mutex
+		queue   []entry
+		nextID  int
+		version int
+		state   string
+		// current is a position of the current song or -1.
+		current int
+		// offset is a byte position in the current song to resume from.
+		offset int64
+		// elapsed is a play time of the current song before startedAt.
+		elapsed   time.Duration
+		startedAt time.Time
+		playing   *playback
+		err       string
+	}
+)
+
+// DiscardSink discards audio, so that the server can only be used to browse
+// the catalog.
+var DiscardSink Sink = discardSink{}
+
+type discardSink struct{}
+
+func (discardSink) Open(ctx context.Context, song Song) (io.WriteCloser, error) {
+	return nopCloser{io.Discard}, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// Open implements Sink interface.
+func (s FileSink) Open(ctx context.Context, song Song) (io.WriteCloser, error) {
+	return os.OpenFile(string(s), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+}
+
+// File returns URI of the song used by add command.
+func (s Song) File() string {
+	return "tracks/" + s.ID
+}
+
+// newSong converts Yandex.Music track to song.
+func newSong(track yamusic.Track) Song {
+	song := Song{
+		ID:       track.ID,
+		Title:    track.Title,
+		Artist:   track.ArtistNames(),
+		Duration: time.Duration(track.DurationMs) * time.Millisecond,
+	}
+
+	if len(track.Albums) > 0 {
+		album := track.Albums[0]
+		song.AlbumID = album.ID
+		song.Album = album.Title
+		song.Genre = album.Genre
+		song.Year = album.Year
+		song.Track = album.TrackPosition.Index
+	}
+
+	return song
+}
+
+func newPlayer(client *yamusic.Client, httpClient yamusic.Doer, sink Sink, notify func(...string)) *player {
+	return &player{
+		client:     client,
+		httpClient: httpClient,
+		sink:       sink,
+		notify:     notify,
+		state:      stateStop,
+		current:    -1,
+	}
+}
+
+// add appends songs to the queue and returns their IDs.
+func (p *player) add(songs []Song) []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids := make([]int, 0, len(songs))
+	for _, song := range songs {
+		p.nextID++
+		p.queue = append(p.queue, entry{id: p.nextID, song: song})
+		ids = append(ids, p.nextID)
+	}
+
+	p.changedLocked("playlist")
+	return ids
+}
+
+// clear stops playback and removes all songs from the queue.
+func (p *player) clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stopLocked()
+	p.queue = nil
+	p.current = -1
+	p.changedLocked("playlist", "player")
+}
+
+// delete removes song at position from the queue.
+func (p *player) delete(pos int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if pos < 0 || pos >= len(p.queue) {
+		return &Error{Code: ErrorArg, Message: "bad song index"}
+	}
+
+	switch {
+	case pos == p.current:
+		// MPD plays the next song when the current one is deleted.
+		state := p.state
+		p.stopLocked()
+		p.queue = append(p.queue[:pos], p.queue[pos+1:]...)
+		if pos >= len(p.queue) {
+			p.current = -1
+		} else if state == statePlay {
+			p.playLocked(pos, 0, 0)
+		}
+		p.changedLocked("player")
+	case pos < p.current:
+		p.queue = append(p.queue[:pos], p.queue[pos+1:]...)
+		p.current--
+	default:
+		p.queue = append(p.queue[:pos], p.queue[pos+1:]...)
+	}
+
+	p.changedLocked("playlist")
+	return nil
+}
+
+// position returns position of the song by ID.
+func (p *player) position(id int) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for pos, e := range p.queue {
+		if e.id == id {
+			return pos, nil
+		}
+	}
+
+	return 0, &Error{Code: ErrorNoExist, Message: "no such song"}
+}
+
+// play starts playback of the song at position. Negative position resumes
+// paused song or plays the current one.
+func (p *player) play(pos int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if pos < 0 {
+		if p.state == statePause {
+			p.playLocked(p.current, p.offset, p.elapsed)
+			p.changedLocked("player")
+			return nil
+		}
+
+		pos = p.current
+		if pos < 0 {
+			pos = 0
+		}
+	}
+
+	if pos >= len(p.queue) {
+		return &Error{Code: ErrorArg, Message: "bad song index"}
+	}
+
+	p.playLocked(pos, 0, 0)
+	p.changedLocked("player")
+	return nil
+}
+
+// pause pauses or resumes playback.
+func (p *player) pause(pause bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case pause && p.state == statePlay:
+		offset := p.offset + atomic.LoadInt64(&p.playing.written)
+		elapsed := p.elapsedLocked()
+		p.stopLocked()
+		p.state = statePause
+		p.offset = offset
+		p.elapsed = elapsed
+	case !pause && p.state == statePause:
+		p.playLocked(p.current, p.offset, p.elapsed)
+	default:
+		return
+	}
+
+	p.changedLocked("player")
+}
+
+// stop stops playback keeping the current song.
+func (p *player) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stopLocked()
+	p.changedLocked("player")
+}
+
+// skip plays the song at delta positions from the current one. Playback
+// stops if there is no such song.
+func (p *player) skip(delta int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.state == stateStop {
+		return &Error{Code: ErrorArg, Message: "not playing"}
+	}
+
+	pos := p.current + delta
+	if pos < 0 || pos >= len(p.queue) {
+		p.stopLocked()
+	} else {
+		p.playLocked(pos, 0, 0)
+	}
+
+	p.changedLocked("player")
+	return nil
+}
+
+// close stops playback.
+func (p *player) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stopLocked()
+}
+
+// status is a snapshot of the player.
+type status struct {
+	version int
+	length  int
+	state   string
+	pos     int
+	current *entry
+	elapsed time.Duration
+	err     string
+}
+
+func (p *player) status() status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s := status{
+		version: p.version,
+		length:  len(p.queue),
+		state:   p.state,
+		pos:     p.current,
+		elapsed: p.elapsedLocked(),
+		err:     p.err,
+	}
+	if p.current >= 0 {
+		current := p.queue[p.current]
+		s.current = &current
+	}
+
+	return s
+}
+
+// entries returns copy of the queue.
+func (p *player) entries() []entry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]entry(nil), p.queue...)
+}
+
+// playLocked starts streaming of the song at position from byte offset.
+// Elapsed is a time already played for resumed song.
+func (p *player) playLocked(pos int, offset int64, elapsed time.Duration) {
+	p.stopLocked()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pb := &playback{cancel: cancel}
+
+	p.playing = pb
+	p.state = statePlay
+	p.current = pos
+	p.offset = offset
+	p.elapsed = elapsed
+	p.startedAt = time.Now()
+	p.err = ""
+
+	song := p.queue[pos].song
+	go func() {
+		err := p.stream(ctx, pb, song, offset, elapsed)
+		p.finished(pb, err)
+	}()
+}
+
+// stopLocked cancels streaming of the current song.
+func (p *player) stopLocked() {
+	if p.playing != nil {
+		p.playing.cancel()
+		p.playing = nil
+	}
+
+	p.state = stateStop
+	p.offset = 0
+	p.elapsed = 0
+}
+
+// finished plays the next song when streaming of the song ends.
+func (p *player) finished(pb *playback, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Song was stopped or replaced with another one.
+	if p.playing != pb {
+		return
+	}
+
+	p.playing = nil
+	switch {
+	case err != nil:
+		p.stopLocked()
+		p.err = err.Error()
+	case p.current+1 < len(p.queue):
+		p.playLocked(p.current+1, 0, 0)
+	default:
+		p.stopLocked()
+		p.current = -1
+	}
+
+	p.changedLocked("player")
+}
+
+// stream downloads the song and writes it to the sink. Writes are paced
+// so that the song takes its duration to stream like in a real player.
+func (p *player) stream(ctx context.Context, pb *playback, song Song, offset int64, elapsed time.Duration) error {
+	id, err := strconv.Atoi(song.ID)
+	if err != nil {
+		return fmt.Errorf("invalid track id %q", song.ID)
+	}
+
+	downloadURL, err := p.client.Tracks().GetDownloadURL(ctx, id)
+	if err != nil {
+		return fmt.Errorf("track %d: %w", id, err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := p.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return p.canceled(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// Range isn't supported, skip already played bytes.
+		if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil {
+			return p.canceled(ctx, err)
+		}
+	default:
+		return fmt.Errorf("track %d: %s", id, resp.Status)
+	}
+
+	// Size of the whole song is needed to pace writes.
+	var size int64
+	if resp.ContentLength > 0 {
+		size = offset + resp.ContentLength
+	}
+
+	p.sinkMu.Lock()
+	defer p.sinkMu.Unlock()
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	w, err := p.sink.Open(ctx, song)
+	if err != nil {
+		return err
+	}
+
+	// Body is copied in chunks, otherwise it may be written at once
+	// without pacing.
+	_, err = io.CopyBuffer(&pacedWriter{
+		ctx:      ctx,
+		w:        w,
+		pb:       pb,
+		start:    time.Now(),
+		offset:   offset,
+		size:     size,
+		duration: song.Duration,
+		elapsed:  elapsed,
+	}, struct{ io.Reader }{body}, make([]byte, 32*1024))
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+
+	return p.canceled(ctx, err)
+}
+
+// canceled ignores error caused by stopped playback.
+func (p *player) canceled(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	return err
+}
+
+func (p *player) elapsedLocked() time.Duration {
+	if p.current < 0 || p.state == stateStop {
+		return 0
+	}
+
+	elapsed := p.elapsed
+	if p.state == statePlay {
+		elapsed += time.Since(p.startedAt)
+	}
+	if duration := p.queue[p.current].song.Duration; duration > 0 && elapsed > duration {
+		elapsed = duration
+	}
+
+	return elapsed
+}
+
+func (p *player) changedLocked(subsystems ...string) {
+	for _, subsystem := range subsystems {
+		if subsystem == "playlist" {
+			p.version++
+		}
+	}
+
+	if p.notify != nil {
+		p.notify(subsystems...)
+	}
+}
+
+// pacedWriter writes song no faster than it's played.
+type pacedWriter struct {
+	ctx      context.Context
+	w        io.Writer
+	pb       *playback
+	start    time.Time
+	offset   int64
+	size     int64
+	duration time.Duration
+	elapsed  time.Duration
+}
+
+func (w *pacedWriter) Write(b []byte) (int, error) {
+	n, err := w.w.Write(b)
+	written := atomic.AddInt64(&w.pb.written, int64(n))
+	if err != nil || w.size <= 0 || w.duration <= 0 {
+		return n, err
+	}
+
+	// Position of written bytes in the song minus time played before.
+	position := time.Duration(float64(w.duration) * float64(w.offset+written) / float64(w.size))
+	wait := position - w.elapsed - time.Since(w.start)
+	if wait <= 0 {
+		return n, nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return n, nil
+	case <-w.ctx.Done():
+		return n, w.ctx.Err()
+	}
+}
@@ -0,0 +1,159 @@
+package mpd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chunk is a size of chunks written to the sink.
+const chunk = 32 * 1024
+
+// eventually waits until condition is true.
+func eventually(t *testing.T, condition func() bool, msg string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			require.FailNow(t, "condition not met", msg)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// handleTracks serves tracks with IDs and durations.
+func handleTracks(mux *http.ServeMux, durations map[string]int) {
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		id := r.FormValue("track-ids")
+		fmt.Fprintf(w, `{"result":[{"id":%q,"title":"Song %s","durationMs":%d}]}`, id, id, durations[id])
+	})
+}
+
+func TestPlayer_PlayQueue(t *testing.T) {
+	mux, files, sink, addr := setup(t)
+	c := dial(t, addr)
+
+	handleTracks(mux, nil)
+	serveTrack(mux, files, 1, "first song")
+	serveTrack(mux, files, 2, "second song")
+
+	assert.Empty(t, c.send("add tracks/1"))
+	assert.Empty(t, c.send("add tracks/2"))
+	assert.Empty(t, c.send("play"))
+
+	// Songs without duration are streamed as fast as possible and the
+	// player stops at the end of the queue.
+	eventually(t, func() bool {
+		return fields(c.send("status"), "state")[0] == stateStop
+	}, "queue is played")
+
+	assert.Equal(t, []string{"1", "2"}, sink.songs())
+	assert.Equal(t, "first song", sink.data("1"))
+	assert.Equal(t, "second song", sink.data("2"))
+	assert.Empty(t, fields(c.send("status"), "song"))
+}
+
+func TestPlayer_PauseResume(t *testing.T) {
+	mux, files, sink, addr := setup(t)
+	c := dial(t, addr)
+
+	// Four chunks played for 4 seconds, so that the second one is written
+	// after a second.
+	content := strings.Repeat("a", chunk) + strings.Repeat("b", chunk) + strings.Repeat("c", 2*chunk)
+	handleTracks(mux, map[string]int{"1": 4000})
+	serveTrack(mux, files, 1, content)
+
+	var (
+		mu     sync.Mutex
+		ranges []string
+	)
+	for path, handler := range files.files {
+		handler := handler
+		files.handle(path, func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			ranges = append(ranges, r.Header.Get("Range"))
+			mu.Unlock()
+			handler(w, r)
+		})
+	}
+
+	assert.Empty(t, c.send("add tracks/1"))
+	assert.Empty(t, c.send("play 0"))
+	eventually(t, func() bool { return len(sink.data("1")) == chunk }, "first chunk is written")
+
+	status := c.send("status")
+	assert.Equal(t, []string{"play"}, fields(status, "state"))
+	assert.Equal(t, []string{"0"}, fields(status, "song"))
+	assert.Equal(t, []string{"1"}, fields(status, "songid"))
+	assert.Equal(t, []string{"4.000"}, fields(status, "duration"))
+	assert.Equal(t, []string{"Song 1"}, fields(c.send("currentsong"), "Title"))
+
+	assert.Empty(t, c.send("pause"))
+	assert.Equal(t, []string{"pause"}, fields(c.send("status"), "state"))
+
+	// Playback is resumed from the next byte.
+	assert.Empty(t, c.send("pause 0"))
+	eventually(t, func() bool { return len(sink.data("1")) == 2*chunk }, "second chunk is written")
+	assert.Equal(t, content[:2*chunk], sink.data("1"))
+
+	mu.Lock()
+	assert.Equal(t, []string{"", fmt.Sprintf("bytes=%d-", chunk)}, ranges)
+	mu.Unlock()
+
+	assert.Empty(t, c.send("stop"))
+	status = c.send("status")
+	assert.Equal(t, []string{"stop"}, fields(status, "state"))
+	assert.Equal(t, []string{"0"}, fields(status, "song"))
+	assert.Empty(t, fields(status, "elapsed"))
+}
+
+func TestPlayer_Next(t *testing.T) {
+	mux, files, sink, addr := setup(t)
+	c := dial(t, addr)
+
+	handleTracks(mux, map[string]int{"1": 60000, "2": 60000})
+	serveTrack(mux, files, 1, strings.Repeat("1", 4*chunk))
+	serveTrack(mux, files, 2, strings.Repeat("2", 4*chunk))
+
+	assert.Empty(t, c.send("add tracks/1"))
+	assert.Empty(t, c.send("add tracks/2"))
+	assert.Empty(t, c.send("playid 1"))
+	eventually(t, func() bool { return len(sink.songs()) == 1 }, "first song is played")
+
+	assert.Empty(t, c.send("next"))
+	eventually(t, func() bool { return len(sink.songs()) == 2 }, "second song is played")
+	assert.Equal(t, []string{"1"}, fields(c.send("status"), "song"))
+
+	assert.Empty(t, c.send("previous"))
+	eventually(t, func() bool { return len(sink.songs()) == 3 }, "first song is played again")
+	assert.Equal(t, []string{"1", "2", "1"}, sink.songs())
+
+	assert.Empty(t, c.send("previous"))
+	assert.Equal(t, []string{"stop"}, fields(c.send("status"), "state"))
+}
+
+func TestPlayer_Error(t *testing.T) {
+	mux, _, sink, addr := setup(t)
+	c := dial(t, addr)
+
+	handleTracks(mux, nil)
+
+	assert.Empty(t, c.send("add tracks/1"))
+	assert.Empty(t, c.send("play"))
+
+	eventually(t, func() bool {
+		return len(fields(c.send("status"), "error")) > 0
+	}, "error is reported")
+
+	status := c.send("status")
+	assert.Equal(t, []string{"stop"}, fields(status, "state"))
+	assert.Contains(t, fields(status, "error")[0], "track 1:")
+	assert.Empty(t, sink.songs())
+}
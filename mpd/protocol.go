@@ -0,0 +1,130 @@
+package mpd
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProtocolVersion is a version of MPD protocol implemented by the server.
+const ProtocolVersion = "0.23.0"
+
+// Error codes of MPD protocol.
+const (
+	ErrorArg     = 2
+	ErrorUnknown = 5
+	ErrorNoExist = 50
+	ErrorSystem  = 52
+)
+
+// Error is an error of a command sent to the client as ACK.
+type Error struct {
+	Code    int
+	Message string
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ack formats error of the command at position index of command list.
+func ack(err error, index int, command string) string {
+	code := ErrorSystem
+	if protoErr, ok := err.(*Error); ok {
+		code = protoErr.Code
+	}
+
+	// Message must be on one line.
+	message := strings.Replace(err.Error(), "\n", " ", -1)
+	return fmt.Sprintf("ACK [%d@%d] {%s} %s\n", code, index, command, message)
+}
+
+// parseLine splits command line to the command and its arguments. Arguments
+// with spaces are quoted with double quotes, quotes and backslashes inside
+// them are escaped with backslash.
+func parseLine(line string) ([]string, error) {
+	var (
+		args   []string
+		arg    strings.Builder
+		inArg  bool
+		quoted bool
+	)
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quoted && c == '\\':
+			i++
+			if i == len(line) {
+				return nil, &Error{Code: ErrorArg, Message: "incomplete escape sequence"}
+			}
+			arg.WriteByte(line[i])
+		case quoted && c == '"':
+			quoted = false
+			args = append(args, arg.String())
+			arg.Reset()
+			inArg = false
+		case quoted:
+			arg.WriteByte(c)
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		case c == '"' && !inArg:
+			quoted = true
+			inArg = true
+		default:
+			arg.WriteByte(c)
+			inArg = true
+		}
+	}
+
+	if quoted {
+		return nil, &Error{Code: ErrorArg, Message: "missing closing quote"}
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+
+	return args, nil
+}
+
+// response is an output of a command: lines of "key: value" pairs.
+type response struct {
+	buf bytes.Buffer
+}
+
+// field adds "key: value" line to the response.
+func (r *response) field(key string, value interface{}) {
+	switch v := value.(type) {
+	case time.Duration:
+		// Durations are seconds with milliseconds.
+		value = strconv.FormatFloat(v.Seconds(), 'f', 3, 64)
+	case bool:
+		value = 0
+		if v {
+			value = 1
+		}
+	}
+
+	fmt.Fprintf(&r.buf, "%s: %v\n", key, value)
+}
+
+// intArg parses integer argument of the command.
+func intArg(args []string, i int) (int, error) {
+	if i >= len(args) {
+		return 0, &Error{Code: ErrorArg, Message: "missing argument"}
+	}
+
+	n, err := strconv.Atoi(args[i])
+	if err != nil {
+		return 0, &Error{Code: ErrorArg, Message: fmt.Sprintf("integer expected: %s", args[i])}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,43 @@
+package mpd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line string
+		args []string
+		err  string
+	}{
+		{line: "status", args: []string{"status"}},
+		{line: "  play\t 1 ", args: []string{"play", "1"}},
+		{line: `search artist "The Beatles"`, args: []string{"search", "artist", "The Beatles"}},
+		{line: `find title "Say \"Hello\" \\ Goodbye"`, args: []string{"find", "title", `Say "Hello" \ Goodbye`}},
+		{line: `load ""`, args: []string{"load", ""}},
+		{line: "", args: nil},
+		{line: `add "tracks/1`, err: "missing closing quote"},
+		{line: `add "tracks/1\`, err: "incomplete escape sequence"},
+	}
+
+	for _, tt := range tests {
+		args, err := parseLine(tt.line)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, tt.line)
+			continue
+		}
+
+		assert.NoError(t, err, tt.line)
+		assert.Equal(t, tt.args, args, tt.line)
+	}
+}
+
+func TestAck(t *testing.T) {
+	assert.Equal(t, "ACK [50@2] {load} no such playlist\n",
+		ack(&Error{Code: ErrorNoExist, Message: "no such playlist"}, 2, "load"))
+	assert.Equal(t, "ACK [52@0] {add} 500 Internal Server Error line two\n",
+		ack(errors.New("500 Internal Server Error\nline two"), 0, "add"))
+}
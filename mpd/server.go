@@ -0,0 +1,335 @@
+// Package mpd implements a subset of Music Player Daemon protocol backed by
+// Yandex.Music, so that MPD clients like mpc or ncmpcpp can browse and
+// queue tracks.
+//
+// Database is the Yandex.Music catalog: search and find commands search
+// tracks and stored playlists are playlists of the user. Songs are
+// addressed by URIs like "tracks/10994777" and "albums/1193829". Played
+// songs are streamed to a Sink, e.g. FIFO read by an audio player.
+package mpd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// maxLineLength is a maximum length of a command line.
+const maxLineLength = 64 * 1024
+
+type (
+	// Options are options of Server.
+	Options struct {
+		// Sink receives audio of played songs. Defaults to DiscardSink.
+		Sink Sink
+		// HTTPClient downloads songs. Defaults to http.DefaultClient.
+		HTTPClient yamusic.Doer
+		// SearchLimit is a maximum number of songs found by search and
+		// find commands. Defaults to 50.
+		SearchLimit int
+	}
+
+	// Server serves MPD protocol on accepted connections. All connections
+	// share the same queue and player.
+	Server struct {
+		client   *yamusic.Client
+		opts     Options
+		player   *player
+		commands map[string]command
+
+		mu    sync.Mutex
+		conns map[*conn]struct{}
+	}
+
+	command func(ctx context.Context, c *conn, args []string, resp *response) error
+
+	// conn is a client connection.
+	conn struct {
+		rw    net.Conn
+		lines chan string
+		// done is closed when the connection is served.
+		done chan struct{}
+		// wake is signaled when subsystems change.
+		wake chan struct{}
+
+		// changed are subsystems changed since the last idle. It's guarded
+		// by Server.mu.
+		changed map[string]bool
+		closed  bool
+	}
+)
+
+// NewServer returns server which plays songs of the client's catalog.
+func NewServer(client *yamusic.Client, opts *Options) *Server {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	s := &Server{client: client, opts: *opts, conns: map[*conn]struct{}{}}
+	if s.opts.Sink == nil {
+		s.opts.Sink = DiscardSink
+	}
+	if s.opts.HTTPClient == nil {
+		s.opts.HTTPClient = http.DefaultClient
+	}
+	if s.opts.SearchLimit <= 0 {
+		s.opts.SearchLimit = 50
+	}
+
+	s.player = newPlayer(client, s.opts.HTTPClient, s.opts.Sink, s.notify)
+	s.commands = map[string]command{
+		"ping":             s.ping,
+		"close":            s.closeConn,
+		"commands":         s.listCommands,
+		"status":           s.status,
+		"currentsong":      s.currentSong,
+		"playlistinfo":     s.playlistInfo,
+		"add":              s.add,
+		"addid":            s.addID,
+		"clear":            s.clear,
+		"delete":           s.delete,
+		"deleteid":         s.deleteID,
+		"play":             s.play,
+		"playid":           s.playID,
+		"pause":            s.pause,
+		"stop":             s.stop,
+		"next":             s.next,
+		"previous":         s.previous,
+		"search":           s.search,
+		"find":             s.find,
+		"listplaylists":    s.listPlaylists,
+		"listplaylistinfo": s.listPlaylistInfo,
+		"load":             s.load,
+		"lsinfo":           s.lsinfo,
+		"idle":             s.idle,
+		"noidle":           s.ping,
+	}
+
+	return s
+}
+
+// Serve accepts connections on the listener until ctx is done. It closes
+// the listener and all connections and stops playback before returning.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer func() {
+		s.mu.Lock()
+		for c := range s.conns {
+			c.rw.Close()
+		}
+		s.mu.Unlock()
+
+		wg.Wait()
+		s.player.close()
+	}()
+
+	for {
+		rw, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		c := s.newConn(rw)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveConn(ctx, c)
+		}()
+	}
+}
+
+func (s *Server) newConn(rw net.Conn) *conn {
+	c := &conn{
+		rw:      rw,
+		lines:   make(chan string),
+		done:    make(chan struct{}),
+		wake:    make(chan struct{}, 1),
+		changed: map[string]bool{},
+	}
+
+	s.mu.Lock()
+	s.conns[c] = struct{}{}
+	s.mu.Unlock()
+
+	return c
+}
+
+// serveConn reads commands of the connection and writes responses.
+func (s *Server) serveConn(ctx context.Context, c *conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		close(c.done)
+		c.rw.Close()
+	}()
+
+	go c.readLines()
+
+	w := bufio.NewWriter(c.rw)
+	fmt.Fprintf(w, "OK MPD %s\n", ProtocolVersion)
+	if w.Flush() != nil {
+		return
+	}
+
+	for line := range c.lines {
+		if err := s.handle(ctx, c, w, line); err != nil {
+			return
+		}
+		if w.Flush() != nil || s.isClosed(c) {
+			return
+		}
+	}
+}
+
+// readLines sends lines read from the connection to lines channel until
+// the connection is closed.
+func (c *conn) readLines() {
+	defer close(c.lines)
+
+	scanner := bufio.NewScanner(c.rw)
+	scanner.Buffer(make([]byte, 4096), maxLineLength)
+	for scanner.Scan() {
+		select {
+		case c.lines <- strings.TrimSuffix(scanner.Text(), "\r"):
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// handle runs a command or a command list started by the line.
+func (s *Server) handle(ctx context.Context, c *conn, w io.Writer, line string) error {
+	var (
+		list []string
+		ok   bool
+	)
+
+	switch line {
+	case "command_list_begin", "command_list_ok_begin":
+		ok = line == "command_list_ok_begin"
+		for l := range c.lines {
+			if l == "command_list_end" {
+				break
+			}
+			list = append(list, l)
+		}
+	default:
+		list = []string{line}
+	}
+
+	for i, l := range list {
+		args, err := parseLine(l)
+		if err != nil {
+			_, err = io.WriteString(w, ack(err, i, ""))
+			return err
+		}
+		if len(args) == 0 {
+			_, err = io.WriteString(w, ack(&Error{Code: ErrorUnknown, Message: "no command given"}, i, ""))
+			return err
+		}
+
+		cmd, found := s.commands[args[0]]
+		if !found {
+			_, err = io.WriteString(w, ack(&Error{Code: ErrorUnknown, Message: fmt.Sprintf("unknown command %q", args[0])}, i, ""))
+			return err
+		}
+
+		resp := &response{}
+		if err := cmd(ctx, c, args[1:], resp); err != nil {
+			_, err = io.WriteString(w, ack(err, i, args[0]))
+			return err
+		}
+
+		if _, err := w.Write(resp.buf.Bytes()); err != nil {
+			return err
+		}
+		if ok {
+			if _, err := io.WriteString(w, "list_OK\n"); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Connection is closed without response to close command.
+	if s.isClosed(c) {
+		return nil
+	}
+
+	_, err := io.WriteString(w, "OK\n")
+	return err
+}
+
+// notify marks subsystems changed for all connections and wakes idle ones.
+func (s *Server) notify(subsystems ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.conns {
+		for _, subsystem := range subsystems {
+			c.changed[subsystem] = true
+		}
+
+		select {
+		case c.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// takeChanged returns changed subsystems matching filter and resets them.
+func (s *Server) takeChanged(c *conn, filter []string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var changed []string
+	for _, subsystem := range []string{"database", "stored_playlist", "playlist", "player"} {
+		if !c.changed[subsystem] || !matches(filter, subsystem) {
+			continue
+		}
+
+		changed = append(changed, subsystem)
+		delete(c.changed, subsystem)
+	}
+
+	return changed
+}
+
+func (s *Server) isClosed(c *conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return c.closed
+}
+
+func matches(filter []string, subsystem string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+
+	for _, f := range filter {
+		if f == subsystem {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,302 @@
+package mpd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storage serves requests of track files outside of the fake API.
+type storage struct {
+	mu    sync.Mutex
+	files map[string]http.HandlerFunc
+}
+
+func (s *storage) handle(path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files[path] = handler
+}
+
+func (s *storage) Do(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	handler, ok := s.files[req.URL.Host+req.URL.Path]
+	s.mu.Unlock()
+	if !ok {
+		handler = http.NotFound
+	}
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, req)
+	return recorder.Result(), nil
+}
+
+// recordingSink stores audio of played songs.
+type recordingSink struct {
+	mu     sync.Mutex
+	played []string
+	audio  map[string]*bytes.Buffer
+}
+
+func (s *recordingSink) Open(ctx context.Context, song Song) (io.WriteCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.played = append(s.played, song.ID)
+	if s.audio[song.ID] == nil {
+		s.audio[song.ID] = &bytes.Buffer{}
+	}
+
+	return &sinkWriter{sink: s, buf: s.audio[song.ID]}, nil
+}
+
+func (s *recordingSink) songs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.played...)
+}
+
+func (s *recordingSink) data(id string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.audio[id] == nil {
+		return ""
+	}
+	return s.audio[id].String()
+}
+
+type sinkWriter struct {
+	sink *recordingSink
+	buf  *bytes.Buffer
+}
+
+func (w *sinkWriter) Write(b []byte) (int, error) {
+	w.sink.mu.Lock()
+	defer w.sink.mu.Unlock()
+
+	return w.buf.Write(b)
+}
+
+func (w *sinkWriter) Close() error { return nil }
+
+// setup returns mux of fake Yandex.Music API, storage of track files,
+// sink of played songs and address of MPD server.
+func setup(t *testing.T) (*http.ServeMux, *storage, *recordingSink, string) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	api := httptest.NewServer(mux)
+	t.Cleanup(api.Close)
+
+	baseURL, _ := url.Parse(api.URL + "/")
+	client := yamusic.NewClient(yamusic.BaseURL(baseURL), yamusic.AccessToken(2000, "accessToken"))
+
+	files := &storage{files: map[string]http.HandlerFunc{}}
+	sink := &recordingSink{audio: map[string]*bytes.Buffer{}}
+	server := NewServer(client, &Options{Sink: sink, HTTPClient: files})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(ctx, listener) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	return mux, files, sink, listener.Addr().String()
+}
+
+// serveTrack makes track available for streaming with content.
+func serveTrack(mux *http.ServeMux, files *storage, id int, content string) {
+	mux.HandleFunc(fmt.Sprintf("/tracks/%d/download-info", id), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"result":[{"codec":"mp3","downloadInfoUrl":"download-info/%d"}]}`, id)
+	})
+	mux.HandleFunc(fmt.Sprintf("/download-info/%d", id), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<download-info><host>storage.test</host><path>/music/%d.mp3</path><ts>5e0d</ts><s>salt</s></download-info>`, id)
+	})
+
+	path := fmt.Sprintf("music/%d.mp3", id)
+	sign := md5.Sum([]byte("XGRlBW9FXlekgbPrRHuSiA" + path + "salt"))
+	files.handle("storage.test/get-mp3/"+hex.EncodeToString(sign[:])+"/5e0d/"+path, func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, path, time.Time{}, strings.NewReader(content))
+	})
+}
+
+// client is a connection to MPD server.
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dial(t *testing.T, addr string) *client {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	c := &client{t: t, conn: conn, r: bufio.NewReader(conn)}
+	assert.Equal(t, "OK MPD "+ProtocolVersion, c.readLine())
+	return c
+}
+
+func (c *client) readLine() string {
+	c.t.Helper()
+
+	require.NoError(c.t, c.conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	line, err := c.r.ReadString('\n')
+	require.NoError(c.t, err)
+	return strings.TrimSuffix(line, "\n")
+}
+
+// send sends lines and returns response lines up to OK, which is not
+// included, or ACK.
+func (c *client) send(lines ...string) []string {
+	c.t.Helper()
+
+	_, err := io.WriteString(c.conn, strings.Join(lines, "\n")+"\n")
+	require.NoError(c.t, err)
+
+	return c.response()
+}
+
+func (c *client) response() []string {
+	c.t.Helper()
+
+	var resp []string
+	for {
+		line := c.readLine()
+		if line == "OK" {
+			return resp
+		}
+
+		resp = append(resp, line)
+		if strings.HasPrefix(line, "ACK ") {
+			return resp
+		}
+	}
+}
+
+// fields returns values of the key in response.
+func fields(resp []string, key string) []string {
+	var values []string
+	for _, line := range resp {
+		if strings.HasPrefix(line, key+": ") {
+			values = append(values, strings.TrimPrefix(line, key+": "))
+		}
+	}
+
+	return values
+}
+
+func TestServer_Status(t *testing.T) {
+	_, _, _, addr := setup(t)
+	c := dial(t, addr)
+
+	assert.Equal(t, []string{
+		"repeat: 0",
+		"random: 0",
+		"single: 0",
+		"consume: 0",
+		"playlist: 0",
+		"playlistlength: 0",
+		"state: stop",
+	}, c.send("status"))
+
+	assert.Empty(t, c.send("ping"))
+	assert.Empty(t, c.send("currentsong"))
+	assert.Contains(t, fields(c.send("commands"), "command"), "search")
+}
+
+func TestServer_Errors(t *testing.T) {
+	_, _, _, addr := setup(t)
+	c := dial(t, addr)
+
+	assert.Equal(t, []string{`ACK [5@0] {} unknown command "volume"`}, c.send("volume 50"))
+	assert.Equal(t, []string{`ACK [2@0] {} missing closing quote`}, c.send(`add "tracks/1`))
+	assert.Equal(t, []string{`ACK [2@0] {play} integer expected: first`}, c.send("play first"))
+	assert.Equal(t, []string{`ACK [2@0] {play} bad song index`}, c.send("play 0"))
+	assert.Equal(t, []string{`ACK [2@0] {next} not playing`}, c.send("next"))
+	assert.Equal(t, []string{`ACK [50@0] {add} no such song or directory: artists/1`}, c.send("add artists/1"))
+
+	// Connection still works after errors.
+	assert.Empty(t, c.send("ping"))
+}
+
+func TestServer_CommandList(t *testing.T) {
+	mux, _, _, addr := setup(t)
+	c := dial(t, addr)
+
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"result":[{"id":%q,"title":"Song %s","durationMs":1000}]}`, r.FormValue("track-ids"), r.FormValue("track-ids"))
+	})
+
+	resp := c.send("command_list_ok_begin", "add tracks/1", "add tracks/2", "status", "command_list_end")
+	assert.Equal(t, []string{"list_OK", "list_OK"}, resp[:2])
+	assert.Equal(t, []string{"2"}, fields(resp, "playlistlength"))
+	assert.Equal(t, "list_OK", resp[len(resp)-1])
+
+	resp = c.send("command_list_begin", "clear", "play 5", "add tracks/3", "command_list_end")
+	assert.Equal(t, []string{"ACK [2@1] {play} bad song index"}, resp)
+
+	// Commands after failed one aren't run.
+	assert.Equal(t, []string{"0"}, fields(c.send("status"), "playlistlength"))
+}
+
+func TestServer_Idle(t *testing.T) {
+	mux, _, _, addr := setup(t)
+	idle := dial(t, addr)
+	c := dial(t, addr)
+
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[{"id":"1","title":"Song"}]}`)
+	})
+
+	_, err := io.WriteString(idle.conn, "idle playlist\n")
+	require.NoError(t, err)
+
+	assert.Empty(t, c.send("add tracks/1"))
+	assert.Equal(t, []string{"changed: playlist"}, idle.response())
+
+	// Changes made between idle calls are reported by the next one.
+	assert.Empty(t, c.send("clear"))
+	assert.Equal(t, []string{"changed: playlist", "changed: player"}, idle.send("idle"))
+
+	_, err = io.WriteString(idle.conn, "idle\n")
+	require.NoError(t, err)
+	assert.Empty(t, idle.send("noidle"))
+}
+
+func TestServer_Close(t *testing.T) {
+	_, _, _, addr := setup(t)
+	c := dial(t, addr)
+
+	_, err := io.WriteString(c.conn, "close\n")
+	require.NoError(t, err)
+
+	_, err = c.r.ReadString('\n')
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,219 @@
+package proxy
+
+import (
+	"container/list"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// cacheExt is an extension of cached track files.
+	cacheExt = ".mp3"
+	// tempPrefix is a prefix of temporary files of downloads.
+	tempPrefix = ".yamusic-download-"
+	// staleTempAge is how long temporary file isn't written before it's
+	// considered left by an interrupted download. Files of downloads in
+	// progress, e.g. by another proxy sharing the directory, are younger.
+	staleTempAge = time.Hour
+)
+
+// Cache keeps audio of tracks in a directory and evicts least recently used
+// tracks when total size exceeds the limit. Access time is stored as
+// modification time of the files, so LRU order survives restarts.
+type Cache struct {
+	dir     string
+	maxSize int64
+
+	mu      sync.Mutex
+	size    int64
+	entries map[int]*list.Element
+	lru     *list.List
+}
+
+type cacheItem struct {
+	id   int
+	size int64
+}
+
+// NewCache returns cache in dir which keeps at most maxSize bytes. The
+// directory is created if it doesn't exist, files left from the previous
+// runs are loaded and stale temporary files are removed.
+func NewCache(dir string, maxSize int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	c := &Cache{
+		dir:     dir,
+		maxSize: maxSize,
+		entries: make(map[int]*list.Element),
+		lru:     list.New(),
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type cachedFile struct {
+		item    cacheItem
+		modTime time.Time
+	}
+
+	var cached []cachedFile
+	for _, file := range files {
+		name := file.Name()
+		if strings.HasPrefix(name, tempPrefix) {
+			// Temporary files of interrupted downloads.
+			if info, err := file.Info(); err == nil && time.Since(info.ModTime()) > staleTempAge {
+				os.Remove(filepath.Join(dir, name))
+			}
+			continue
+		}
+		if !strings.HasSuffix(name, cacheExt) {
+			continue
+		}
+
+		id, err := strconv.Atoi(strings.TrimSuffix(name, cacheExt))
+		if err != nil {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+
+		cached = append(cached, cachedFile{item: cacheItem{id: id, size: info.Size()}, modTime: info.ModTime()})
+	}
+
+	// The most recently used are in front.
+	sort.Slice(cached, func(i, j int) bool {
+		return cached[i].modTime.After(cached[j].modTime)
+	})
+
+	for _, file := range cached {
+		item := file.item
+		c.entries[item.id] = c.lru.PushBack(&item)
+		c.size += item.size
+	}
+	c.evictLocked()
+
+	return c, nil
+}
+
+// path returns path of the track's file.
+func (c *Cache) path(id int) string {
+	return filepath.Join(c.dir, strconv.Itoa(id)+cacheExt)
+}
+
+// Open returns file of the cached track and marks it recently used.
+func (c *Cache) Open(id int) (*os.File, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[id]
+	if !ok {
+		return nil, false
+	}
+
+	file, err := os.Open(c.path(id))
+	if err != nil {
+		// File was removed behind our back.
+		c.removeLocked(element)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(element)
+	now := time.Now()
+	os.Chtimes(c.path(id), now, now)
+
+	return file, true
+}
+
+// Create returns temporary file for the track's audio. It's added to the
+// cache by Commit or removed by Abort.
+func (c *Cache) Create(id int) (*os.File, error) {
+	return os.CreateTemp(c.dir, tempPrefix+strconv.Itoa(id)+"-*")
+}
+
+// Commit adds complete temporary file of the track to the cache. Tracks
+// larger than the cache are not kept.
+func (c *Cache) Commit(id int, file *os.File) error {
+	info, err := file.Stat()
+	if err == nil {
+		err = file.Close()
+	}
+	if err != nil {
+		c.Abort(file)
+		return err
+	}
+
+	if info.Size() > c.maxSize {
+		os.Remove(file.Name())
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.Rename(file.Name(), c.path(id)); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	if element, ok := c.entries[id]; ok {
+		c.size -= element.Value.(*cacheItem).size
+		c.lru.Remove(element)
+	}
+
+	c.entries[id] = c.lru.PushFront(&cacheItem{id: id, size: info.Size()})
+	c.size += info.Size()
+	c.evictLocked()
+
+	return nil
+}
+
+// Abort removes temporary file of incomplete download.
+func (c *Cache) Abort(file *os.File) {
+	file.Close()
+	os.Remove(file.Name())
+}
+
+// Size returns total size of cached tracks.
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.size
+}
+
+// Len returns count of cached tracks.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// evictLocked removes least recently used tracks until size fits.
+func (c *Cache) evictLocked() {
+	for c.size > c.maxSize && c.lru.Len() > 0 {
+		c.removeLocked(c.lru.Back())
+	}
+}
+
+func (c *Cache) removeLocked(element *list.Element) {
+	item := element.Value.(*cacheItem)
+	c.lru.Remove(element)
+	delete(c.entries, item.id)
+	c.size -= item.size
+
+	// Open files are still readable on Unix after removal.
+	os.Remove(c.path(item.id))
+}
@@ -0,0 +1,140 @@
+package proxy
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// put adds track with content to the cache.
+func put(t *testing.T, cache *Cache, id int, content string) {
+	t.Helper()
+
+	file, err := cache.Create(id)
+	require.NoError(t, err)
+	_, err = file.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, cache.Commit(id, file))
+}
+
+// read returns content of the cached track or false.
+func read(t *testing.T, cache *Cache, id int) (string, bool) {
+	t.Helper()
+
+	file, ok := cache.Open(id)
+	if !ok {
+		return "", false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	return string(data), true
+}
+
+func TestCache_Evict(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewCache(dir, 10)
+	require.NoError(t, err)
+
+	put(t, cache, 1, "aaaa")
+	put(t, cache, 2, "bbbb")
+
+	// Track 1 becomes the most recently used.
+	content, ok := read(t, cache, 1)
+	assert.True(t, ok)
+	assert.Equal(t, "aaaa", content)
+
+	put(t, cache, 3, "cccc")
+	assert.Equal(t, int64(8), cache.Size())
+	assert.Equal(t, 2, cache.Len())
+
+	_, ok = read(t, cache, 2)
+	assert.False(t, ok)
+	_, err = os.Stat(filepath.Join(dir, "2.mp3"))
+	assert.True(t, os.IsNotExist(err))
+
+	// Replacing track updates size.
+	put(t, cache, 3, "cc")
+	assert.Equal(t, int64(6), cache.Size())
+
+	// Track larger than the cache isn't kept.
+	put(t, cache, 4, strings.Repeat("d", 11))
+	_, ok = read(t, cache, 4)
+	assert.False(t, ok)
+	assert.Equal(t, 2, cache.Len())
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+}
+
+func TestCache_Abort(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewCache(dir, 10)
+	require.NoError(t, err)
+
+	file, err := cache.Create(1)
+	require.NoError(t, err)
+	_, err = file.WriteString("partial")
+	require.NoError(t, err)
+	cache.Abort(file)
+
+	_, ok := read(t, cache, 1)
+	assert.False(t, ok)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestNewCache_LoadsFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, content string, age time.Duration) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		modTime := time.Now().Add(-age)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	write("1.mp3", "aaaa", time.Hour)
+	write("2.mp3", "bbbb", time.Minute)
+	write("3.mp3", "cccc", time.Second)
+	write(tempPrefix+"4-123", "partial", 2*time.Hour)
+	write(tempPrefix+"5-456", "downloading", time.Minute)
+	write(".other", "keep", 2*time.Hour)
+	write("notes.txt", "keep", 0)
+
+	// The oldest track is evicted to fit the limit.
+	cache, err := NewCache(dir, 8)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cache.Len())
+	assert.Equal(t, int64(8), cache.Size())
+
+	_, ok := read(t, cache, 1)
+	assert.False(t, ok)
+	content, ok := read(t, cache, 2)
+	assert.True(t, ok)
+	assert.Equal(t, "bbbb", content)
+
+	// Only stale temporary files are removed.
+	_, err = os.Stat(filepath.Join(dir, tempPrefix+"4-123"))
+	assert.True(t, os.IsNotExist(err))
+	assert.FileExists(t, filepath.Join(dir, tempPrefix+"5-456"))
+	assert.FileExists(t, filepath.Join(dir, ".other"))
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+
+	// LRU order survives restart: track 2 was used after track 3.
+	cache, err = NewCache(dir, 4)
+	require.NoError(t, err)
+	_, ok = read(t, cache, 3)
+	assert.False(t, ok)
+	_, ok = read(t, cache, 2)
+	assert.True(t, ok)
+}
@@ -0,0 +1,205 @@
+// Package proxy implements HTTP server which streams tracks by stable URLs
+// like "/track/10994777". Download URLs of Yandex.Music expire within
+// a minute, so a fresh URL is resolved on each request and the audio is
+// proxied with Range support, so that any media player or web <audio> tag
+// can play and seek tracks.
+//
+// Tracks downloaded completely are cached on disk and served from there
+// next time. Least recently used tracks are evicted when the cache exceeds
+// its size limit.
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// DefaultMaxCacheSize is a size limit of the cache if Options.MaxCacheSize
+// is zero.
+const DefaultMaxCacheSize = 1 << 30
+
+// CacheStatusHeader is set on track responses. Its value is "HIT" for
+// tracks served from the cache and "MISS" for proxied ones.
+const CacheStatusHeader = "X-Yamusic-Cache"
+
+// proxiedHeaders are headers of upstream response passed to the client.
+var proxiedHeaders = []string{"Content-Type", "Content-Length", "Content-Range", "Accept-Ranges", "Last-Modified", "ETag"}
+
+type (
+	// Options are options of Server.
+	Options struct {
+		// CacheDir is a directory of cached tracks. Caching is disabled
+		// if it's empty.
+		CacheDir string
+		// MaxCacheSize is a size limit of the cache in bytes. Defaults to
+		// DefaultMaxCacheSize.
+		MaxCacheSize int64
+		// HTTPClient downloads tracks. Defaults to http.DefaultClient.
+		HTTPClient yamusic.Doer
+	}
+
+	// Server serves tracks under "/track/" path.
+	Server struct {
+		client *yamusic.Client
+		opts   Options
+		cache  *Cache
+	}
+)
+
+// NewServer returns server which streams tracks of the client's catalog.
+func NewServer(client *yamusic.Client, opts *Options) (*Server, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	s := &Server{client: client, opts: *opts}
+	if s.opts.HTTPClient == nil {
+		s.opts.HTTPClient = http.DefaultClient
+	}
+	if s.opts.MaxCacheSize <= 0 {
+		s.opts.MaxCacheSize = DefaultMaxCacheSize
+	}
+
+	if s.opts.CacheDir != "" {
+		cache, err := NewCache(s.opts.CacheDir, s.opts.MaxCacheSize)
+		if err != nil {
+			return nil, fmt.Errorf("proxy: open cache: %w", err)
+		}
+		s.cache = cache
+	}
+
+	return s, nil
+}
+
+// Cache returns cache of the server or nil if caching is disabled.
+func (s *Server) Cache() *Cache {
+	return s.cache
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/track/")
+	if name == r.URL.Path {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Extension helps players which detect format by URL.
+	id, err := strconv.Atoi(strings.TrimSuffix(name, ".mp3"))
+	if err != nil || id <= 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.cache != nil {
+		if file, ok := s.cache.Open(id); ok {
+			defer file.Close()
+
+			w.Header().Set("Content-Type", "audio/mpeg")
+			w.Header().Set(CacheStatusHeader, "HIT")
+			// ServeContent handles Range requests. Modification time of the
+			// file is access time, so it's not sent as Last-Modified.
+			http.ServeContent(w, r, "", time.Time{}, file)
+			return
+		}
+	}
+
+	s.proxy(w, r, id)
+}
+
+// proxy streams the track from a fresh download URL. Complete downloads
+// are written to the cache.
+func (s *Server) proxy(w http.ResponseWriter, r *http.Request, id int) {
+	downloadURL, err := s.client.Tracks().GetDownloadURL(r.Context(), id)
+	if err != nil {
+		status := http.StatusBadGateway
+		if errors.Is(err, yamusic.ErrZeroResultLen) || errors.Is(err, yamusic.ErrEmptyPath) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, fmt.Sprintf("track %d: %v", id, err), status)
+		return
+	}
+
+	// Players usually start with "bytes=0-", which is the whole track
+	// as well, so it's requested without Range to be cached.
+	rangeHeader := r.Header.Get("Range")
+	whole := rangeHeader == "" || rangeHeader == "bytes=0-"
+
+	req, err := http.NewRequest(r.Method, downloadURL, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !whole {
+		req.Header.Set("Range", rangeHeader)
+	}
+
+	resp, err := s.opts.HTTPClient.Do(req.WithContext(r.Context()))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("track %d: %v", id, err), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent, http.StatusRequestedRangeNotSatisfiable:
+	default:
+		http.Error(w, fmt.Sprintf("track %d: %s", id, resp.Status), http.StatusBadGateway)
+		return
+	}
+
+	for _, header := range proxiedHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			w.Header().Set(header, value)
+		}
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "audio/mpeg")
+	}
+	w.Header().Set("Accept-Ranges", "bytes")
+	w.Header().Set(CacheStatusHeader, "MISS")
+	w.WriteHeader(resp.StatusCode)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	if s.cache == nil || !whole || resp.StatusCode != http.StatusOK {
+		// Client may disconnect at any moment, error can't be reported anyway.
+		_, _ = io.Copy(w, resp.Body)
+		return
+	}
+
+	s.copyAndCache(w, resp, id)
+}
+
+// copyAndCache writes body to the client and to the cache. The track is
+// cached only if it's downloaded completely.
+func (s *Server) copyAndCache(w io.Writer, resp *http.Response, id int) {
+	file, err := s.cache.Create(id)
+	if err != nil {
+		_, _ = io.Copy(w, resp.Body)
+		return
+	}
+
+	n, err := io.Copy(io.MultiWriter(w, file), resp.Body)
+	if err != nil || (resp.ContentLength >= 0 && n != resp.ContentLength) {
+		s.cache.Abort(file)
+		return
+	}
+
+	_ = s.cache.Commit(id, file)
+}
@@ -0,0 +1,181 @@
+package proxy
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// content is audio of track 1.
+const content = "ID3 fake mp3 content of the track"
+
+// upstream counts requests of track files.
+type upstream struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (u *upstream) add(r *http.Request) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.requests = append(u.requests, r.Method+" "+r.Header.Get("Range"))
+}
+
+func (u *upstream) list() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return append([]string(nil), u.requests...)
+}
+
+// setup returns URL of proxy server with the cache in a temporary directory
+// and upstream of track 1.
+func setup(t *testing.T, opts *Options) (string, *upstream) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	api := httptest.NewServer(mux)
+	t.Cleanup(api.Close)
+
+	mux.HandleFunc("/tracks/1/download-info", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[{"codec":"mp3","downloadInfoUrl":"download-info/1"}]}`)
+	})
+	mux.HandleFunc("/download-info/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<download-info><host>storage.test</host><path>/music/1.mp3</path><ts>5e0d</ts><s>salt</s></download-info>`)
+	})
+	mux.HandleFunc("/tracks/2/download-info", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"name":"not-found"}}`)
+	})
+
+	sign := md5.Sum([]byte("XGRlBW9FXlekgbPrRHuSiA" + "music/1.mp3" + "salt"))
+	trackURL := "https://storage.test/get-mp3/" + hex.EncodeToString(sign[:]) + "/5e0d/music/1.mp3"
+
+	up := &upstream{}
+	httpClient := yamusic.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		recorder := httptest.NewRecorder()
+		if req.URL.String() != trackURL {
+			http.NotFound(recorder, req)
+			return recorder.Result(), nil
+		}
+
+		up.add(req)
+		recorder.Header().Set("Content-Type", "audio/mpeg")
+		http.ServeContent(recorder, req, "", time.Time{}, strings.NewReader(content))
+		return recorder.Result(), nil
+	})
+
+	baseURL, _ := url.Parse(api.URL + "/")
+	client := yamusic.NewClient(yamusic.BaseURL(baseURL), yamusic.AccessToken(2000, "accessToken"))
+
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.HTTPClient = httpClient
+
+	server, err := NewServer(client, opts)
+	require.NoError(t, err)
+
+	proxy := httptest.NewServer(server)
+	t.Cleanup(proxy.Close)
+
+	return proxy.URL, up
+}
+
+// get requests the path with Range header if it's not empty.
+func get(t *testing.T, rawURL, rangeHeader string) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	require.NoError(t, err)
+	if rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestServer_Proxy(t *testing.T) {
+	proxyURL, up := setup(t, nil)
+
+	resp, body := get(t, proxyURL+"/track/1", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "audio/mpeg", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "bytes", resp.Header.Get("Accept-Ranges"))
+	assert.Equal(t, "MISS", resp.Header.Get(CacheStatusHeader))
+	assert.Equal(t, content, body)
+
+	resp, body = get(t, proxyURL+"/track/1.mp3", "bytes=4-7")
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, fmt.Sprintf("bytes 4-7/%d", len(content)), resp.Header.Get("Content-Range"))
+	assert.Equal(t, "fake", body)
+
+	// Without cache every request goes upstream.
+	get(t, proxyURL+"/track/1", "")
+	assert.Equal(t, []string{"GET ", "GET bytes=4-7", "GET "}, up.list())
+}
+
+func TestServer_Cache(t *testing.T) {
+	proxyURL, up := setup(t, &Options{CacheDir: t.TempDir()})
+
+	// Range request isn't cached.
+	_, body := get(t, proxyURL+"/track/1", "bytes=0-2")
+	assert.Equal(t, "ID3", body)
+
+	// Players start with "bytes=0-", the whole track is downloaded.
+	resp, body := get(t, proxyURL+"/track/1", "bytes=0-")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "MISS", resp.Header.Get(CacheStatusHeader))
+	assert.Equal(t, content, body)
+
+	resp, body = get(t, proxyURL+"/track/1", "bytes=4-7")
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, "HIT", resp.Header.Get(CacheStatusHeader))
+	assert.Equal(t, "fake", body)
+
+	resp, body = get(t, proxyURL+"/track/1", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "HIT", resp.Header.Get(CacheStatusHeader))
+	assert.Equal(t, "audio/mpeg", resp.Header.Get("Content-Type"))
+	assert.Equal(t, content, body)
+
+	assert.Equal(t, []string{"GET bytes=0-2", "GET "}, up.list())
+}
+
+func TestServer_Errors(t *testing.T) {
+	proxyURL, _ := setup(t, nil)
+
+	resp, _ := get(t, proxyURL+"/track/2", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = get(t, proxyURL+"/track/abc", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = get(t, proxyURL+"/album/1", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err := http.Post(proxyURL+"/track/1", "text/plain", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	assert.Equal(t, "GET, HEAD", resp.Header.Get("Allow"))
+}
@@ -0,0 +1,138 @@
+package scrobble
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// DefaultLastFMEndpoint is a Last.fm API endpoint.
+const DefaultLastFMEndpoint = "https://ws.audioscrobbler.com/2.0/"
+
+// lastFMBatchSize is a maximum count of scrobbles in track.scrobble.
+const lastFMBatchSize = 50
+
+// lastFMTemporary are codes of Last.fm errors after which the same
+// scrobbles may be accepted: operation failed, service offline, temporarily
+// unavailable and rate limit exceeded.
+var lastFMTemporary = map[int]bool{8: true, 11: true, 16: true, 29: true}
+
+// LastFM submits listens with Last.fm track.scrobble method.
+type LastFM struct {
+	// APIKey and Secret are credentials of the API account.
+	APIKey string
+	Secret string
+	// SessionKey is a key of the user's session.
+	SessionKey string
+	// Endpoint is an API endpoint. Defaults to DefaultLastFMEndpoint.
+	Endpoint string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient yamusic.Doer
+}
+
+// BatchSize implements Submitter interface.
+func (l *LastFM) BatchSize() int {
+	return lastFMBatchSize
+}
+
+// Submit implements Submitter interface. Only the main artist of a listen
+// is sent, so that Last.fm can match the track.
+func (l *LastFM) Submit(ctx context.Context, listens []Listen) error {
+	params := l.params(listens)
+
+	endpoint := l.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultLastFMEndpoint
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	httpClient := l.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("scrobble: last.fm: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Error   int    `json:"error"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && resp.StatusCode < http.StatusBadRequest {
+		return fmt.Errorf("scrobble: last.fm: decode response: %w", err)
+	}
+
+	if body.Error != 0 {
+		return &Error{Service: "last.fm", Code: body.Error, Message: body.Message, Rejected: !lastFMTemporary[body.Error]}
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return &Error{Service: "last.fm", Code: resp.StatusCode, Message: resp.Status, Rejected: rejectedStatus(resp.StatusCode)}
+	}
+
+	return nil
+}
+
+// params returns signed parameters of track.scrobble.
+func (l *LastFM) params(listens []Listen) url.Values {
+	params := url.Values{}
+	params.Set("method", "track.scrobble")
+	params.Set("api_key", l.APIKey)
+	params.Set("sk", l.SessionKey)
+
+	for i, listen := range listens {
+		suffix := "[" + strconv.Itoa(i) + "]"
+		params.Set("artist"+suffix, listen.artist())
+		params.Set("track"+suffix, listen.Title)
+		params.Set("timestamp"+suffix, strconv.FormatInt(listen.PlayedAt.Unix(), 10))
+		if listen.Album != "" {
+			params.Set("album"+suffix, listen.Album)
+		}
+		if listen.Duration > 0 {
+			params.Set("duration"+suffix, strconv.Itoa(int(listen.Duration.Seconds())))
+		}
+	}
+
+	params.Set("api_sig", lastFMSignature(params, l.Secret))
+	params.Set("format", "json")
+
+	return params
+}
+
+// lastFMSignature returns md5 of parameters sorted by name and concatenated
+// as name and value followed by the secret.
+func lastFMSignature(params url.Values, secret string) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if name != "format" && name != "callback" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteString(params.Get(name))
+	}
+	b.WriteString(secret)
+
+	sum := md5.Sum([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,88 @@
+package scrobble
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastFM_Submit(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/2.0/", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+
+		form := r.PostForm
+		assert.Equal(t, "track.scrobble", form.Get("method"))
+		assert.Equal(t, "key", form.Get("api_key"))
+		assert.Equal(t, "session", form.Get("sk"))
+		assert.Equal(t, "json", form.Get("format"))
+
+		assert.Equal(t, "Queen", form.Get("artist[0]"))
+		assert.Equal(t, "Under Pressure", form.Get("track[0]"))
+		assert.Equal(t, "Hot Space", form.Get("album[0]"))
+		assert.Equal(t, "248", form.Get("duration[0]"))
+		assert.Equal(t, "1577934245", form.Get("timestamp[0]"))
+
+		assert.Equal(t, "The Beatles", form.Get("artist[1]"))
+		assert.Equal(t, "Yesterday", form.Get("track[1]"))
+		assert.Empty(t, form["album[1]"])
+		assert.Equal(t, "1577937845", form.Get("timestamp[1]"))
+
+		// Signature is md5 of sorted parameters without format and the secret.
+		signed := "album[0]Hot Spaceapi_keykeyartist[0]Queenartist[1]The Beatles" +
+			"duration[0]248duration[1]125methodtrack.scrobblesksession" +
+			"timestamp[0]1577934245timestamp[1]1577937845" +
+			"track[0]Under Pressuretrack[1]Yesterdaysecret"
+		sum := md5.Sum([]byte(signed))
+		assert.Equal(t, hex.EncodeToString(sum[:]), form.Get("api_sig"))
+
+		fmt.Fprint(w, `{"scrobbles":{"@attr":{"accepted":2,"ignored":0}}}`)
+	}))
+	defer server.Close()
+
+	lastFM := &LastFM{APIKey: "key", Secret: "secret", SessionKey: "session", Endpoint: server.URL + "/2.0/"}
+	assert.Equal(t, 50, lastFM.BatchSize())
+
+	playedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := lastFM.Submit(context.Background(), []Listen{
+		{
+			Title:    "Under Pressure",
+			Artists:  []string{"Queen", "David Bowie"},
+			Album:    "Hot Space",
+			Duration: 248 * time.Second,
+			PlayedAt: playedAt,
+		},
+		{
+			Title:    "Yesterday",
+			Artists:  []string{"The Beatles"},
+			Duration: 125 * time.Second,
+			PlayedAt: playedAt.Add(time.Hour),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestLastFM_SubmitError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error":9,"message":"Invalid session key - Please re-authenticate"}`)
+	}))
+	defer server.Close()
+
+	lastFM := &LastFM{Endpoint: server.URL}
+	err := lastFM.Submit(context.Background(), []Listen{{Title: "Yesterday"}})
+	assert.Equal(t, &Error{Service: "last.fm", Code: 9, Message: "Invalid session key - Please re-authenticate", Rejected: true}, err)
+	assert.EqualError(t, err, "scrobble: last.fm: error 9: Invalid session key - Please re-authenticate")
+}
@@ -0,0 +1,167 @@
+// Package scrobble submits plays of Yandex.Music tracks to Last.fm and
+// ListenBrainz.
+//
+// Plays reported with TracksService.ReportPlay are converted to listens
+// with track metadata and added to a Queue, which keeps them on disk while
+// the service is unavailable, skips duplicates and submits them in batches.
+package scrobble
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// minDuration is a minimum duration of a track to be scrobbled.
+const minDuration = 30 * time.Second
+
+// maxPlayed is played time after which a track is scrobbled even if it's
+// played less than a half.
+const maxPlayed = 4 * time.Minute
+
+type (
+	// Listen is a play of a track with its metadata.
+	Listen struct {
+		TrackID string `json:"trackId"`
+		Title   string `json:"title"`
+		// Artists are names of the track's artists, the main one first.
+		Artists  []string      `json:"artists"`
+		Album    string        `json:"album,omitempty"`
+		Duration time.Duration `json:"duration"`
+		// Played is how long the track was played. Zero means the whole
+		// track.
+		Played   time.Duration `json:"played,omitempty"`
+		PlayedAt time.Time     `json:"playedAt"`
+	}
+
+	// Submitter submits listens to a scrobbling service.
+	Submitter interface {
+		// Submit submits at most BatchSize listens.
+		Submit(ctx context.Context, listens []Listen) error
+		// BatchSize is a maximum count of listens in one submission.
+		BatchSize() int
+	}
+
+	// Error is an error returned by a scrobbling service.
+	Error struct {
+		Service string
+		Code    int
+		Message string
+		// Rejected is true if the service rejected listens, so that
+		// submitting them again can't succeed.
+		Rejected bool
+	}
+)
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("scrobble: %s: error %d: %s", e.Service, e.Code, e.Message)
+}
+
+// rejectedStatus reports whether HTTP status means that the service
+// rejected listens. Auth and rate limit errors may be fixed, so that
+// listens are submitted again.
+func rejectedStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError
+}
+
+// NewListen returns listen of the whole track played at the time.
+func NewListen(track yamusic.Track, playedAt time.Time) Listen {
+	listen := Listen{
+		TrackID:  track.ID,
+		Title:    track.Title,
+		Duration: time.Duration(track.DurationMs) * time.Millisecond,
+		PlayedAt: playedAt,
+	}
+
+	for _, artist := range track.Artists {
+		listen.Artists = append(listen.Artists, artist.Name)
+	}
+	if len(track.Albums) > 0 {
+		listen.Album = track.Albums[0].Title
+	}
+
+	return listen
+}
+
+// Listens converts plays to listens fetching metadata of their tracks.
+// Plays of unknown tracks are skipped.
+func Listens(ctx context.Context, client *yamusic.Client, plays []yamusic.PlayAudio) ([]Listen, error) {
+	if len(plays) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(plays))
+	ids := make([]string, 0, len(plays))
+	for _, play := range plays {
+		if !seen[play.TrackID] {
+			seen[play.TrackID] = true
+			ids = append(ids, play.TrackID)
+		}
+	}
+
+	tracks, resp, err := client.Tracks().GetMany(ctx, ids)
+	if err == nil {
+		err = yamusic.CheckResponse(resp, tracks.Error)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scrobble: get tracks: %w", err)
+	}
+
+	byID := make(map[string]yamusic.Track, len(tracks.Result))
+	for _, track := range tracks.Result {
+		byID[track.ID] = track
+	}
+
+	listens := make([]Listen, 0, len(plays))
+	for _, play := range plays {
+		track, ok := byID[play.TrackID]
+		if !ok {
+			continue
+		}
+
+		listen := NewListen(track, play.Timestamp)
+		if play.TotalPlayedSeconds > 0 {
+			listen.Played = time.Duration(play.TotalPlayedSeconds * float64(time.Second))
+		}
+		listens = append(listens, listen)
+	}
+
+	return listens, nil
+}
+
+// Eligible reports whether the listen should be scrobbled according to
+// Last.fm rules: the track is longer than 30 seconds and it was played
+// for at least half of its duration or for 4 minutes.
+func (l Listen) Eligible() bool {
+	if l.Duration > 0 && l.Duration <= minDuration {
+		return false
+	}
+	if l.Played == 0 || l.Duration == 0 {
+		return true
+	}
+
+	return l.Played >= maxPlayed || l.Played*2 >= l.Duration
+}
+
+// Key identifies the listen for deduplication.
+func (l Listen) Key() string {
+	return l.TrackID + "@" + strconv.FormatInt(l.PlayedAt.Unix(), 10)
+}
+
+// artist returns the main artist of the listen.
+func (l Listen) artist() string {
+	if len(l.Artists) == 0 {
+		return ""
+	}
+
+	return l.Artists[0]
+}
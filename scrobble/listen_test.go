@@ -0,0 +1,108 @@
+package scrobble
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setup(t *testing.T) (*http.ServeMux, *yamusic.Client) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	baseURL, _ := url.Parse(server.URL + "/")
+	client := yamusic.NewClient(yamusic.BaseURL(baseURL), yamusic.AccessToken(2000, "accessToken"))
+	return mux, client
+}
+
+func TestListens(t *testing.T) {
+	mux, client := setup(t)
+
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1,2,3", r.FormValue("track-ids"))
+		fmt.Fprint(w, `{"result":[
+			{"id":"1","title":"Under Pressure","durationMs":248000,
+			 "artists":[{"name":"Queen"},{"name":"David Bowie"}],"albums":[{"title":"Hot Space"}]},
+			{"id":"2","title":"Yesterday","durationMs":125000,"artists":[{"name":"The Beatles"}]}
+		]}`)
+	})
+
+	playedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	listens, err := Listens(context.Background(), client, []yamusic.PlayAudio{
+		{TrackID: "1", Timestamp: playedAt, TotalPlayedSeconds: 100.5},
+		{TrackID: "2", Timestamp: playedAt.Add(time.Hour)},
+		{TrackID: "1", Timestamp: playedAt.Add(2 * time.Hour)},
+		{TrackID: "3", Timestamp: playedAt.Add(3 * time.Hour)},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Listen{
+		{
+			TrackID:  "1",
+			Title:    "Under Pressure",
+			Artists:  []string{"Queen", "David Bowie"},
+			Album:    "Hot Space",
+			Duration: 248 * time.Second,
+			Played:   100500 * time.Millisecond,
+			PlayedAt: playedAt,
+		},
+		{
+			TrackID:  "2",
+			Title:    "Yesterday",
+			Artists:  []string{"The Beatles"},
+			Duration: 125 * time.Second,
+			PlayedAt: playedAt.Add(time.Hour),
+		},
+		{
+			TrackID:  "1",
+			Title:    "Under Pressure",
+			Artists:  []string{"Queen", "David Bowie"},
+			Album:    "Hot Space",
+			Duration: 248 * time.Second,
+			PlayedAt: playedAt.Add(2 * time.Hour),
+		},
+	}, listens)
+}
+
+func TestListens_Error(t *testing.T) {
+	mux, client := setup(t)
+
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"name":"session-expired","message":"Your session has expired"}}`)
+	})
+
+	_, err := Listens(context.Background(), client, []yamusic.PlayAudio{{TrackID: "1"}})
+	assert.EqualError(t, err, "scrobble: get tracks: 401 Unauthorized: session-expired: Your session has expired")
+}
+
+func TestListen_Eligible(t *testing.T) {
+	tests := []struct {
+		duration, played time.Duration
+		eligible         bool
+	}{
+		{duration: 3 * time.Minute, eligible: true},
+		{duration: 3 * time.Minute, played: 90 * time.Second, eligible: true},
+		{duration: 3 * time.Minute, played: 89 * time.Second, eligible: false},
+		{duration: 20 * time.Minute, played: 4 * time.Minute, eligible: true},
+		{duration: 20 * time.Minute, played: 3 * time.Minute, eligible: false},
+		{duration: 30 * time.Second, eligible: false},
+		{played: time.Second, eligible: true},
+	}
+
+	for _, tt := range tests {
+		listen := Listen{Duration: tt.duration, Played: tt.played}
+		assert.Equal(t, tt.eligible, listen.Eligible(), "duration %v, played %v", tt.duration, tt.played)
+	}
+}
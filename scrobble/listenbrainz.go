@@ -0,0 +1,158 @@
+package scrobble
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// DefaultListenBrainzEndpoint is a ListenBrainz submit-listens endpoint.
+const DefaultListenBrainzEndpoint = "https://api.listenbrainz.org/1/submit-listens"
+
+// listenBrainzBatchSize is a count of listens in one submission. API
+// accepts up to 1000, but also limits size of a request.
+const listenBrainzBatchSize = 100
+
+type (
+	// ListenBrainz submits listens with ListenBrainz submit-listens method.
+	ListenBrainz struct {
+		// Token is a user token.
+		Token string
+		// Endpoint is a submit-listens endpoint. Defaults to
+		// DefaultListenBrainzEndpoint.
+		Endpoint string
+		// HTTPClient defaults to http.DefaultClient.
+		HTTPClient yamusic.Doer
+	}
+
+	// ListenBrainzSubmission is a body of submit-listens request.
+	ListenBrainzSubmission struct {
+		ListenType string               `json:"listen_type"`
+		Payload    []ListenBrainzListen `json:"payload"`
+	}
+
+	// ListenBrainzListen is a listen of submit-listens request.
+	ListenBrainzListen struct {
+		ListenedAt    int64                     `json:"listened_at"`
+		TrackMetadata ListenBrainzTrackMetadata `json:"track_metadata"`
+	}
+
+	// ListenBrainzTrackMetadata is metadata of a listened track.
+	ListenBrainzTrackMetadata struct {
+		ArtistName     string                 `json:"artist_name"`
+		TrackName      string                 `json:"track_name"`
+		ReleaseName    string                 `json:"release_name,omitempty"`
+		AdditionalInfo map[string]interface{} `json:"additional_info,omitempty"`
+	}
+)
+
+// NewListenBrainzSubmission converts listens to submit-listens request.
+// Single listen is submitted as "single", multiple as "import".
+func NewListenBrainzSubmission(listens []Listen) *ListenBrainzSubmission {
+	submission := &ListenBrainzSubmission{
+		ListenType: "import",
+		Payload:    make([]ListenBrainzListen, 0, len(listens)),
+	}
+	if len(listens) == 1 {
+		submission.ListenType = "single"
+	}
+
+	for _, listen := range listens {
+		info := map[string]interface{}{
+			"media_player":      "Yandex.Music",
+			"submission_client": "go-yamusic",
+			"music_service":     "music.yandex.ru",
+			"origin_url":        "https://music.yandex.ru/track/" + listen.TrackID,
+		}
+		if len(listen.Artists) > 0 {
+			info["artist_names"] = listen.Artists
+		}
+		if listen.Duration > 0 {
+			info["duration_ms"] = listen.Duration.Milliseconds()
+		}
+
+		submission.Payload = append(submission.Payload, ListenBrainzListen{
+			ListenedAt: listen.PlayedAt.Unix(),
+			TrackMetadata: ListenBrainzTrackMetadata{
+				ArtistName:     joinArtists(listen.Artists),
+				TrackName:      listen.Title,
+				ReleaseName:    listen.Album,
+				AdditionalInfo: info,
+			},
+		})
+	}
+
+	return submission
+}
+
+// BatchSize implements Submitter interface.
+func (l *ListenBrainz) BatchSize() int {
+	return listenBrainzBatchSize
+}
+
+// Submit implements Submitter interface.
+func (l *ListenBrainz) Submit(ctx context.Context, listens []Listen) error {
+	body, err := json.Marshal(NewListenBrainzSubmission(listens))
+	if err != nil {
+		return err
+	}
+
+	endpoint := l.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultListenBrainzEndpoint
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Token "+l.Token)
+
+	httpClient := l.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("scrobble: listenbrainz: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Code  int    `json:"code"`
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Error == "" {
+			apiErr.Error = resp.Status
+		}
+
+		return &Error{Service: "listenbrainz", Code: resp.StatusCode, Message: apiErr.Error, Rejected: rejectedStatus(resp.StatusCode)}
+	}
+
+	return nil
+}
+
+// joinArtists joins names like "A, B & C".
+func joinArtists(names []string) string {
+	var b bytes.Buffer
+	for i, name := range names {
+		switch {
+		case i == 0:
+		case i == len(names)-1:
+			b.WriteString(" & ")
+		default:
+			b.WriteString(", ")
+		}
+		b.WriteString(name)
+	}
+
+	return b.String()
+}
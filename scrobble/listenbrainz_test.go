@@ -0,0 +1,111 @@
+package scrobble
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenBrainz_Submit(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/1/submit-listens", r.URL.Path)
+		assert.Equal(t, "Token token", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+
+		fmt.Fprint(w, `{"status":"ok"}`)
+	}))
+	defer server.Close()
+
+	listenBrainz := &ListenBrainz{Token: "token", Endpoint: server.URL + "/1/submit-listens"}
+	assert.Equal(t, 100, listenBrainz.BatchSize())
+
+	playedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	listens := []Listen{
+		{
+			TrackID:  "1",
+			Title:    "Under Pressure",
+			Artists:  []string{"Queen", "David Bowie"},
+			Album:    "Hot Space",
+			Duration: 248 * time.Second,
+			PlayedAt: playedAt,
+		},
+		{
+			TrackID:  "2",
+			Title:    "Yesterday",
+			Artists:  []string{"The Beatles"},
+			PlayedAt: playedAt.Add(time.Hour),
+		},
+	}
+
+	require.NoError(t, listenBrainz.Submit(context.Background(), listens))
+	require.NoError(t, listenBrainz.Submit(context.Background(), listens[1:]))
+
+	require.Len(t, bodies, 2)
+	assert.JSONEq(t, `{
+		"listen_type": "import",
+		"payload": [
+			{
+				"listened_at": 1577934245,
+				"track_metadata": {
+					"artist_name": "Queen & David Bowie",
+					"track_name": "Under Pressure",
+					"release_name": "Hot Space",
+					"additional_info": {
+						"artist_names": ["Queen", "David Bowie"],
+						"duration_ms": 248000,
+						"media_player": "Yandex.Music",
+						"submission_client": "go-yamusic",
+						"music_service": "music.yandex.ru",
+						"origin_url": "https://music.yandex.ru/track/1"
+					}
+				}
+			},
+			{
+				"listened_at": 1577937845,
+				"track_metadata": {
+					"artist_name": "The Beatles",
+					"track_name": "Yesterday",
+					"additional_info": {
+						"artist_names": ["The Beatles"],
+						"media_player": "Yandex.Music",
+						"submission_client": "go-yamusic",
+						"music_service": "music.yandex.ru",
+						"origin_url": "https://music.yandex.ru/track/2"
+					}
+				}
+			}
+		]
+	}`, bodies[0])
+	assert.Contains(t, bodies[1], `"listen_type":"single"`)
+}
+
+func TestListenBrainz_SubmitError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"code":401,"error":"Invalid authorization token."}`)
+	}))
+	defer server.Close()
+
+	listenBrainz := &ListenBrainz{Endpoint: server.URL}
+	err := listenBrainz.Submit(context.Background(), []Listen{{Title: "Yesterday"}})
+	assert.Equal(t, &Error{Service: "listenbrainz", Code: 401, Message: "Invalid authorization token."}, err)
+}
+
+func TestJoinArtists(t *testing.T) {
+	assert.Equal(t, "", joinArtists(nil))
+	assert.Equal(t, "Queen", joinArtists([]string{"Queen"}))
+	assert.Equal(t, "A, B & C", joinArtists([]string{"A", "B", "C"}))
+}
@@ -0,0 +1,226 @@
+package scrobble
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// queueVersion is a version of the queue file format.
+const queueVersion = 1
+
+// DedupWindow is how long keys of submitted listens are kept to skip
+// duplicates. Services don't accept listens older than two weeks anyway.
+const DedupWindow = 14 * 24 * time.Hour
+
+type (
+	// Queue keeps listens until they are submitted. It's saved to a file
+	// after each change, so that listens aren't lost while offline or
+	// between runs. It is safe for concurrent use.
+	Queue struct {
+		path      string
+		submitter Submitter
+		now       func() time.Time
+
+		// flushMu serializes flushes, so that a batch isn't submitted twice.
+		flushMu sync.Mutex
+
+		mu    sync.Mutex
+		state queueState
+	}
+
+	// queueState is a content of the queue file.
+	queueState struct {
+		Version int      `json:"version"`
+		Pending []Listen `json:"pending"`
+		// Submitted are times of submitted listens by their keys.
+		Submitted map[string]time.Time `json:"submitted"`
+	}
+)
+
+// OpenQueue returns queue of listens for the submitter stored in the file
+// at path. Missing file means empty queue. Empty path keeps the queue in
+// memory only.
+func OpenQueue(path string, submitter Submitter) (*Queue, error) {
+	q := &Queue{
+		path:      path,
+		submitter: submitter,
+		now:       time.Now,
+		state:     queueState{Version: queueVersion, Submitted: map[string]time.Time{}},
+	}
+
+	if path == "" {
+		return q, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scrobble: read queue: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &q.state); err != nil {
+		return nil, fmt.Errorf("scrobble: read queue: %w", err)
+	}
+	if q.state.Version != queueVersion {
+		return nil, fmt.Errorf("scrobble: unsupported queue version %d", q.state.Version)
+	}
+	if q.state.Submitted == nil {
+		q.state.Submitted = map[string]time.Time{}
+	}
+
+	return q, nil
+}
+
+// Add queues listens and returns count of added ones. Listens which are
+// not eligible for scrobbling, already queued or submitted are skipped.
+func (q *Queue) Add(listens ...Listen) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	queued := make(map[string]bool, len(q.state.Pending))
+	for _, listen := range q.state.Pending {
+		queued[listen.Key()] = true
+	}
+
+	added := 0
+	for _, listen := range listens {
+		key := listen.Key()
+		if _, submitted := q.state.Submitted[key]; submitted || queued[key] || !listen.Eligible() {
+			continue
+		}
+
+		queued[key] = true
+		q.state.Pending = append(q.state.Pending, listen)
+		added++
+	}
+
+	if added == 0 {
+		return 0, nil
+	}
+
+	// Services expect listens in chronological order.
+	sort.SliceStable(q.state.Pending, func(i, j int) bool {
+		return q.state.Pending[i].PlayedAt.Before(q.state.Pending[j].PlayedAt)
+	})
+
+	return added, q.saveLocked()
+}
+
+// Pending returns copy of queued listens.
+func (q *Queue) Pending() []Listen {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return append([]Listen(nil), q.state.Pending...)
+}
+
+// Flush submits queued listens in batches and returns count of submitted
+// ones. It stops on the first failure keeping the failed batch and the rest
+// of listens in the queue. Batches which the service rejected (see
+// Error.Rejected) are dropped, since submitting them again can't succeed,
+// and the error of the first one is returned after the rest are submitted.
+func (q *Queue) Flush(ctx context.Context) (int, error) {
+	q.flushMu.Lock()
+	defer q.flushMu.Unlock()
+
+	var rejectErr error
+	submitted := 0
+	for {
+		q.mu.Lock()
+		batch := q.state.Pending
+		if size := q.submitter.BatchSize(); len(batch) > size {
+			batch = batch[:size]
+		}
+		batch = append([]Listen(nil), batch...)
+		q.mu.Unlock()
+
+		if len(batch) == 0 {
+			return submitted, rejectErr
+		}
+
+		err := q.submitter.Submit(ctx, batch)
+		var serviceErr *Error
+		switch {
+		case err == nil:
+			submitted += len(batch)
+		case errors.As(err, &serviceErr) && serviceErr.Rejected:
+			if rejectErr == nil {
+				rejectErr = err
+			}
+		default:
+			return submitted, err
+		}
+
+		if err := q.markDone(batch); err != nil {
+			return submitted, err
+		}
+	}
+}
+
+// markDone removes submitted or rejected listens from pending and remembers
+// their keys, so that they aren't queued again.
+func (q *Queue) markDone(batch []Listen) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	done := make(map[string]bool, len(batch))
+	for _, listen := range batch {
+		done[listen.Key()] = true
+		q.state.Submitted[listen.Key()] = listen.PlayedAt
+	}
+
+	pending := q.state.Pending[:0]
+	for _, listen := range q.state.Pending {
+		if !done[listen.Key()] {
+			pending = append(pending, listen)
+		}
+	}
+	q.state.Pending = pending
+
+	// Forget listens which are too old to be submitted again.
+	expired := q.now().Add(-DedupWindow)
+	for key, playedAt := range q.state.Submitted {
+		if playedAt.Before(expired) {
+			delete(q.state.Submitted, key)
+		}
+	}
+
+	return q.saveLocked()
+}
+
+// saveLocked writes the queue to a temporary file and renames it, so that
+// the file is never left half-written.
+func (q *Queue) saveLocked() error {
+	if q.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(q.state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(q.path), 0o700); err != nil {
+		return fmt.Errorf("scrobble: save queue: %w", err)
+	}
+
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("scrobble: save queue: %w", err)
+	}
+	if err := os.Rename(tmp, q.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("scrobble: save queue: %w", err)
+	}
+
+	return nil
+}
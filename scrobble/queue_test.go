@@ -0,0 +1,214 @@
+package scrobble
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSubmitter records submitted batches and fails while err is set.
+// Batches with rejected tracks are rejected.
+type fakeSubmitter struct {
+	batchSize int
+	rejected  map[string]bool
+
+	mu      sync.Mutex
+	batches [][]string
+	err     error
+}
+
+func (s *fakeSubmitter) BatchSize() int {
+	return s.batchSize
+}
+
+func (s *fakeSubmitter) Submit(ctx context.Context, listens []Listen) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	keys := make([]string, 0, len(listens))
+	for _, listen := range listens {
+		if s.rejected[listen.TrackID] {
+			return &Error{Service: "fake", Code: 400, Message: "invalid listen", Rejected: true}
+		}
+		keys = append(keys, listen.TrackID)
+	}
+	s.batches = append(s.batches, keys)
+
+	return nil
+}
+
+// listensAt returns listens of tracks "1", "2"... played with a minute
+// interval starting at the time.
+func listensAt(start time.Time, count int) []Listen {
+	listens := make([]Listen, 0, count)
+	for i := 0; i < count; i++ {
+		listens = append(listens, Listen{
+			TrackID:  strconv.Itoa(i + 1),
+			Title:    "Song",
+			Duration: 3 * time.Minute,
+			PlayedAt: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	return listens
+}
+
+func TestQueue_Flush(t *testing.T) {
+	submitter := &fakeSubmitter{batchSize: 2}
+	queue, err := OpenQueue("", submitter)
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Second)
+	listens := listensAt(now, 5)
+
+	// Listens are sorted by time.
+	added, err := queue.Add(listens[4], listens[3], listens[2], listens[1], listens[0])
+	require.NoError(t, err)
+	assert.Equal(t, 5, added)
+
+	submitted, err := queue.Flush(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 5, submitted)
+	assert.Equal(t, [][]string{{"1", "2"}, {"3", "4"}, {"5"}}, submitter.batches)
+	assert.Empty(t, queue.Pending())
+
+	// Submitted listens are skipped.
+	added, err = queue.Add(listens[0])
+	require.NoError(t, err)
+	assert.Equal(t, 0, added)
+}
+
+func TestQueue_Dedup(t *testing.T) {
+	queue, err := OpenQueue("", &fakeSubmitter{batchSize: 10})
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Second)
+	listen := listensAt(now, 1)[0]
+
+	short := listen
+	short.TrackID = "short"
+	short.Duration = 20 * time.Second
+
+	skipped := listen
+	skipped.TrackID = "skipped"
+	skipped.Played = time.Minute
+
+	// The same track played at the same second is the same listen.
+	duplicate := listen
+	duplicate.PlayedAt = now.Add(500 * time.Millisecond)
+
+	added, err := queue.Add(listen, duplicate, short, skipped)
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+
+	added, err = queue.Add(listen)
+	require.NoError(t, err)
+	assert.Equal(t, 0, added)
+	assert.Len(t, queue.Pending(), 1)
+}
+
+func TestQueue_Offline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scrobble", "lastfm.json")
+	submitter := &fakeSubmitter{batchSize: 2, err: errors.New("network is unreachable")}
+
+	queue, err := OpenQueue(path, submitter)
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Second)
+	_, err = queue.Add(listensAt(now, 3)...)
+	require.NoError(t, err)
+
+	submitted, err := queue.Flush(context.Background())
+	assert.EqualError(t, err, "network is unreachable")
+	assert.Equal(t, 0, submitted)
+
+	// Queue survives restart.
+	submitter.err = nil
+	queue, err = OpenQueue(path, submitter)
+	require.NoError(t, err)
+	assert.Len(t, queue.Pending(), 3)
+
+	submitted, err = queue.Flush(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, submitted)
+
+	// Submitted listens are remembered between runs.
+	queue, err = OpenQueue(path, submitter)
+	require.NoError(t, err)
+	assert.Empty(t, queue.Pending())
+
+	added, err := queue.Add(listensAt(now, 4)...)
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+}
+
+func TestQueue_Rejected(t *testing.T) {
+	submitter := &fakeSubmitter{batchSize: 2, rejected: map[string]bool{"2": true}}
+	queue, err := OpenQueue("", submitter)
+	require.NoError(t, err)
+
+	listens := listensAt(time.Now().Truncate(time.Second), 5)
+	_, err = queue.Add(listens...)
+	require.NoError(t, err)
+
+	// Rejected batch doesn't block the rest of listens.
+	submitted, err := queue.Flush(context.Background())
+	assert.EqualError(t, err, "scrobble: fake: error 400: invalid listen")
+	assert.Equal(t, 3, submitted)
+	assert.Equal(t, [][]string{{"3", "4"}, {"5"}}, submitter.batches)
+	assert.Empty(t, queue.Pending())
+
+	// Rejected listens aren't queued again.
+	added, err := queue.Add(listens[0])
+	require.NoError(t, err)
+	assert.Equal(t, 0, added)
+}
+
+func TestQueue_FlushConcurrent(t *testing.T) {
+	submitter := &fakeSubmitter{batchSize: 1}
+	queue, err := OpenQueue("", submitter)
+	require.NoError(t, err)
+
+	_, err = queue.Add(listensAt(time.Now().Truncate(time.Second), 3)...)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := queue.Flush(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, [][]string{{"1"}, {"2"}, {"3"}}, submitter.batches)
+}
+
+func TestQueue_ForgetsOldListens(t *testing.T) {
+	queue, err := OpenQueue("", &fakeSubmitter{batchSize: 10})
+	require.NoError(t, err)
+
+	now := time.Date(2020, 1, 20, 0, 0, 0, 0, time.UTC)
+	queue.now = func() time.Time { return now }
+
+	old := listensAt(now.Add(-DedupWindow-time.Hour), 1)
+	_, err = queue.Add(old...)
+	require.NoError(t, err)
+	_, err = queue.Flush(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, queue.state.Submitted)
+}
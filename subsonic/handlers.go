@@ -0,0 +1,406 @@
+package subsonic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// defaultSearchCount is a default count of search3 results of each type.
+const defaultSearchCount = 20
+
+func (s *Server) ping(ctx context.Context, r *http.Request, resp *Response) error {
+	return nil
+}
+
+func (s *Server) getLicense(ctx context.Context, r *http.Request, resp *Response) error {
+	resp.License = &License{Valid: true}
+	return nil
+}
+
+func (s *Server) getMusicFolders(ctx context.Context, r *http.Request, resp *Response) error {
+	resp.MusicFolders = &MusicFolders{
+		MusicFolder: []MusicFolder{{ID: 1, Name: "Yandex.Music"}},
+	}
+	return nil
+}
+
+// getArtists returns liked artists grouped by the first letter.
+func (s *Server) getArtists(ctx context.Context, r *http.Request, resp *Response) error {
+	liked, httpResp, err := s.client.Likes().Artists(ctx)
+	if err == nil {
+		err = checkResponse(httpResp, liked.Error)
+	}
+	if err != nil {
+		return err
+	}
+
+	artists := make([]Artist, 0, len(liked.Result))
+	for _, item := range liked.Result {
+		artist := newArtist(item.Artist)
+		artist.ID = strconv.Itoa(item.ID)
+		artist.Starred = formatTime(item.Timestamp)
+		artists = append(artists, artist)
+	}
+
+	sort.SliceStable(artists, func(i, j int) bool {
+		a, b := indexName(artists[i].Name), indexName(artists[j].Name)
+		if a != b {
+			// "#" goes last.
+			return a != "#" && (b == "#" || a < b)
+		}
+		return strings.ToLower(artists[i].Name) < strings.ToLower(artists[j].Name)
+	})
+
+	resp.Artists = newArtists(artists)
+	return nil
+}
+
+// getAlbum returns album with songs of all its volumes.
+func (s *Server) getAlbum(ctx context.Context, r *http.Request, resp *Response) error {
+	id, err := intParam(r, "id")
+	if err != nil {
+		return err
+	}
+
+	album, httpResp, err := s.client.Albums().GetWithTracks(ctx, id)
+	if err == nil {
+		err = checkResponse(httpResp, album.Error)
+	}
+	if err != nil {
+		return err
+	}
+
+	result := newAlbum(album.Result.Album)
+	for _, volume := range album.Result.Volumes {
+		for _, track := range volume {
+			song := newSong(track)
+			result.Duration += song.Duration
+			result.Song = append(result.Song, song)
+		}
+	}
+	result.SongCount = len(result.Song)
+
+	resp.Album = &result
+	return nil
+}
+
+// getPlaylists returns playlists of the user.
+func (s *Server) getPlaylists(ctx context.Context, r *http.Request, resp *Response) error {
+	list, httpResp, err := s.client.Playlists().List(ctx, 0)
+	if err == nil {
+		err = checkResponse(httpResp, list.Error)
+	}
+	if err != nil {
+		return err
+	}
+
+	resp.Playlists = &Playlists{Playlist: make([]Playlist, 0, len(list.Result))}
+	for _, playlist := range list.Result {
+		resp.Playlists.Playlist = append(resp.Playlists.Playlist, newPlaylist(playlist))
+	}
+
+	return nil
+}
+
+// getPlaylist returns playlist of the user by kind with songs.
+func (s *Server) getPlaylist(ctx context.Context, r *http.Request, resp *Response) error {
+	kind, err := intParam(r, "id")
+	if err != nil {
+		return err
+	}
+
+	playlist, httpResp, err := s.client.Playlists().Get(ctx, 0, kind)
+	if err == nil {
+		err = checkResponse(httpResp, playlist.Error)
+	}
+	if err != nil {
+		return err
+	}
+
+	result := newPlaylist(playlist.Result.PlaylistsResult)
+	result.Entry = make([]Song, 0, len(playlist.Result.Tracks))
+	for _, track := range playlist.Result.Tracks {
+		result.Entry = append(result.Entry, newSong(track.Track))
+	}
+
+	resp.Playlist = &result
+	return nil
+}
+
+// search3 searches artists, albums and songs. Yandex.Music search is paged,
+// so offset is rounded down to a multiple of count.
+func (s *Server) search3(ctx context.Context, r *http.Request, resp *Response) error {
+	query := strings.Trim(strings.TrimSpace(r.Form.Get("query")), `"`)
+	resp.SearchResult3 = &SearchResult3{}
+	if query == "" {
+		return nil
+	}
+
+	opts := func(kind string) (*yamusic.SearchOptions, bool) {
+		count := defaultSearchCount
+		if value := r.Form.Get(kind + "Count"); value != "" {
+			count, _ = strconv.Atoi(value)
+		}
+		if count <= 0 {
+			return nil, false
+		}
+
+		offset, _ := strconv.Atoi(r.Form.Get(kind + "Offset"))
+		return &yamusic.SearchOptions{Page: offset / count, PageSize: count}, true
+	}
+
+	if o, ok := opts("artist"); ok {
+		found, httpResp, err := s.client.Search().Artists(ctx, query, o)
+		if err == nil {
+			err = checkResponse(httpResp, found.Error)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, artist := range found.Result.Artists.Results {
+			resp.SearchResult3.Artist = append(resp.SearchResult3.Artist, Artist{
+				ID:         strconv.Itoa(artist.ID),
+				Name:       artist.Name,
+				CoverArt:   artist.Cover.URI,
+				AlbumCount: artist.Counts.DirectAlbums,
+			})
+		}
+	}
+
+	if o, ok := opts("album"); ok {
+		found, httpResp, err := s.client.Search().Albums(ctx, query, o)
+		if err == nil {
+			err = checkResponse(httpResp, found.Error)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, album := range found.Result.Albums.Results {
+			result := Album{
+				ID:        strconv.Itoa(album.ID),
+				Name:      album.Title,
+				Artist:    album.Artists.Names(),
+				CoverArt:  album.CoverURI,
+				SongCount: album.TrackCount,
+				Year:      album.Year,
+				Genre:     album.Genre,
+			}
+			if len(album.Artists) > 0 {
+				result.ArtistID = strconv.Itoa(album.Artists[0].ID)
+			}
+
+			resp.SearchResult3.Album = append(resp.SearchResult3.Album, result)
+		}
+	}
+
+	if o, ok := opts("song"); ok {
+		found, httpResp, err := s.client.Search().Tracks(ctx, query, o)
+		if err == nil {
+			err = checkResponse(httpResp, found.Error)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, track := range found.Result.Tracks.Results {
+			song := Song{
+				ID:          strconv.Itoa(track.ID),
+				Title:       track.Title,
+				Artist:      track.Artists.Names(),
+				ContentType: "audio/mpeg",
+				Suffix:      "mp3",
+				Duration:    track.DurationMs / 1000,
+				Type:        "music",
+			}
+			if len(track.Artists) > 0 {
+				song.ArtistID = strconv.Itoa(track.Artists[0].ID)
+			}
+
+			if len(track.Albums) > 0 {
+				album := track.Albums[0]
+				song.Parent = strconv.Itoa(album.ID)
+				song.AlbumID = song.Parent
+				song.Album = album.Title
+				song.Year = album.Year
+				song.Genre = album.Genre
+				song.CoverArt = album.CoverURI
+				song.Track = album.TrackPosition.Index
+				song.DiscNumber = album.TrackPosition.Volume
+			}
+
+			resp.SearchResult3.Song = append(resp.SearchResult3.Song, song)
+		}
+	}
+
+	return nil
+}
+
+// star likes tracks, albums and artists.
+func (s *Server) star(ctx context.Context, r *http.Request, resp *Response) error {
+	likes := s.client.Likes()
+	return s.changeLikes(ctx, r, likes.LikeTracks, likes.LikeAlbums, likes.LikeArtists)
+}
+
+// unstar removes tracks, albums and artists from liked.
+func (s *Server) unstar(ctx context.Context, r *http.Request, resp *Response) error {
+	likes := s.client.Likes()
+	return s.changeLikes(ctx, r, likes.UnlikeTracks, likes.UnlikeAlbums, likes.UnlikeArtists)
+}
+
+func (s *Server) changeLikes(
+	ctx context.Context,
+	r *http.Request,
+	changeTracks func(context.Context, []string, ...yamusic.RequestOption) (*yamusic.LikesTracksChangeResp, *http.Response, error),
+	changeAlbums func(context.Context, []int, ...yamusic.RequestOption) (*yamusic.LikesChangeResp, *http.Response, error),
+	changeArtists func(context.Context, []int, ...yamusic.RequestOption) (*yamusic.LikesChangeResp, *http.Response, error),
+) error {
+	if ids := r.Form["id"]; len(ids) > 0 {
+		changed, httpResp, err := changeTracks(ctx, ids)
+		if err == nil {
+			err = checkResponse(httpResp, changed.Error)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, param := range []struct {
+		name   string
+		change func(context.Context, []int, ...yamusic.RequestOption) (*yamusic.LikesChangeResp, *http.Response, error)
+	}{
+		{"albumId", changeAlbums},
+		{"artistId", changeArtists},
+	} {
+		if len(r.Form[param.name]) == 0 {
+			continue
+		}
+
+		ids, err := intParams(r, param.name)
+		if err != nil {
+			return err
+		}
+
+		changed, httpResp, err := param.change(ctx, ids)
+		if err == nil {
+			err = checkResponse(httpResp, changed.Error)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scrobble reports plays of songs. "Now playing" notifications
+// (submission=false) are accepted but not reported.
+func (s *Server) scrobble(ctx context.Context, r *http.Request, resp *Response) error {
+	ids := r.Form["id"]
+	if len(ids) == 0 {
+		return &Error{Code: ErrorMissingParameter, Message: "required parameter is missing: id"}
+	}
+
+	if r.Form.Get("submission") == "false" {
+		return nil
+	}
+
+	tracks, httpResp, err := s.client.Tracks().GetMany(ctx, ids)
+	if err == nil {
+		err = checkResponse(httpResp, tracks.Error)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Tracks may be returned in other order and some may be missing,
+	// so that times are matched by ID.
+	times := make(map[string]time.Time, len(ids))
+	for i, value := range r.Form["time"] {
+		if i >= len(ids) {
+			break
+		}
+		if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+			times[ids[i]] = time.Unix(0, ms*int64(time.Millisecond))
+		}
+	}
+
+	for _, track := range tracks.Result {
+		seconds := float64(track.DurationMs) / 1000
+		play := yamusic.PlayAudio{
+			TrackID:            track.ID,
+			From:               "subsonic",
+			TrackLengthSeconds: seconds,
+			TotalPlayedSeconds: seconds,
+			EndPositionSeconds: seconds,
+		}
+		if len(track.Albums) > 0 {
+			play.AlbumID = track.Albums[0].ID
+		}
+		play.Timestamp = times[track.ID]
+
+		played, httpResp, err := s.client.Tracks().ReportPlay(ctx, play)
+		if err == nil {
+			err = checkResponse(httpResp, played.Error)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// intParam returns required integer parameter.
+func intParam(r *http.Request, name string) (int, error) {
+	value, err := requiredParam(r, name)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &Error{Code: ErrorNotFound, Message: fmt.Sprintf("invalid %s: %q", name, value)}
+	}
+
+	return n, nil
+}
+
+// intParams returns all values of integer parameter.
+func intParams(r *http.Request, name string) ([]int, error) {
+	values := r.Form[name]
+	ids := make([]int, 0, len(values))
+	for _, value := range values {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, &Error{Code: ErrorNotFound, Message: fmt.Sprintf("invalid %s: %q", name, value)}
+		}
+		ids = append(ids, n)
+	}
+
+	return ids, nil
+}
+
+// checkResponse converts error of Yandex.Music API to Subsonic error.
+func checkResponse(resp *http.Response, apiErr yamusic.Error) error {
+	var respErr *yamusic.ResponseError
+	if err := yamusic.CheckResponse(resp, apiErr); !errors.As(err, &respErr) {
+		return err
+	}
+
+	code := ErrorGeneric
+	if respErr.StatusCode == http.StatusNotFound {
+		code = ErrorNotFound
+	}
+
+	return &Error{Code: code, Message: respErr.Error()}
+}
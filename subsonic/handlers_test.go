@@ -0,0 +1,253 @@
+package subsonic
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_GetArtists(t *testing.T) {
+	mux, _, serverURL := setup(t)
+
+	mux.HandleFunc("/users/2000/likes/artists", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[
+			{"id":1,"timestamp":"2020-01-02T03:04:05Z","artist":{"id":1,"name":"Queen","cover":{"uri":"avatars.yandex.net/queen/%%"}}},
+			{"id":2,"artist":{"id":2,"name":"The Beatles"}},
+			{"id":3,"artist":{"id":3,"name":"2Pac"}},
+			{"id":4,"artist":{"id":4,"name":"ABBA"}},
+			{"id":5,"artist":{"id":5,"name":"Кино"}}
+		]}`)
+	})
+
+	resp := get(t, serverURL, "getArtists", nil)
+	require.NotNil(t, resp.Artists)
+	assert.Equal(t, "The", resp.Artists.IgnoredArticles)
+	assert.Equal(t, []Index{
+		{Name: "A", Artist: []Artist{{ID: "4", Name: "ABBA"}}},
+		{Name: "B", Artist: []Artist{{ID: "2", Name: "The Beatles"}}},
+		{Name: "Q", Artist: []Artist{{
+			ID:       "1",
+			Name:     "Queen",
+			CoverArt: "avatars.yandex.net/queen/%%",
+			Starred:  "2020-01-02T03:04:05Z",
+		}}},
+		{Name: "К", Artist: []Artist{{ID: "5", Name: "Кино"}}},
+		{Name: "#", Artist: []Artist{{ID: "3", Name: "2Pac"}}},
+	}, resp.Artists.Index)
+}
+
+func TestServer_GetAlbum(t *testing.T) {
+	mux, _, serverURL := setup(t)
+
+	mux.HandleFunc("/albums/20/with-tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{
+			"id":20,"title":"Help!","year":1965,"genre":"rock","coverUri":"avatars.yandex.net/help/%%","trackCount":2,
+			"artists":[{"id":7,"name":"The Beatles"}],
+			"volumes":[[
+				{"id":"1","title":"Help!","durationMs":140000,"artists":[{"id":7,"name":"The Beatles"}],
+				 "albums":[{"id":20,"title":"Help!","year":1965,"trackPosition":{"volume":1,"index":1}}]},
+				{"id":"2","title":"Yesterday","durationMs":125000,"artists":[{"id":7,"name":"The Beatles"}],
+				 "albums":[{"id":20,"title":"Help!","year":1965,"trackPosition":{"volume":1,"index":13}}]}
+			]]
+		}}`)
+	})
+
+	resp := get(t, serverURL, "getAlbum", url.Values{"id": {"20"}})
+	require.NotNil(t, resp.Album)
+
+	album := resp.Album
+	assert.Equal(t, "20", album.ID)
+	assert.Equal(t, "Help!", album.Name)
+	assert.Equal(t, "The Beatles", album.Artist)
+	assert.Equal(t, "7", album.ArtistID)
+	assert.Equal(t, 1965, album.Year)
+	assert.Equal(t, 2, album.SongCount)
+	assert.Equal(t, 265, album.Duration)
+	require.Len(t, album.Song, 2)
+	assert.Equal(t, Song{
+		ID:          "2",
+		Parent:      "20",
+		Title:       "Yesterday",
+		Album:       "Help!",
+		Artist:      "The Beatles",
+		Track:       13,
+		DiscNumber:  1,
+		Year:        1965,
+		ContentType: "audio/mpeg",
+		Suffix:      "mp3",
+		Duration:    125,
+		AlbumID:     "20",
+		ArtistID:    "7",
+		Type:        "music",
+	}, album.Song[1])
+}
+
+func TestServer_GetPlaylists(t *testing.T) {
+	mux, _, serverURL := setup(t)
+
+	mux.HandleFunc("/users/2000/playlists/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[{
+			"kind":1003,"title":"Road trip","description":"Songs","visibility":"public","trackCount":1,"durationMs":125000,
+			"created":"2020-01-02T03:04:05+03:00","owner":{"login":"john"},"cover":{"uri":"avatars.yandex.net/road/%%"}
+		}]}`)
+	})
+	mux.HandleFunc("/users/2000/playlists/1003", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"kind":1003,"title":"Road trip","trackCount":1,"tracks":[
+			{"track":{"id":"2","title":"Yesterday","durationMs":125000,"albums":[{"id":20,"title":"Help!"}]}}
+		]}}`)
+	})
+
+	resp := get(t, serverURL, "getPlaylists", nil)
+	require.NotNil(t, resp.Playlists)
+	assert.Equal(t, []Playlist{{
+		ID:        "1003",
+		Name:      "Road trip",
+		Comment:   "Songs",
+		Owner:     "john",
+		Public:    true,
+		SongCount: 1,
+		Duration:  125,
+		Created:   "2020-01-02T00:04:05Z",
+		CoverArt:  "avatars.yandex.net/road/%%",
+	}}, resp.Playlists.Playlist)
+
+	resp = get(t, serverURL, "getPlaylist", url.Values{"id": {"1003"}})
+	require.NotNil(t, resp.Playlist)
+	assert.Equal(t, "Road trip", resp.Playlist.Name)
+	require.Len(t, resp.Playlist.Entry, 1)
+	assert.Equal(t, "Yesterday", resp.Playlist.Entry[0].Title)
+	assert.Equal(t, "20", resp.Playlist.Entry[0].AlbumID)
+}
+
+func TestServer_Search3(t *testing.T) {
+	mux, _, serverURL := setup(t)
+
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "beatles", query.Get("text"))
+
+		switch query.Get("type") {
+		case "artist":
+			assert.Equal(t, "0", query.Get("page"))
+			assert.Equal(t, "20", query.Get("page-size"))
+			fmt.Fprint(w, `{"result":{"artists":{"results":[{"id":7,"name":"The Beatles","counts":{"directAlbums":13}}]}}}`)
+		case "track":
+			assert.Equal(t, "2", query.Get("page"))
+			assert.Equal(t, "5", query.Get("page-size"))
+			fmt.Fprint(w, `{"result":{"tracks":{"results":[
+				{"id":2,"title":"Yesterday","durationMs":125000,"artists":[{"id":7,"name":"The Beatles"}],"albums":[{"id":20,"title":"Help!"}]}
+			]}}}`)
+		default:
+			t.Errorf("unexpected search of %s", query.Get("type"))
+		}
+	})
+
+	resp := get(t, serverURL, "search3", url.Values{
+		"query":       {`"beatles"`},
+		"albumCount":  {"0"},
+		"songCount":   {"5"},
+		"songOffset":  {"10"},
+		"artistCount": {"20"},
+	})
+	require.NotNil(t, resp.SearchResult3)
+	assert.Equal(t, []Artist{{ID: "7", Name: "The Beatles", AlbumCount: 13}}, resp.SearchResult3.Artist)
+	assert.Empty(t, resp.SearchResult3.Album)
+	require.Len(t, resp.SearchResult3.Song, 1)
+	assert.Equal(t, "Yesterday", resp.SearchResult3.Song[0].Title)
+	assert.Equal(t, "The Beatles", resp.SearchResult3.Song[0].Artist)
+	assert.Equal(t, "20", resp.SearchResult3.Song[0].AlbumID)
+	assert.Equal(t, 125, resp.SearchResult3.Song[0].Duration)
+
+	// Empty query is used by some clients to list everything.
+	resp = get(t, serverURL, "search3", url.Values{"query": {`""`}})
+	assert.Equal(t, "ok", resp.Status)
+	assert.Equal(t, &SearchResult3{}, resp.SearchResult3)
+}
+
+func TestServer_StarUnstar(t *testing.T) {
+	mux, _, serverURL := setup(t)
+
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+	handle := func(path, field string) {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			calls = append(calls, path+" "+r.FormValue(field))
+			mu.Unlock()
+			fmt.Fprint(w, `{"result":"ok"}`)
+		})
+	}
+	handle("/users/2000/likes/tracks/add-multiple", "track-ids")
+	handle("/users/2000/likes/albums/add-multiple", "album-ids")
+	handle("/users/2000/likes/artists/add-multiple", "artist-ids")
+	handle("/users/2000/likes/tracks/remove", "track-ids")
+
+	resp := get(t, serverURL, "star", url.Values{"id": {"1", "2"}, "albumId": {"20"}, "artistId": {"7"}})
+	assert.Equal(t, "ok", resp.Status)
+
+	resp = get(t, serverURL, "unstar", url.Values{"id": {"1"}})
+	assert.Equal(t, "ok", resp.Status)
+
+	assert.Equal(t, []string{
+		"/users/2000/likes/tracks/add-multiple 1,2",
+		"/users/2000/likes/albums/add-multiple 20",
+		"/users/2000/likes/artists/add-multiple 7",
+		"/users/2000/likes/tracks/remove 1",
+	}, calls)
+
+	resp = get(t, serverURL, "star", url.Values{"albumId": {"help"}})
+	assert.Equal(t, &Error{Code: ErrorNotFound, Message: `invalid albumId: "help"`}, resp.Error)
+}
+
+func TestServer_Scrobble(t *testing.T) {
+	mux, _, serverURL := setup(t)
+
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("track-ids") == "404,3,2" {
+			// Tracks are reordered and missing ones are skipped.
+			fmt.Fprint(w, `{"result":[{"id":"2","durationMs":125000},{"id":"3","durationMs":1000}]}`)
+			return
+		}
+		assert.Equal(t, "2", r.FormValue("track-ids"))
+		fmt.Fprint(w, `{"result":[{"id":"2","durationMs":125000,"albums":[{"id":20}]}]}`)
+	})
+
+	var plays []url.Values
+	mux.HandleFunc("/play-audio", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		plays = append(plays, r.PostForm)
+		fmt.Fprint(w, `{"result":"ok"}`)
+	})
+
+	resp := get(t, serverURL, "scrobble", url.Values{"id": {"2"}, "submission": {"false"}})
+	assert.Equal(t, "ok", resp.Status)
+	assert.Empty(t, plays)
+
+	resp = get(t, serverURL, "scrobble", url.Values{"id": {"2"}, "time": {"1577934245000"}})
+	assert.Equal(t, "ok", resp.Status)
+	require.Len(t, plays, 1)
+	assert.Equal(t, "2", plays[0].Get("track-id"))
+	assert.Equal(t, "20", plays[0].Get("album-id"))
+	assert.Equal(t, "subsonic", plays[0].Get("from"))
+	assert.Equal(t, "125", plays[0].Get("track-length-seconds"))
+	assert.Equal(t, "2020-01-02T03:04:05.000Z", plays[0].Get("timestamp"))
+
+	plays = nil
+	resp = get(t, serverURL, "scrobble", url.Values{
+		"id":   {"404", "3", "2"},
+		"time": {"1577934245000", "1577934246000", "1577934247000"},
+	})
+	assert.Equal(t, "ok", resp.Status)
+	require.Len(t, plays, 2)
+	assert.Equal(t, "2", plays[0].Get("track-id"))
+	assert.Equal(t, "2020-01-02T03:04:07.000Z", plays[0].Get("timestamp"))
+	assert.Equal(t, "3", plays[1].Get("track-id"))
+	assert.Equal(t, "2020-01-02T03:04:06.000Z", plays[1].Get("timestamp"))
+}
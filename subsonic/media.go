@@ -0,0 +1,127 @@
+package subsonic
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// streamHeaders are headers of the track response passed to the client.
+var streamHeaders = []string{"Content-Type", "Content-Length", "Content-Range", "Accept-Ranges"}
+
+// stream proxies MP3 of the track from its download URL. Range requests
+// are passed through so that clients can seek.
+func (s *Server) stream(w http.ResponseWriter, r *http.Request) error {
+	id, err := intParam(r, "id")
+	if err != nil {
+		return err
+	}
+
+	downloadURL, err := s.client.Tracks().GetDownloadURL(r.Context(), id)
+	if err != nil {
+		return &Error{Code: ErrorNotFound, Message: fmt.Sprintf("track %d: %v", id, err)}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return err
+	}
+	if rangeHeader := r.Header.Get("Range"); rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+
+	resp, err := s.opts.HTTPClient.Do(req.WithContext(r.Context()))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("stream track %d: %s", id, resp.Status)
+	}
+
+	for _, header := range streamHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			w.Header().Set(header, value)
+		}
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "audio/mpeg")
+	}
+
+	w.WriteHeader(resp.StatusCode)
+	// Client may disconnect at any moment, error can't be reported anyway.
+	_, _ = io.Copy(w, resp.Body)
+
+	return nil
+}
+
+// getCoverArt proxies cover image. Cover art ID is Yandex.Music cover URI
+// like "avatars.yandex.net/get-music-content/.../%%" where "%%" is
+// replaced with the requested size.
+func (s *Server) getCoverArt(w http.ResponseWriter, r *http.Request) error {
+	uri, err := requiredParam(r, "id")
+	if err != nil {
+		return err
+	}
+
+	size := s.opts.CoverSize
+	if value := r.Form.Get("size"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			size = n
+		}
+	}
+
+	coverURL, ok := coverArtURL(uri, size)
+	if !ok {
+		// Don't let clients make the server fetch arbitrary URLs.
+		return &Error{Code: ErrorNotFound, Message: "cover art not found"}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, coverURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.opts.HTTPClient.Do(req.WithContext(r.Context()))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &Error{Code: ErrorNotFound, Message: "cover art not found: " + resp.Status}
+	}
+
+	for _, header := range []string{"Content-Type", "Content-Length"} {
+		if value := resp.Header.Get(header); value != "" {
+			w.Header().Set(header, value)
+		}
+	}
+
+	_, _ = io.Copy(w, resp.Body)
+	return nil
+}
+
+// coverArtURL returns URL of the cover image of the size. It reports false
+// if the URL isn't a plain URL of Yandex host.
+func coverArtURL(uri string, size int) (string, bool) {
+	raw := "https://" + strings.Replace(uri, "%%", fmt.Sprintf("%dx%d", size, size), 1)
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+	if u.User != nil || u.Port() != "" || u.RawQuery != "" || u.ForceQuery || u.Fragment != "" {
+		return "", false
+	}
+	host := u.Hostname()
+	if host != "yandex.net" && !strings.HasSuffix(host, ".yandex.net") {
+		return "", false
+	}
+
+	return u.String(), true
+}
@@ -0,0 +1,112 @@
+package subsonic
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// media requests binary content from Subsonic server.
+func media(t *testing.T, serverURL, method string, params url.Values, header http.Header) (*http.Response, []byte) {
+	t.Helper()
+
+	params.Set("u", "admin")
+	params.Set("p", "secret")
+
+	req, err := http.NewRequest(http.MethodGet, serverURL+"/rest/"+method+".view?"+params.Encode(), nil)
+	require.NoError(t, err)
+	for key := range header {
+		req.Header.Set(key, header.Get(key))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp, body
+}
+
+func TestServer_Stream(t *testing.T) {
+	mux, files, serverURL := setup(t)
+
+	mux.HandleFunc("/tracks/2/download-info", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[{"codec":"mp3","bitrateInKbps":192,"downloadInfoUrl":"download-info/2"}]}`)
+	})
+	mux.HandleFunc("/download-info/2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?>
+<download-info><host>storage.test</host><path>/music/2.mp3</path><ts>5e0d</ts><region>-1</region><s>salt</s></download-info>`)
+	})
+
+	sign := md5.Sum([]byte("XGRlBW9FXlekgbPrRHuSiA" + "music/2.mp3" + "salt"))
+	content := []byte("ID3 fake mp3 content")
+	files["storage.test/get-mp3/"+hex.EncodeToString(sign[:])+"/5e0d/music/2.mp3"] = func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/mpeg")
+		http.ServeContent(w, r, "2.mp3", time.Time{}, bytes.NewReader(content))
+	}
+
+	resp, body := media(t, serverURL, "stream", url.Values{"id": {"2"}}, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	assert.Equal(t, "audio/mpeg", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "bytes", resp.Header.Get("Accept-Ranges"))
+	assert.Equal(t, content, body)
+
+	resp, body = media(t, serverURL, "stream", url.Values{"id": {"2"}}, http.Header{"Range": {"bytes=4-7"}})
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode, string(body))
+	assert.Equal(t, "bytes 4-7/20", resp.Header.Get("Content-Range"))
+	assert.Equal(t, "4", resp.Header.Get("Content-Length"))
+	assert.Equal(t, "fake", string(body))
+
+	resp, body = media(t, serverURL, "stream", url.Values{"id": {"3"}, "f": {"json"}}, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `"status":"failed"`)
+	assert.Contains(t, string(body), `"code":70`)
+}
+
+func TestServer_GetCoverArt(t *testing.T) {
+	_, files, serverURL := setup(t)
+
+	files["avatars.yandex.net/get-music-content/help/200x200"] = func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("jpeg 200"))
+	}
+	files["avatars.yandex.net/get-music-content/help/400x400"] = func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("jpeg 400"))
+	}
+
+	id := "avatars.yandex.net/get-music-content/help/%%"
+
+	resp, body := media(t, serverURL, "getCoverArt", url.Values{"id": {id}}, nil)
+	assert.Equal(t, "image/jpeg", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "jpeg 400", string(body))
+
+	resp, body = media(t, serverURL, "getCoverArt", url.Values{"id": {id}, "size": {"200"}}, nil)
+	assert.Equal(t, "image/jpeg", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "jpeg 200", string(body))
+
+	for _, id := range []string{
+		"evil.example.com/yandex.net/%%",
+		"avatars.yandex.net.evil.com/%%",
+		"127.0.0.1:8080#.yandex.net/%%",
+		"127.0.0.1:8080?.yandex.net/%%",
+		"user@127.0.0.1#.yandex.net/%%",
+		"evil.com\\@avatars.yandex.net/%%",
+		"avatars.yandex.net:8080/%%",
+		"avatars.yandex.net/%%?redirect=evil.com",
+	} {
+		_, body = media(t, serverURL, "getCoverArt", url.Values{"id": {id}, "f": {"json"}}, nil)
+		assert.Contains(t, string(body), `"message":"cover art not found"`, id)
+	}
+}
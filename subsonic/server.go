@@ -0,0 +1,224 @@
+// Package subsonic implements Subsonic REST API server backed by
+// Yandex.Music account, so that Subsonic clients can browse and play
+// the library.
+//
+// Artists are the liked artists of the account, albums and songs are
+// addressed by Yandex.Music IDs and playlists by their kinds. Starring
+// likes tracks, albums and artists and scrobbling reports plays.
+package subsonic
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+// APIVersion is a version of Subsonic API implemented by the server.
+const APIVersion = "1.16.1"
+
+// Error codes of Subsonic API.
+const (
+	ErrorGeneric          = 0
+	ErrorMissingParameter = 10
+	ErrorWrongCredentials = 40
+	ErrorNotFound         = 70
+)
+
+const xmlns = "http://subsonic.org/restapi"
+
+type (
+	// Options are options of Server.
+	Options struct {
+		// Username and Password are credentials of Subsonic clients.
+		// Authentication is disabled if Username is empty.
+		Username string
+		Password string
+		// HTTPClient fetches streams and cover images.
+		// Defaults to http.DefaultClient.
+		HTTPClient yamusic.Doer
+		// CoverSize is a size of cover images if client doesn't request
+		// a size. Defaults to 400.
+		CoverSize int
+	}
+
+	// Server serves Subsonic API under "/rest/" path.
+	Server struct {
+		client *yamusic.Client
+		opts   Options
+		// methods write Response in requested format.
+		methods map[string]method
+		// media write binary content.
+		media map[string]func(http.ResponseWriter, *http.Request) error
+	}
+
+	method func(ctx context.Context, r *http.Request, resp *Response) error
+)
+
+// NewServer returns server which serves the library of the client.
+func NewServer(client *yamusic.Client, opts *Options) *Server {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	s := &Server{client: client, opts: *opts}
+	if s.opts.HTTPClient == nil {
+		s.opts.HTTPClient = http.DefaultClient
+	}
+	if s.opts.CoverSize <= 0 {
+		s.opts.CoverSize = 400
+	}
+
+	s.methods = map[string]method{
+		"ping":            s.ping,
+		"getLicense":      s.getLicense,
+		"getMusicFolders": s.getMusicFolders,
+		"getArtists":      s.getArtists,
+		"getAlbum":        s.getAlbum,
+		"getPlaylists":    s.getPlaylists,
+		"getPlaylist":     s.getPlaylist,
+		"search3":         s.search3,
+		"star":            s.star,
+		"unstar":          s.unstar,
+		"scrobble":        s.scrobble,
+	}
+	s.media = map[string]func(http.ResponseWriter, *http.Request) error{
+		"stream":      s.stream,
+		"download":    s.stream,
+		"getCoverArt": s.getCoverArt,
+	}
+
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/rest/"), ".view")
+
+	if err := r.ParseForm(); err != nil {
+		s.writeError(w, r, &Error{Code: ErrorGeneric, Message: err.Error()})
+		return
+	}
+
+	if err := s.authenticate(r); err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	if media, ok := s.media[name]; ok {
+		if err := media(w, r); err != nil {
+			s.writeError(w, r, err)
+		}
+		return
+	}
+
+	method, ok := s.methods[name]
+	if !ok {
+		s.writeError(w, r, &Error{Code: ErrorNotFound, Message: "unknown method " + name})
+		return
+	}
+
+	resp := newResponse()
+	if err := method(r.Context(), r, resp); err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	s.write(w, r, resp)
+}
+
+// authenticate checks either plain password "p" (optionally hex-encoded
+// with "enc:" prefix) or token "t" which is md5 of password and salt "s".
+func (s *Server) authenticate(r *http.Request) error {
+	if s.opts.Username == "" {
+		return nil
+	}
+
+	wrong := &Error{Code: ErrorWrongCredentials, Message: "wrong username or password"}
+
+	if r.Form.Get("u") == "" {
+		return &Error{Code: ErrorMissingParameter, Message: "required parameter is missing: u"}
+	}
+	if r.Form.Get("u") != s.opts.Username {
+		return wrong
+	}
+
+	if token := r.Form.Get("t"); token != "" {
+		sum := md5.Sum([]byte(s.opts.Password + r.Form.Get("s")))
+		if subtle.ConstantTimeCompare([]byte(strings.ToLower(token)), []byte(hex.EncodeToString(sum[:]))) != 1 {
+			return wrong
+		}
+		return nil
+	}
+
+	password := r.Form.Get("p")
+	if password == "" {
+		return &Error{Code: ErrorMissingParameter, Message: "required parameter is missing: p or t"}
+	}
+
+	if strings.HasPrefix(password, "enc:") {
+		decoded, err := hex.DecodeString(strings.TrimPrefix(password, "enc:"))
+		if err != nil {
+			return wrong
+		}
+		password = string(decoded)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(password), []byte(s.opts.Password)) != 1 {
+		return wrong
+	}
+
+	return nil
+}
+
+// write writes response as JSON if client requested "f=json" or as XML.
+// Subsonic API always responds with 200 OK, errors are in the body.
+func (s *Server) write(w http.ResponseWriter, r *http.Request, resp *Response) {
+	if r.Form.Get("f") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]*Response{"subsonic-response": resp})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		apiErr = &Error{Code: ErrorGeneric, Message: err.Error()}
+	}
+
+	resp := newResponse()
+	resp.Status = "failed"
+	resp.Error = apiErr
+	s.write(w, r, resp)
+}
+
+func newResponse() *Response {
+	return &Response{
+		Xmlns:   xmlns,
+		Status:  "ok",
+		Version: APIVersion,
+		Type:    "yamusic",
+	}
+}
+
+// requiredParam returns value of the parameter or error if it's missing.
+func requiredParam(r *http.Request, name string) (string, error) {
+	value := r.Form.Get(name)
+	if value == "" {
+		return "", &Error{Code: ErrorMissingParameter, Message: "required parameter is missing: " + name}
+	}
+
+	return value, nil
+}
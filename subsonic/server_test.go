@@ -0,0 +1,179 @@
+package subsonic
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storage serves requests of Subsonic server to hosts outside of the fake
+// API, i.e. track files and cover images.
+type storage map[string]http.HandlerFunc
+
+// setup returns mux of fake Yandex.Music API, storage of media files and
+// URL of Subsonic server with credentials "admin" and "secret".
+func setup(t *testing.T) (*http.ServeMux, storage, string) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	api := httptest.NewServer(mux)
+	t.Cleanup(api.Close)
+
+	files := storage{}
+	httpClient := yamusic.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		handler, ok := files[req.URL.Host+req.URL.Path]
+		if !ok {
+			handler = http.NotFound
+		}
+
+		recorder := httptest.NewRecorder()
+		handler(recorder, req)
+		return recorder.Result(), nil
+	})
+
+	baseURL, _ := url.Parse(api.URL + "/")
+	client := yamusic.NewClient(yamusic.BaseURL(baseURL), yamusic.AccessToken(2000, "accessToken"))
+
+	server := httptest.NewServer(NewServer(client, &Options{
+		Username:   "admin",
+		Password:   "secret",
+		HTTPClient: httpClient,
+	}))
+	t.Cleanup(server.Close)
+
+	return mux, files, server.URL
+}
+
+// get calls Subsonic method with credentials of the test server and returns
+// the response decoded from JSON.
+func get(t *testing.T, serverURL, method string, params url.Values) *Response {
+	t.Helper()
+
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("u", "admin")
+	params.Set("p", "secret")
+	params.Set("f", "json")
+	params.Set("v", "1.16.1")
+	params.Set("c", "test")
+
+	resp, err := http.Get(serverURL + "/rest/" + method + ".view?" + params.Encode())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var body struct {
+		Response *Response `json:"subsonic-response"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.NotNil(t, body.Response)
+
+	return body.Response
+}
+
+func TestServer_Ping(t *testing.T) {
+	_, _, serverURL := setup(t)
+
+	resp := get(t, serverURL, "ping", nil)
+	assert.Equal(t, "ok", resp.Status)
+	assert.Equal(t, APIVersion, resp.Version)
+	assert.Nil(t, resp.Error)
+}
+
+func TestServer_XML(t *testing.T) {
+	_, _, serverURL := setup(t)
+
+	resp, err := http.Get(serverURL + "/rest/getLicense?u=admin&p=secret")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, "text/xml; charset=utf-8", resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(body), xml.Header), string(body))
+	assert.Contains(t, string(body),
+		`<subsonic-response xmlns="http://subsonic.org/restapi" status="ok" version="1.16.1" type="yamusic"><license valid="true"></license></subsonic-response>`)
+}
+
+func TestServer_Authentication(t *testing.T) {
+	_, _, serverURL := setup(t)
+
+	sum := md5.Sum([]byte("secret" + "c19b2d"))
+	token := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name  string
+		query string
+		code  int
+	}{
+		{"plain password", "u=admin&p=secret", -1},
+		{"encoded password", "u=admin&p=enc:" + hex.EncodeToString([]byte("secret")), -1},
+		{"token", "u=admin&t=" + token + "&s=c19b2d", -1},
+		{"wrong token", "u=admin&t=" + token + "&s=salt", ErrorWrongCredentials},
+		{"wrong password", "u=admin&p=password", ErrorWrongCredentials},
+		{"wrong user", "u=root&p=secret", ErrorWrongCredentials},
+		{"missing user", "p=secret", ErrorMissingParameter},
+		{"missing password", "u=admin", ErrorMissingParameter},
+	}
+
+	for _, tt := range tests {
+		resp, err := http.Get(serverURL + "/rest/ping?f=json&" + tt.query)
+		require.NoError(t, err, tt.name)
+
+		var body struct {
+			Response Response `json:"subsonic-response"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body), tt.name)
+		resp.Body.Close()
+
+		if tt.code < 0 {
+			assert.Equal(t, "ok", body.Response.Status, tt.name)
+			continue
+		}
+
+		assert.Equal(t, "failed", body.Response.Status, tt.name)
+		require.NotNil(t, body.Response.Error, tt.name)
+		assert.Equal(t, tt.code, body.Response.Error.Code, tt.name)
+	}
+}
+
+func TestServer_UnknownMethod(t *testing.T) {
+	_, _, serverURL := setup(t)
+
+	resp := get(t, serverURL, "getPodcasts", nil)
+	assert.Equal(t, "failed", resp.Status)
+	assert.Equal(t, &Error{Code: ErrorNotFound, Message: "unknown method getPodcasts"}, resp.Error)
+}
+
+func TestServer_APIError(t *testing.T) {
+	mux, _, serverURL := setup(t)
+
+	mux.HandleFunc("/users/2000/playlists/5", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"name":"playlist-not-found","message":"Playlist not found"}}`))
+	})
+
+	resp := get(t, serverURL, "getPlaylist", url.Values{"id": {"5"}})
+	assert.Equal(t, "failed", resp.Status)
+	assert.Equal(t, &Error{
+		Code:    ErrorNotFound,
+		Message: "404 Not Found: playlist-not-found: Playlist not found",
+	}, resp.Error)
+
+	resp = get(t, serverURL, "getPlaylist", nil)
+	assert.Equal(t, &Error{Code: ErrorMissingParameter, Message: "required parameter is missing: id"}, resp.Error)
+}
@@ -0,0 +1,264 @@
+package subsonic
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ndrewnee/go-yamusic/yamusic"
+)
+
+type (
+	// Response is a root element of every Subsonic API response.
+	Response struct {
+		XMLName xml.Name `xml:"subsonic-response" json:"-"`
+		Xmlns   string   `xml:"xmlns,attr" json:"-"`
+		Status  string   `xml:"status,attr" json:"status"`
+		Version string   `xml:"version,attr" json:"version"`
+		Type    string   `xml:"type,attr" json:"type"`
+
+		Error         *Error         `xml:"error,omitempty" json:"error,omitempty"`
+		License       *License       `xml:"license,omitempty" json:"license,omitempty"`
+		MusicFolders  *MusicFolders  `xml:"musicFolders,omitempty" json:"musicFolders,omitempty"`
+		Artists       *Artists       `xml:"artists,omitempty" json:"artists,omitempty"`
+		Album         *Album         `xml:"album,omitempty" json:"album,omitempty"`
+		Playlists     *Playlists     `xml:"playlists,omitempty" json:"playlists,omitempty"`
+		Playlist      *Playlist      `xml:"playlist,omitempty" json:"playlist,omitempty"`
+		SearchResult3 *SearchResult3 `xml:"searchResult3,omitempty" json:"searchResult3,omitempty"`
+	}
+
+	// Error is an error of Subsonic API.
+	Error struct {
+		Code    int    `xml:"code,attr" json:"code"`
+		Message string `xml:"message,attr" json:"message"`
+	}
+
+	// License is a response of getLicense.
+	License struct {
+		Valid bool `xml:"valid,attr" json:"valid"`
+	}
+
+	// MusicFolders is a response of getMusicFolders.
+	MusicFolders struct {
+		MusicFolder []MusicFolder `xml:"musicFolder" json:"musicFolder"`
+	}
+
+	// MusicFolder is a music folder. The server has the only folder which
+	// is the Yandex.Music library.
+	MusicFolder struct {
+		ID   int    `xml:"id,attr" json:"id"`
+		Name string `xml:"name,attr" json:"name"`
+	}
+
+	// Artists is a response of getArtists.
+	Artists struct {
+		IgnoredArticles string  `xml:"ignoredArticles,attr" json:"ignoredArticles"`
+		Index           []Index `xml:"index" json:"index"`
+	}
+
+	// Index is a group of artists by the first letter.
+	Index struct {
+		Name   string   `xml:"name,attr" json:"name"`
+		Artist []Artist `xml:"artist" json:"artist"`
+	}
+
+	// Artist is an artist.
+	Artist struct {
+		ID         string `xml:"id,attr" json:"id"`
+		Name       string `xml:"name,attr" json:"name"`
+		CoverArt   string `xml:"coverArt,attr,omitempty" json:"coverArt,omitempty"`
+		AlbumCount int    `xml:"albumCount,attr" json:"albumCount"`
+		Starred    string `xml:"starred,attr,omitempty" json:"starred,omitempty"`
+	}
+
+	// Album is an album with songs in getAlbum response.
+	Album struct {
+		ID        string `xml:"id,attr" json:"id"`
+		Name      string `xml:"name,attr" json:"name"`
+		Artist    string `xml:"artist,attr,omitempty" json:"artist,omitempty"`
+		ArtistID  string `xml:"artistId,attr,omitempty" json:"artistId,omitempty"`
+		CoverArt  string `xml:"coverArt,attr,omitempty" json:"coverArt,omitempty"`
+		SongCount int    `xml:"songCount,attr" json:"songCount"`
+		Duration  int    `xml:"duration,attr" json:"duration"`
+		Year      int    `xml:"year,attr,omitempty" json:"year,omitempty"`
+		Genre     string `xml:"genre,attr,omitempty" json:"genre,omitempty"`
+		Starred   string `xml:"starred,attr,omitempty" json:"starred,omitempty"`
+		Song      []Song `xml:"song,omitempty" json:"song,omitempty"`
+	}
+
+	// Song is a track.
+	Song struct {
+		ID          string `xml:"id,attr" json:"id"`
+		Parent      string `xml:"parent,attr,omitempty" json:"parent,omitempty"`
+		IsDir       bool   `xml:"isDir,attr" json:"isDir"`
+		Title       string `xml:"title,attr" json:"title"`
+		Album       string `xml:"album,attr,omitempty" json:"album,omitempty"`
+		Artist      string `xml:"artist,attr,omitempty" json:"artist,omitempty"`
+		Track       int    `xml:"track,attr,omitempty" json:"track,omitempty"`
+		DiscNumber  int    `xml:"discNumber,attr,omitempty" json:"discNumber,omitempty"`
+		Year        int    `xml:"year,attr,omitempty" json:"year,omitempty"`
+		Genre       string `xml:"genre,attr,omitempty" json:"genre,omitempty"`
+		CoverArt    string `xml:"coverArt,attr,omitempty" json:"coverArt,omitempty"`
+		ContentType string `xml:"contentType,attr" json:"contentType"`
+		Suffix      string `xml:"suffix,attr" json:"suffix"`
+		Duration    int    `xml:"duration,attr" json:"duration"`
+		AlbumID     string `xml:"albumId,attr,omitempty" json:"albumId,omitempty"`
+		ArtistID    string `xml:"artistId,attr,omitempty" json:"artistId,omitempty"`
+		Type        string `xml:"type,attr" json:"type"`
+	}
+
+	// Playlists is a response of getPlaylists.
+	Playlists struct {
+		Playlist []Playlist `xml:"playlist" json:"playlist"`
+	}
+
+	// Playlist is a playlist. Entries are set only in getPlaylist response.
+	Playlist struct {
+		ID        string `xml:"id,attr" json:"id"`
+		Name      string `xml:"name,attr" json:"name"`
+		Comment   string `xml:"comment,attr,omitempty" json:"comment,omitempty"`
+		Owner     string `xml:"owner,attr,omitempty" json:"owner,omitempty"`
+		Public    bool   `xml:"public,attr" json:"public"`
+		SongCount int    `xml:"songCount,attr" json:"songCount"`
+		Duration  int    `xml:"duration,attr" json:"duration"`
+		Created   string `xml:"created,attr,omitempty" json:"created,omitempty"`
+		Changed   string `xml:"changed,attr,omitempty" json:"changed,omitempty"`
+		CoverArt  string `xml:"coverArt,attr,omitempty" json:"coverArt,omitempty"`
+		Entry     []Song `xml:"entry,omitempty" json:"entry,omitempty"`
+	}
+
+	// SearchResult3 is a response of search3.
+	SearchResult3 struct {
+		Artist []Artist `xml:"artist" json:"artist,omitempty"`
+		Album  []Album  `xml:"album" json:"album,omitempty"`
+		Song   []Song   `xml:"song" json:"song,omitempty"`
+	}
+)
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("subsonic: error %d: %s", e.Code, e.Message)
+}
+
+// newSong converts Yandex.Music track to Subsonic song.
+func newSong(track yamusic.Track) Song {
+	song := Song{
+		ID:          track.ID,
+		Title:       track.Title,
+		Artist:      track.ArtistNames(),
+		CoverArt:    track.CoverURI,
+		ContentType: "audio/mpeg",
+		Suffix:      "mp3",
+		Duration:    track.DurationMs / 1000,
+		Type:        "music",
+	}
+
+	if len(track.Artists) > 0 {
+		song.ArtistID = strconv.Itoa(track.Artists[0].ID)
+	}
+
+	if len(track.Albums) > 0 {
+		album := track.Albums[0]
+		song.Parent = strconv.Itoa(album.ID)
+		song.AlbumID = song.Parent
+		song.Album = album.Title
+		song.Year = album.Year
+		song.Genre = album.Genre
+		song.Track = album.TrackPosition.Index
+		song.DiscNumber = album.TrackPosition.Volume
+		if song.CoverArt == "" {
+			song.CoverArt = album.CoverURI
+		}
+	}
+
+	return song
+}
+
+// newAlbum converts Yandex.Music album to Subsonic album without songs.
+func newAlbum(album yamusic.Album) Album {
+	a := Album{
+		ID:        strconv.Itoa(album.ID),
+		Name:      album.Title,
+		Artist:    album.Artists.Names(),
+		CoverArt:  album.CoverURI,
+		SongCount: album.TrackCount,
+		Year:      album.Year,
+		Genre:     album.Genre,
+	}
+
+	if len(album.Artists) > 0 {
+		a.ArtistID = strconv.Itoa(album.Artists[0].ID)
+	}
+
+	return a
+}
+
+// newArtist converts Yandex.Music artist to Subsonic artist.
+func newArtist(artist yamusic.Artist) Artist {
+	return Artist{
+		ID:       strconv.Itoa(artist.ID),
+		Name:     artist.Name,
+		CoverArt: artist.Cover.URI,
+	}
+}
+
+// newPlaylist converts Yandex.Music playlist to Subsonic playlist without
+// entries.
+func newPlaylist(playlist yamusic.PlaylistsResult) Playlist {
+	return Playlist{
+		ID:        strconv.Itoa(playlist.Kind),
+		Name:      playlist.Title,
+		Comment:   playlist.Description,
+		Owner:     playlist.Owner.Login,
+		Public:    playlist.Visibility == "public",
+		SongCount: playlist.TrackCount,
+		Duration:  playlist.DurationMs / 1000,
+		Created:   formatTime(playlist.Created),
+		Changed:   formatTime(playlist.Modified),
+		CoverArt:  playlist.Cover.URI,
+	}
+}
+
+// newArtists groups artists by the first letter of their names.
+func newArtists(artists []Artist) *Artists {
+	result := &Artists{IgnoredArticles: "The"}
+
+	indexes := make(map[string]int)
+	for _, artist := range artists {
+		name := indexName(artist.Name)
+		i, ok := indexes[name]
+		if !ok {
+			i = len(result.Index)
+			indexes[name] = i
+			result.Index = append(result.Index, Index{Name: name})
+		}
+		result.Index[i].Artist = append(result.Index[i].Artist, artist)
+	}
+
+	return result
+}
+
+// indexName returns upper-cased first letter of the name without "The"
+// article or "#" if it doesn't start with a letter.
+func indexName(name string) string {
+	name = strings.TrimPrefix(name, "The ")
+	for _, r := range name {
+		if unicode.IsLetter(r) {
+			return string(unicode.ToUpper(r))
+		}
+		break
+	}
+
+	return "#"
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
@@ -18,3 +18,11 @@ func TestAccountGetStatus(t *testing.T) {
 	assert.NotZero(t, accountStatus)
 	assert.NotZero(t, accountStatus.Result)
 }
+
+func TestAccountSettings(t *testing.T) {
+	settings, resp, err := client.Account().Settings(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotZero(t, settings.Result.UID)
+}
@@ -0,0 +1,31 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLanding(t *testing.T) {
+	ctx := context.Background()
+	t.Run("Get new releases and resolve albums", func(t *testing.T) {
+		releases, resp, err := client.Landing().NewReleases(ctx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NotEmpty(t, releases.Result.NewReleases)
+
+		albums, _, err := client.Albums().GetMany(ctx, releases.Result.NewReleases[:1])
+		require.NoError(t, err)
+		require.NotEmpty(t, albums.Result)
+	})
+	t.Run("Get chart", func(t *testing.T) {
+		chart, resp, err := client.Landing().Chart(ctx, "")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NotEmpty(t, chart.Result.Chart.Tracks)
+	})
+}
@@ -35,10 +35,24 @@ func TestSearchAll(t *testing.T) {
 	assert.NotZero(t, result.Result.Best)
 }
 
+func TestSearchPlaylists(t *testing.T) {
+	result := testSearch(t, client.Search().Playlists)
+	assert.NotEmpty(t, result.Result.Playlists.Results)
+}
+
+func TestSearchSuggest(t *testing.T) {
+	result, resp, err := client.Search().Suggest(context.Background(), "Oxxy")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, result.Result.Suggestions)
+}
+
 func testSearch(t *testing.T, searchFunc func(
 	ctx context.Context,
 	query string,
 	opts *yamusic.SearchOptions,
+	reqOpts ...yamusic.RequestOption,
 ) (*yamusic.SearchResp, *http.Response, error),
 ) *yamusic.SearchResp {
 	ctx := context.Background()
@@ -22,6 +22,18 @@ func TestTracks(t *testing.T) {
 		require.NotZero(t, result.Result)
 		require.Equal(t, result.Result[0].ID, strconv.Itoa(kind))
 	})
+	t.Run("Get several tracks", func(t *testing.T) {
+		result, resp, err := client.Tracks().GetMany(ctx, []string{strconv.Itoa(kind)})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Len(t, result.Result, 1)
+	})
+	t.Run("Get track full info", func(t *testing.T) {
+		info, err := client.Tracks().FullInfo(ctx, kind)
+		require.NoError(t, err)
+		require.Equal(t, info.Track.ID, strconv.Itoa(kind))
+		require.NotNil(t, info.Album)
+	})
 	t.Run("Get track download URL", func(t *testing.T) {
 		url, err := client.Tracks().GetDownloadURL(ctx, kind)
 		require.NoError(t, err)
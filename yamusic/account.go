@@ -3,9 +3,26 @@ package yamusic
 import (
 	"context"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 )
 
+// Permissions from AccountPermissions' Values and Default.
+const (
+	PermissionLandingPlay         = "landing-play"
+	PermissionFeedPlay            = "feed-play"
+	PermissionRadioPlay           = "radio-play"
+	PermissionMixPlay             = "mix-play"
+	PermissionLibraryPlay         = "library-play"
+	PermissionLibraryCache        = "library-cache"
+	PermissionPlayPremium         = "play-premium"
+	PermissionPlayRadioFullTracks = "play-radio-full-tracks"
+	PermissionRadioSkips          = "radio-skips"
+	PermissionHighQuality         = "high-quality"
+	PermissionLossless            = "lossless"
+)
+
 type (
 	// AccountService is a service to deal with accounts.
 	AccountService struct {
@@ -33,23 +50,132 @@ type (
 					Phone string `json:"phone"`
 				} `json:"passport-phones"`
 			} `json:"account"`
-			Permissions struct {
-				Until   time.Time `json:"until"`
-				Values  []string  `json:"values"`
-				Default []string  `json:"default"`
-			} `json:"permissions"`
+			Permissions  AccountPermissions `json:"permissions"`
 			Subscription struct {
 				CanStartTrial bool `json:"canStartTrial"`
 				Mcdonalds     bool `json:"mcdonalds"`
 			} `json:"subscription"`
 		} `json:"result"`
 	}
+	// AccountPermissions describes what account is allowed to do.
+	// Values are valid until Until, after that only Default are granted.
+	AccountPermissions struct {
+		Until   time.Time `json:"until"`
+		Values  []string  `json:"values"`
+		Default []string  `json:"default"`
+	}
+	// AccountSettings describes user's settings
+	AccountSettings struct {
+		UID                     int       `json:"uid"`
+		Theme                   string    `json:"theme"`
+		ExplicitContentEnabled  bool      `json:"explicitContentEnabled"`
+		AutoPlayRadio           bool      `json:"autoPlayRadio"`
+		VolumePercents          int       `json:"volumePercents"`
+		LastFmScrobblingEnabled bool      `json:"lastFmScrobblingEnabled"`
+		ShuffleEnabled          bool      `json:"shuffleEnabled"`
+		AdsDisabled             bool      `json:"adsDisabled"`
+		DiskEnabled             bool      `json:"diskEnabled"`
+		ShowDiskTracksInLibrary bool      `json:"showDiskTracksInLibrary"`
+		UserMusicVisibility     string    `json:"userMusicVisibility"`
+		UserSocialVisibility    string    `json:"userSocialVisibility"`
+		Modified                time.Time `json:"modified"`
+	}
+	// AccountSettingsResp describes account get and update settings methods response
+	AccountSettingsResp struct {
+		InvocationInfo InvocationInfo  `json:"invocationInfo"`
+		Error          Error           `json:"error"`
+		Result         AccountSettings `json:"result"`
+	}
+	// AccountUpdateSettingsOptions are options for UpdateSettings method.
+	// Only non-nil fields are updated.
+	AccountUpdateSettingsOptions struct {
+		Theme                   *string
+		ExplicitContentEnabled  *bool
+		AutoPlayRadio           *bool
+		VolumePercents          *int
+		LastFmScrobblingEnabled *bool
+		ShuffleEnabled          *bool
+	}
+	// AccountPermissionAlertsResp describes account get permission alerts method response
+	AccountPermissionAlertsResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         struct {
+			Alerts []string `json:"alerts"`
+		} `json:"result"`
+	}
+	// AccountExperimentsResp describes account get experiments method response
+	AccountExperimentsResp struct {
+		InvocationInfo InvocationInfo    `json:"invocationInfo"`
+		Error          Error             `json:"error"`
+		Result         map[string]string `json:"result"`
+	}
+	// AccountConsumePromoCodeResp describes account consume promo code method response
+	AccountConsumePromoCodeResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         struct {
+			Status     string `json:"status"`
+			StatusDesc string `json:"statusDesc"`
+		} `json:"result"`
+	}
 )
 
+// Has reports whether the permission is granted now.
+func (p AccountPermissions) Has(permission string) bool {
+	return p.HasAt(permission, time.Now())
+}
+
+// HasAt reports whether the permission is granted at the moment.
+func (p AccountPermissions) HasAt(permission string, at time.Time) bool {
+	values := p.Values
+	if !p.Until.IsZero() && at.After(p.Until) {
+		values = p.Default
+	}
+
+	for _, value := range values {
+		if value == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanStreamHQ reports whether the account can play tracks in high quality.
+func (p AccountPermissions) CanStreamHQ() bool {
+	return p.Has(PermissionHighQuality)
+}
+
+// CanStreamLossless reports whether the account can play lossless tracks.
+func (p AccountPermissions) CanStreamLossless() bool {
+	return p.Has(PermissionLossless)
+}
+
+// CanPlayFullTracks reports whether the account can play full tracks
+// and not only previews.
+func (p AccountPermissions) CanPlayFullTracks() bool {
+	return p.Has(PermissionPlayPremium)
+}
+
+// CanCacheTracks reports whether the account can save tracks for offline use.
+func (p AccountPermissions) CanCacheTracks() bool {
+	return p.Has(PermissionLibraryCache)
+}
+
+// CanSkipRadio reports whether the account can skip radio tracks.
+func (p AccountPermissions) CanSkipRadio() bool {
+	return p.Has(PermissionRadioSkips)
+}
+
 // GetStatus returns account's status
 func (s *AccountService) GetStatus(
 	ctx context.Context,
+	reqOpts ...RequestOption,
 ) (*AccountStatusResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "AccountService.GetStatus", reqOpts)
+	defer call.end()
+
 	req, err := s.client.NewRequest(http.MethodGet, "account/status", nil)
 	if err != nil {
 		return nil, nil, err
@@ -59,3 +185,128 @@ func (s *AccountService) GetStatus(
 	resp, err := s.client.Do(ctx, req, accountStatus)
 	return accountStatus, resp, err
 }
+
+// Settings returns account's settings
+func (s *AccountService) Settings(
+	ctx context.Context,
+	reqOpts ...RequestOption,
+) (*AccountSettingsResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "AccountService.Settings", reqOpts)
+	defer call.end()
+
+	req, err := s.client.NewRequest(http.MethodGet, "account/settings", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	settings := new(AccountSettingsResp)
+	resp, err := s.client.Do(ctx, req, settings)
+	return settings, resp, err
+}
+
+// UpdateSettings updates account's settings and returns updated settings
+func (s *AccountService) UpdateSettings(
+	ctx context.Context,
+	opts *AccountUpdateSettingsOptions,
+	reqOpts ...RequestOption,
+) (*AccountSettingsResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "AccountService.UpdateSettings", reqOpts)
+	defer call.end()
+
+	if opts == nil {
+		opts = &AccountUpdateSettingsOptions{}
+	}
+
+	form := url.Values{}
+	if opts.Theme != nil {
+		form.Set("theme", *opts.Theme)
+	}
+	if opts.ExplicitContentEnabled != nil {
+		form.Set("explicitContentEnabled", strconv.FormatBool(*opts.ExplicitContentEnabled))
+	}
+	if opts.AutoPlayRadio != nil {
+		form.Set("autoPlayRadio", strconv.FormatBool(*opts.AutoPlayRadio))
+	}
+	if opts.VolumePercents != nil {
+		form.Set("volumePercents", strconv.Itoa(*opts.VolumePercents))
+	}
+	if opts.LastFmScrobblingEnabled != nil {
+		form.Set("lastFmScrobblingEnabled", strconv.FormatBool(*opts.LastFmScrobblingEnabled))
+	}
+	if opts.ShuffleEnabled != nil {
+		form.Set("shuffleEnabled", strconv.FormatBool(*opts.ShuffleEnabled))
+	}
+
+	req, err := s.client.NewRequest(http.MethodPost, "account/settings", form)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	settings := new(AccountSettingsResp)
+	resp, err := s.client.Do(ctx, req, settings)
+	return settings, resp, err
+}
+
+// PermissionAlerts returns alerts about account's permissions
+// like expiring subscription
+func (s *AccountService) PermissionAlerts(
+	ctx context.Context,
+	reqOpts ...RequestOption,
+) (*AccountPermissionAlertsResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "AccountService.PermissionAlerts", reqOpts)
+	defer call.end()
+
+	req, err := s.client.NewRequest(http.MethodGet, "permission-alerts", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	alerts := new(AccountPermissionAlertsResp)
+	resp, err := s.client.Do(ctx, req, alerts)
+	return alerts, resp, err
+}
+
+// Experiments returns experiments enabled for the account
+func (s *AccountService) Experiments(
+	ctx context.Context,
+	reqOpts ...RequestOption,
+) (*AccountExperimentsResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "AccountService.Experiments", reqOpts)
+	defer call.end()
+
+	req, err := s.client.NewRequest(http.MethodGet, "account/experiments", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	experiments := new(AccountExperimentsResp)
+	resp, err := s.client.Do(ctx, req, experiments)
+	return experiments, resp, err
+}
+
+// ConsumePromoCode activates promo code for the account. Language is
+// language of the status description like "ru" or "en"
+func (s *AccountService) ConsumePromoCode(
+	ctx context.Context,
+	code string,
+	language string,
+	reqOpts ...RequestOption,
+) (*AccountConsumePromoCodeResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "AccountService.ConsumePromoCode", reqOpts)
+	defer call.end()
+
+	form := url.Values{}
+	form.Set("code", code)
+	if language != "" {
+		form.Set("language", language)
+	}
+
+	req, err := s.client.NewRequest(http.MethodPost, "account/consume-promo-code", form)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	promo := new(AccountConsumePromoCodeResp)
+	resp, err := s.client.Do(ctx, req, promo)
+	return promo, resp, err
+}
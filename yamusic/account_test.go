@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -30,3 +31,127 @@ func TestAccountService_GetStatus(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
 }
+
+func TestAccountService_Settings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &AccountSettingsResp{}
+	want.InvocationInfo.ReqID = "Account.Settings"
+	want.Result.VolumePercents = 75
+
+	mux.HandleFunc("/account/settings", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	result, _, err := client.Account().Settings(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 75, result.Result.VolumePercents)
+}
+
+func TestAccountService_UpdateSettings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &AccountSettingsResp{}
+	want.InvocationInfo.ReqID = "Account.UpdateSettings"
+
+	mux.HandleFunc("/account/settings", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "dark", r.FormValue("theme"))
+		assert.Equal(t, "50", r.FormValue("volumePercents"))
+		assert.Equal(t, "true", r.FormValue("lastFmScrobblingEnabled"))
+		_, ok := r.PostForm["autoPlayRadio"]
+		assert.False(t, ok)
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	theme := "dark"
+	volume := 50
+	scrobbling := true
+	result, _, err := client.Account().UpdateSettings(
+		context.Background(),
+		&AccountUpdateSettingsOptions{
+			Theme:                   &theme,
+			VolumePercents:          &volume,
+			LastFmScrobblingEnabled: &scrobbling,
+		},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
+}
+
+func TestAccountService_PermissionAlerts(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/permission-alerts", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		fmt.Fprint(w, `{"result": {"alerts": ["subscription-expires"]}}`)
+	})
+
+	result, _, err := client.Account().PermissionAlerts(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"subscription-expires"}, result.Result.Alerts)
+}
+
+func TestAccountService_Experiments(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/account/experiments", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		fmt.Fprint(w, `{"result": {"newPlayer": "on"}}`)
+	})
+
+	result, _, err := client.Account().Experiments(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "on", result.Result["newPlayer"])
+}
+
+func TestAccountService_ConsumePromoCode(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/account/consume-promo-code", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "CODE", r.FormValue("code"))
+		assert.Equal(t, "en", r.FormValue("language"))
+		fmt.Fprint(w, `{"result": {"status": "success"}}`)
+	})
+
+	result, _, err := client.Account().ConsumePromoCode(context.Background(), "CODE", "en")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "success", result.Result.Status)
+}
+
+func TestAccountPermissions_Has(t *testing.T) {
+	until := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	permissions := AccountPermissions{
+		Until:   until,
+		Values:  []string{PermissionHighQuality, PermissionLossless},
+		Default: []string{PermissionRadioPlay},
+	}
+
+	assert.True(t, permissions.HasAt(PermissionLossless, until.Add(-time.Hour)))
+	assert.False(t, permissions.HasAt(PermissionLossless, until.Add(time.Hour)))
+	assert.True(t, permissions.HasAt(PermissionRadioPlay, until.Add(time.Hour)))
+
+	permissions.Until = time.Time{}
+	assert.True(t, permissions.CanStreamHQ())
+	assert.True(t, permissions.CanStreamLossless())
+	assert.False(t, permissions.CanCacheTracks())
+}
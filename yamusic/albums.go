@@ -0,0 +1,111 @@
+package yamusic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type (
+	// AlbumsService is a service to deal with albums.
+	AlbumsService struct {
+		client *Client
+	}
+	// AlbumsGetResp describes get album method response
+	AlbumsGetResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         Album          `json:"result"`
+	}
+	// AlbumsGetWithTracksResp describes get album with tracks method response
+	AlbumsGetWithTracksResp struct {
+		InvocationInfo InvocationInfo  `json:"invocationInfo"`
+		Error          Error           `json:"error"`
+		Result         AlbumWithTracks `json:"result"`
+	}
+	// AlbumWithTracks is album with its tracks grouped by volumes (discs)
+	AlbumWithTracks struct {
+		Album
+		Volumes [][]Track `json:"volumes"`
+	}
+	// AlbumsGetManyResp describes get several albums method response
+	AlbumsGetManyResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         Albums         `json:"result"`
+	}
+)
+
+// Get returns album by its ID
+func (s *AlbumsService) Get(
+	ctx context.Context,
+	id int,
+	reqOpts ...RequestOption,
+) (*AlbumsGetResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "AlbumsService.Get", reqOpts)
+	defer call.end()
+
+	uri := fmt.Sprintf("albums/%v", id)
+	req, err := s.client.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	album := new(AlbumsGetResp)
+	resp, err := s.client.Do(ctx, req, album)
+	return album, resp, err
+}
+
+// GetWithTracks returns album by its ID with all its tracks
+func (s *AlbumsService) GetWithTracks(
+	ctx context.Context,
+	id int,
+	reqOpts ...RequestOption,
+) (*AlbumsGetWithTracksResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "AlbumsService.GetWithTracks", reqOpts)
+	defer call.end()
+
+	uri := fmt.Sprintf("albums/%v/with-tracks", id)
+	req, err := s.client.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	album := new(AlbumsGetWithTracksResp)
+	resp, err := s.client.Do(ctx, req, album)
+	return album, resp, err
+}
+
+// GetMany returns several albums by their IDs in one request
+func (s *AlbumsService) GetMany(
+	ctx context.Context,
+	ids []int,
+	reqOpts ...RequestOption,
+) (*AlbumsGetManyResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "AlbumsService.GetMany", reqOpts)
+	defer call.end()
+
+	form := url.Values{}
+	form.Set("album-ids", joinInts(ids))
+
+	req, err := s.client.NewRequest(http.MethodPost, "albums", form)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	albums := new(AlbumsGetManyResp)
+	resp, err := s.client.Do(ctx, req, albums)
+	return albums, resp, err
+}
+
+// joinInts joins ints with comma.
+func joinInts(ids []int) string {
+	stringIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		stringIDs = append(stringIDs, strconv.Itoa(id))
+	}
+	return strings.Join(stringIDs, ",")
+}
@@ -0,0 +1,85 @@
+package yamusic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlbumsService_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &AlbumsGetResp{}
+	want.InvocationInfo.ReqID = "Albums.Get"
+
+	id := 42
+
+	mux.HandleFunc(
+		fmt.Sprintf("/albums/%d", id),
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+			b, err := json.Marshal(want)
+			assert.NoError(t, err)
+			fmt.Fprint(w, string(b))
+		},
+	)
+
+	result, _, err := client.Albums().Get(context.Background(), id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
+}
+
+func TestAlbumsService_GetWithTracks(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &AlbumsGetWithTracksResp{}
+	want.InvocationInfo.ReqID = "Albums.GetWithTracks"
+
+	id := 42
+
+	mux.HandleFunc(
+		fmt.Sprintf("/albums/%d/with-tracks", id),
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+			b, err := json.Marshal(want)
+			assert.NoError(t, err)
+			fmt.Fprint(w, string(b))
+		},
+	)
+
+	result, _, err := client.Albums().GetWithTracks(context.Background(), id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
+}
+
+func TestAlbumsService_GetMany(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &AlbumsGetManyResp{}
+	want.InvocationInfo.ReqID = "Albums.GetMany"
+
+	mux.HandleFunc("/albums", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+		assert.Equal(t, "1,2,3", r.FormValue("album-ids"))
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	result, _, err := client.Albums().GetMany(context.Background(), []int{1, 2, 3})
+
+	assert.NoError(t, err)
+	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
+}
@@ -0,0 +1,42 @@
+package yamusic
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type (
+	// ArtistsService is a service to deal with artists.
+	ArtistsService struct {
+		client *Client
+	}
+	// ArtistsGetManyResp describes get several artists method response
+	ArtistsGetManyResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         Artists        `json:"result"`
+	}
+)
+
+// GetMany returns several artists by their IDs in one request
+func (s *ArtistsService) GetMany(
+	ctx context.Context,
+	ids []int,
+	reqOpts ...RequestOption,
+) (*ArtistsGetManyResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "ArtistsService.GetMany", reqOpts)
+	defer call.end()
+
+	form := url.Values{}
+	form.Set("artist-ids", joinInts(ids))
+
+	req, err := s.client.NewRequest(http.MethodPost, "artists", form)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	artists := new(ArtistsGetManyResp)
+	resp, err := s.client.Do(ctx, req, artists)
+	return artists, resp, err
+}
@@ -0,0 +1,33 @@
+package yamusic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtistsService_GetMany(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &ArtistsGetManyResp{}
+	want.InvocationInfo.ReqID = "Artists.GetMany"
+
+	mux.HandleFunc("/artists", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+		assert.Equal(t, "1,2", r.FormValue("artist-ids"))
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	result, _, err := client.Artists().GetMany(context.Background(), []int{1, 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
+}
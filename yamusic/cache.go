@@ -0,0 +1,507 @@
+package yamusic
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStatusHeader is set on responses served by the cache middleware.
+// Its value is "HIT" for fresh cached responses and "REVALIDATED" for
+// cached responses confirmed by the server with 304 Not Modified.
+const CacheStatusHeader = "X-Yamusic-Cache"
+
+// DefaultCacheTTLs are TTLs of rarely changing endpoints used by
+// CacheMiddleware if CacheOptions.TTLs is nil. Keys are URL path prefixes.
+var DefaultCacheTTLs = map[string]time.Duration{
+	"genres":   24 * time.Hour,
+	"albums/":  time.Hour,
+	"artists/": time.Hour,
+	"tracks/":  time.Hour,
+}
+
+// uncachedPathSuffixes are suffixes of URL paths which are never cached
+// whatever TTLs are. Download info holds signed storage URLs which expire
+// in about a minute.
+var uncachedPathSuffixes = []string{
+	"/download-info",
+}
+
+// cacheKeyHeaders are request headers which change the response, so that
+// they are part of the cache key.
+var cacheKeyHeaders = []string{
+	"Authorization",
+	"Accept-Language",
+}
+
+type (
+	// CacheEntry is a cached response.
+	CacheEntry struct {
+		StatusCode   int         `json:"statusCode"`
+		Header       http.Header `json:"header"`
+		Body         []byte      `json:"body"`
+		Expires      time.Time   `json:"expires"`
+		ETag         string      `json:"etag,omitempty"`
+		LastModified string      `json:"lastModified,omitempty"`
+	}
+
+	// CacheStore stores cached responses. Implementations must be safe
+	// for concurrent use.
+	CacheStore interface {
+		Get(key string) (*CacheEntry, bool)
+		Set(key string, entry *CacheEntry)
+		Delete(key string)
+	}
+
+	// CacheOptions are options for CacheMiddleware.
+	CacheOptions struct {
+		// TTLs are TTLs of responses by URL path prefix without leading
+		// slash like "genres" or "tracks/". The longest matching prefix wins.
+		// DefaultCacheTTLs are used if nil.
+		TTLs map[string]time.Duration
+		// DefaultTTL is TTL of responses which don't match any of TTLs.
+		// Zero means that they are cached only if the server sends
+		// validators (ETag or Last-Modified) and revalidated on each request.
+		DefaultTTL time.Duration
+		// Now returns current time. Defaults to time.Now.
+		Now func() time.Time
+	}
+
+	cacheDoer struct {
+		next  Doer
+		store CacheStore
+		opts  CacheOptions
+	}
+)
+
+// CacheMiddleware returns middleware which caches successful GET responses
+// in the store. Responses are keyed by method, URL, user and language
+// (hash of Authorization and Accept-Language headers). Requests with other
+// methods and download info requests are never cached. When cached response
+// with validators expires it is revalidated with a conditional request.
+//
+// Requests which change data (like liking a track) don't invalidate cached
+// responses, so that they may be stale until their TTL expires. Use short
+// TTLs for endpoints whose data the client changes.
+func CacheMiddleware(store CacheStore, opts *CacheOptions) Middleware {
+	if opts == nil {
+		opts = &CacheOptions{}
+	}
+
+	o := *opts
+	if o.TTLs == nil {
+		o.TTLs = DefaultCacheTTLs
+	}
+	if o.Now == nil {
+		o.Now = time.Now
+	}
+
+	return func(next Doer) Doer {
+		return &cacheDoer{next: next, store: store, opts: o}
+	}
+}
+
+// CacheKey returns key of the request in the cache store.
+func CacheKey(req *http.Request) string {
+	hash := sha256.New()
+	for _, name := range cacheKeyHeaders {
+		hash.Write([]byte(req.Header.Get(name)))
+		hash.Write([]byte{0})
+	}
+	return req.Method + " " + req.URL.String() + " " + hex.EncodeToString(hash.Sum(nil)[:8])
+}
+
+// cacheable reports whether the request's response may be cached.
+func cacheable(req *http.Request) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+	for _, suffix := range uncachedPathSuffixes {
+		if strings.HasSuffix(req.URL.Path, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// ttl returns TTL for the request's URL.
+func (d *cacheDoer) ttl(req *http.Request) time.Duration {
+	path := strings.TrimPrefix(req.URL.Path, "/")
+
+	ttl := d.opts.DefaultTTL
+	longest := -1
+	for prefix, prefixTTL := range d.opts.TTLs {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			ttl = prefixTTL
+			longest = len(prefix)
+		}
+	}
+
+	return ttl
+}
+
+func (d *cacheDoer) Do(req *http.Request) (*http.Response, error) {
+	if !cacheable(req) {
+		return d.next.Do(req)
+	}
+
+	key := CacheKey(req)
+	now := d.opts.Now()
+
+	entry, ok := d.store.Get(key)
+	if ok && now.Before(entry.Expires) {
+		return entry.response(req, "HIT"), nil
+	}
+	if ok && entry.ETag == "" && entry.LastModified == "" {
+		// Expired entry can't be revalidated.
+		d.store.Delete(key)
+		ok = false
+	}
+
+	if ok && (entry.ETag != "" || entry.LastModified != "") {
+		req = req.Clone(req.Context())
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := d.next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := d.ttl(req)
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		entry.Expires = now.Add(ttl)
+		d.store.Set(key, entry)
+		return entry.response(req, "REVALIDATED"), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || noStore(resp.Header) {
+		return resp, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if ttl <= 0 && etag == "" && lastModified == "" {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	d.store.Set(key, &CacheEntry{
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+		Expires:      now.Add(ttl),
+		ETag:         etag,
+		LastModified: lastModified,
+	})
+
+	return resp, nil
+}
+
+// noStore reports whether Cache-Control forbids storing the response.
+func noStore(header http.Header) bool {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+			return true
+		}
+	}
+	return false
+}
+
+// response builds http response from the entry.
+func (e *CacheEntry) response(req *http.Request, status string) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(CacheStatusHeader, status)
+
+	return &http.Response{
+		Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// MemoryCacheStore is in-memory CacheStore which evicts least recently used
+// entries when it's full.
+type MemoryCacheStore struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCacheStore returns in-memory store which keeps at most maxEntries
+// entries. Zero maxEntries means no limit.
+func NewMemoryCacheStore(maxEntries int) *MemoryCacheStore {
+	return &MemoryCacheStore{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Get returns entry by key.
+func (s *MemoryCacheStore) Get(key string) (*CacheEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	s.lru.MoveToFront(element)
+	entry := *element.Value.(*memoryCacheItem).entry
+	return &entry, true
+}
+
+// Set stores entry by key.
+func (s *MemoryCacheStore) Set(key string, entry *CacheEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		element.Value.(*memoryCacheItem).entry = entry
+		s.lru.MoveToFront(element)
+		return
+	}
+
+	s.entries[key] = s.lru.PushFront(&memoryCacheItem{key: key, entry: entry})
+
+	for s.maxEntries > 0 && s.lru.Len() > s.maxEntries {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryCacheItem).key)
+	}
+}
+
+// Delete deletes entry by key.
+func (s *MemoryCacheStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		s.lru.Remove(element)
+		delete(s.entries, key)
+	}
+}
+
+// Len returns count of entries in the store.
+func (s *MemoryCacheStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lru.Len()
+}
+
+// DiskCacheStore is CacheStore which keeps each entry in a JSON file
+// in the directory. It evicts least recently used entries when total size
+// of the files exceeds the limit. Access time is stored as modification
+// time of the files, so that LRU order survives restarts.
+type DiskCacheStore struct {
+	dir     string
+	maxSize int64
+
+	mu      sync.Mutex
+	size    int64
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type diskCacheItem struct {
+	name string
+	size int64
+}
+
+// NewDiskCacheStore returns disk store which keeps entries in dir taking
+// at most maxSize bytes. Zero maxSize means no limit. The directory is
+// created if it doesn't exist and entries of the previous runs are loaded.
+func NewDiskCacheStore(dir string, maxSize int64) (*DiskCacheStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	s := &DiskCacheStore{
+		dir:     dir,
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type cachedFile struct {
+		item    diskCacheItem
+		modTime time.Time
+	}
+
+	var cached []cachedFile
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+
+		cached = append(cached, cachedFile{item: diskCacheItem{name: file.Name(), size: info.Size()}, modTime: info.ModTime()})
+	}
+
+	// The most recently used are in front.
+	sort.Slice(cached, func(i, j int) bool {
+		return cached[i].modTime.After(cached[j].modTime)
+	})
+
+	for _, file := range cached {
+		item := file.item
+		s.entries[item.name] = s.lru.PushBack(&item)
+		s.size += item.size
+	}
+	s.evictLocked()
+
+	return s, nil
+}
+
+// name returns name of the entry's file.
+func (s *DiskCacheStore) name(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:]) + ".json"
+}
+
+// Get returns entry by key and marks it recently used.
+func (s *DiskCacheStore) Get(key string) (*CacheEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := s.name(key)
+	element, ok := s.entries[name]
+	if !ok {
+		return nil, false
+	}
+
+	path := filepath.Join(s.dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		// File was removed behind our back.
+		s.removeLocked(element)
+		return nil, false
+	}
+
+	entry := new(CacheEntry)
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, false
+	}
+
+	s.lru.MoveToFront(element)
+	now := time.Now()
+	os.Chtimes(path, now, now)
+
+	return entry, true
+}
+
+// Set stores entry by key. Errors are ignored since cache is optional.
+func (s *DiskCacheStore) Set(key string, entry *CacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := s.name(key)
+	path := filepath.Join(s.dir, name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return
+	}
+
+	if element, ok := s.entries[name]; ok {
+		s.size -= element.Value.(*diskCacheItem).size
+		s.lru.Remove(element)
+	}
+
+	s.entries[name] = s.lru.PushFront(&diskCacheItem{name: name, size: int64(len(data))})
+	s.size += int64(len(data))
+	s.evictLocked()
+}
+
+// Delete deletes entry by key.
+func (s *DiskCacheStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[s.name(key)]; ok {
+		s.removeLocked(element)
+	}
+}
+
+// Size returns total size of the entries' files.
+func (s *DiskCacheStore) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.size
+}
+
+// evictLocked removes least recently used entries until size fits.
+func (s *DiskCacheStore) evictLocked() {
+	for s.maxSize > 0 && s.size > s.maxSize && s.lru.Len() > 0 {
+		s.removeLocked(s.lru.Back())
+	}
+}
+
+func (s *DiskCacheStore) removeLocked(element *list.Element) {
+	item := element.Value.(*diskCacheItem)
+	s.lru.Remove(element)
+	delete(s.entries, item.name)
+	s.size -= item.size
+	os.Remove(filepath.Join(s.dir, item.name))
+}
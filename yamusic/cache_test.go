@@ -0,0 +1,259 @@
+package yamusic
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheMiddleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryCacheStore(0)
+
+	baseURL, _ := url.Parse(server.URL + "/")
+	cachedClient := NewClient(
+		BaseURL(baseURL),
+		AccessToken(userID, accessToken),
+		Middlewares(CacheMiddleware(store, &CacheOptions{
+			TTLs: map[string]time.Duration{"genres": time.Minute},
+			Now:  func() time.Time { return now },
+		})),
+	)
+
+	calls := 0
+	mux.HandleFunc("/genres", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"invocationInfo": {"req-id": "Genres.List"}}`)
+	})
+
+	ctx := context.Background()
+
+	result, resp, err := cachedClient.Genres().List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "Genres.List", result.InvocationInfo.ReqID)
+	assert.Empty(t, resp.Header.Get(CacheStatusHeader))
+	assert.Equal(t, 1, calls)
+
+	result, resp, err = cachedClient.Genres().List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "Genres.List", result.InvocationInfo.ReqID)
+	assert.Equal(t, "HIT", resp.Header.Get(CacheStatusHeader))
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(2 * time.Minute)
+	result, resp, err = cachedClient.Genres().List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "Genres.List", result.InvocationInfo.ReqID)
+	assert.Equal(t, "REVALIDATED", resp.Header.Get(CacheStatusHeader))
+	assert.Equal(t, 2, calls)
+
+	// Other user must not get cached response of the first one.
+	otherClient := NewClient(
+		BaseURL(baseURL),
+		AccessToken(userID+1, "otherToken"),
+		Middlewares(CacheMiddleware(store, nil)),
+	)
+	_, resp, err = otherClient.Genres().List(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Header.Get(CacheStatusHeader))
+	assert.Equal(t, 3, calls)
+}
+
+func TestCacheMiddleware_NoWrites(t *testing.T) {
+	store := NewMemoryCacheStore(0)
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	doer := CacheMiddleware(store, &CacheOptions{DefaultTTL: time.Hour})(http.DefaultClient)
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/genres", nil)
+		resp, err := doer.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestMemoryCacheStore_LRU(t *testing.T) {
+	store := NewMemoryCacheStore(2)
+	store.Set("a", &CacheEntry{Body: []byte("a")})
+	store.Set("b", &CacheEntry{Body: []byte("b")})
+	_, ok := store.Get("a")
+	assert.True(t, ok)
+	store.Set("c", &CacheEntry{Body: []byte("c")})
+
+	_, ok = store.Get("b")
+	assert.False(t, ok)
+	_, ok = store.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, store.Len())
+
+	store.Delete("a")
+	_, ok = store.Get("a")
+	assert.False(t, ok)
+}
+
+func TestDiskCacheStore(t *testing.T) {
+	store, err := NewDiskCacheStore(t.TempDir(), 0)
+	assert.NoError(t, err)
+
+	entry := &CacheEntry{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Etag": {`"v1"`}},
+		Body:       []byte(`{"a":1}`),
+		Expires:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		ETag:       `"v1"`,
+	}
+	store.Set("key", entry)
+
+	got, ok := store.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, entry, got)
+
+	store.Delete("key")
+	_, ok = store.Get("key")
+	assert.False(t, ok)
+}
+
+func TestCacheMiddleware_DownloadInfo(t *testing.T) {
+	setup()
+	defer teardown()
+
+	baseURL, _ := url.Parse(server.URL + "/")
+	cachedClient := NewClient(
+		BaseURL(baseURL),
+		AccessToken(userID, accessToken),
+		Middlewares(CacheMiddleware(NewMemoryCacheStore(0), &CacheOptions{DefaultTTL: time.Hour})),
+	)
+
+	calls := 0
+	mux.HandleFunc("/tracks/1/download-info", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"result": []}`)
+	})
+
+	for i := 0; i < 2; i++ {
+		_, resp, err := cachedClient.Tracks().GetDownloadInfoResp(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Header.Get(CacheStatusHeader))
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestCacheMiddleware_Language(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("Accept-Language"))
+	}))
+	defer server.Close()
+
+	doer := CacheMiddleware(NewMemoryCacheStore(0), nil)(http.DefaultClient)
+	for _, lang := range []string{"en", "ru", "en"} {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/genres", nil)
+		req.Header.Set("Accept-Language", lang)
+		resp, err := doer.Do(req)
+		assert.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, lang, string(body))
+	}
+}
+
+func TestDiskCacheStore_Evict(t *testing.T) {
+	dir := t.TempDir()
+	entry := func(body string) *CacheEntry {
+		return &CacheEntry{StatusCode: http.StatusOK, Body: []byte(body)}
+	}
+
+	store, err := NewDiskCacheStore(dir, 0)
+	assert.NoError(t, err)
+	store.Set("a", entry("a"))
+	size := store.Size()
+
+	// Store fits two entries.
+	store, err = NewDiskCacheStore(dir, 2*size)
+	assert.NoError(t, err)
+	store.Set("b", entry("b"))
+	_, ok := store.Get("a")
+	assert.True(t, ok)
+	store.Set("c", entry("c"))
+
+	_, ok = store.Get("b")
+	assert.False(t, ok)
+	_, ok = store.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2*size, store.Size())
+
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	// LRU order survives restart: "a" was used after "c".
+	time.Sleep(10 * time.Millisecond)
+	_, ok = store.Get("a")
+	assert.True(t, ok)
+	store, err = NewDiskCacheStore(dir, size)
+	assert.NoError(t, err)
+	_, ok = store.Get("c")
+	assert.False(t, ok)
+	_, ok = store.Get("a")
+	assert.True(t, ok)
+}
+
+func TestCacheMiddleware_DeletesExpired(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryCacheStore(0)
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	doer := CacheMiddleware(store, &CacheOptions{
+		DefaultTTL: time.Minute,
+		Now:        func() time.Time { return now },
+	})(http.DefaultClient)
+	get := func() {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/search", nil)
+		resp, err := doer.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	get()
+	assert.Equal(t, 1, store.Len())
+
+	// Expired entry without validators is deleted even if the new
+	// response isn't cached.
+	now = now.Add(2 * time.Minute)
+	fail = true
+	get()
+	assert.Equal(t, 0, store.Len())
+}
@@ -2,12 +2,36 @@ package yamusic
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 )
 
+// Feed event types returned by the API in FeedEvent's `type` field.
+const (
+	FeedEventTracks                     = "tracks"
+	FeedEventMissedLikes                = "missed-likes"
+	FeedEventRecentTrack                = "recent-track"
+	FeedEventPromotion                  = "promotion"
+	FeedEventGenreTop                   = "genre-top"
+	FeedEventNewAlbumsOfFavoriteGenre   = "new-albums-of-favorite-genre"
+	FeedEventNewAlbumsOfFavoriteArtist  = "new-albums-of-favorite-artist"
+	FeedEventArtist                     = "artist"
+	FeedEventRecommendedSimilarArtists  = "recommended-similar-artists"
+	FeedEventRecommendedArtistsFromHist = "recommended-artists-with-artists-from-history"
+	FeedEventTracksByArtistFromHistory  = "recommended-tracks-by-artist-from-history"
+	FeedEventNotification               = "notification"
+)
+
+// ErrNoMoreFeedEvents is returned by FeedService.GetMore when since is
+// empty, e.g. when it's taken from FeedResp.NextSince of the last page.
+var ErrNoMoreFeedEvents = errors.New("yamusic: no more feed events")
+
 type (
-	// FeedService is a service to deal with accounts.
+	// FeedService is a service to deal with feed.
 	FeedService struct {
 		client *Client
 	}
@@ -17,357 +41,292 @@ type (
 		InvocationInfo InvocationInfo `json:"invocationInfo"`
 		Error          Error          `json:"error"`
 		Result         struct {
-			CanGetMoreEvents   bool          `json:"canGetMoreEvents"`
-			Pumpkin            bool          `json:"pumpkin"`
-			Today              string        `json:"today"`
-			GeneratedPlaylists []interface{} `json:"generatedPlaylists"`
-			Headlines          []struct {
-				Type    string `json:"type"`
-				ID      string `json:"id"`
-				Message string `json:"message"`
-			} `json:"headlines"`
-			Days []struct {
-				Day    string `json:"day"`
-				Events []struct {
-					ID          string `json:"id"`
-					Type        string `json:"type"`
-					TypeForFrom string `json:"typeForFrom,omitempty"`
-					Title       []struct {
-						Type string `json:"type"`
-						Text string `json:"text"`
-					} `json:"title,omitempty"`
-					Promo struct {
-						PromoID       string        `json:"promoId"`
-						Category      string        `json:"category"`
-						TitleURL      string        `json:"titleUrl"`
-						SubtitleURL   string        `json:"subtitleUrl"`
-						Title         string        `json:"title"`
-						Subtitle      string        `json:"subtitle"`
-						Heading       string        `json:"heading"`
-						Description   string        `json:"description"`
-						Background    string        `json:"background"`
-						ImagePosition string        `json:"imagePosition"`
-						PromotionType string        `json:"promotionType"`
-						Tags          []interface{} `json:"tags"`
-						StartDate     time.Time     `json:"startDate"`
-						Pager         struct {
-							Total   int `json:"total"`
-							Page    int `json:"page"`
-							PerPage int `json:"perPage"`
-						} `json:"pager"`
-						Playlists []struct {
-							Playlist struct {
-								UID                  int       `json:"uid"`
-								Kind                 int       `json:"kind"`
-								Revision             int       `json:"revision"`
-								TrackCount           int       `json:"trackCount"`
-								DurationMs           int       `json:"durationMs"`
-								Collective           bool      `json:"collective"`
-								IsBanner             bool      `json:"isBanner"`
-								IsPremiere           bool      `json:"isPremiere"`
-								Available            bool      `json:"available"`
-								Title                string    `json:"title"`
-								Description          string    `json:"description"`
-								DescriptionFormatted string    `json:"descriptionFormatted"`
-								Visibility           string    `json:"visibility"`
-								BackgroundColor      string    `json:"backgroundColor"`
-								TextColor            string    `json:"textColor"`
-								Image                string    `json:"image"`
-								OgImage              string    `json:"ogImage"`
-								Created              time.Time `json:"created"`
-								Modified             time.Time `json:"modified"`
-								Owner                struct {
-									UID      int    `json:"uid"`
-									Login    string `json:"login"`
-									Name     string `json:"name"`
-									Verified bool   `json:"verified"`
-								} `json:"owner"`
-								Cover struct {
-									Type    string `json:"type"`
-									Dir     string `json:"dir"`
-									Version string `json:"version"`
-									URI     string `json:"uri"`
-									Custom  bool   `json:"custom"`
-								} `json:"cover"`
-								Tags []struct {
-									ID    string `json:"id"`
-									Value string `json:"value"`
-								} `json:"tags"`
-							} `json:"playlist"`
-							SomeArtists []struct {
-								Various          bool     `json:"various"`
-								Composer         bool     `json:"composer"`
-								Available        bool     `json:"available"`
-								TicketsAvailable bool     `json:"ticketsAvailable"`
-								ID               string   `json:"id"`
-								Name             string   `json:"name"`
-								OgImage          string   `json:"ogImage"`
-								Genres           []string `json:"genres"`
-								Cover            struct {
-									Type   string `json:"type"`
-									Prefix string `json:"prefix"`
-									URI    string `json:"uri"`
-								} `json:"cover,omitempty"`
-								Counts struct {
-									Tracks       int `json:"tracks"`
-									DirectAlbums int `json:"directAlbums"`
-									AlsoAlbums   int `json:"alsoAlbums"`
-									AlsoTracks   int `json:"alsoTracks"`
-								} `json:"counts"`
-								Ratings struct {
-									Day   int `json:"day"`
-									Week  int `json:"week"`
-									Month int `json:"month"`
-								} `json:"ratings,omitempty"`
-								Links []struct {
-									Title         string `json:"title"`
-									Href          string `json:"href"`
-									Type          string `json:"type"`
-									SocialNetwork string `json:"socialNetwork,omitempty"`
-								} `json:"links"`
-							} `json:"someArtists"`
-							ArtistsCount int `json:"artistsCount"`
-						} `json:"playlists"`
-					} `json:"promo,omitempty"`
-					Message          string `json:"message,omitempty"`
-					Genre            string `json:"genre,omitempty"`
-					RadioIsAvailable bool   `json:"radioIsAvailable,omitempty"`
-					Tracks           []struct {
-						DurationMs               int    `json:"durationMs"`
-						FileSize                 int    `json:"fileSize"`
-						Available                bool   `json:"available"`
-						AvailableForPremiumUsers bool   `json:"availableForPremiumUsers"`
-						LyricsAvailable          bool   `json:"lyricsAvailable"`
-						ID                       string `json:"id"`
-						RealID                   string `json:"realId"`
-						Title                    string `json:"title"`
-						StorageDir               string `json:"storageDir"`
-						CoverURI                 string `json:"coverUri"`
-						OgImage                  string `json:"ogImage"`
-						Major                    struct {
-							ID   int    `json:"id"`
-							Name string `json:"name"`
-						} `json:"major"`
-						Normalization struct {
-							Gain float64 `json:"gain"`
-							Peak int     `json:"peak"`
-						} `json:"normalization"`
-						Artists []struct {
-							ID       int           `json:"id"`
-							Name     string        `json:"name"`
-							Various  bool          `json:"various"`
-							Composer bool          `json:"composer"`
-							Genres   []interface{} `json:"genres"`
-							Cover    struct {
-								Type   string `json:"type"`
-								Prefix string `json:"prefix"`
-								URI    string `json:"uri"`
-							} `json:"cover"`
-						} `json:"artists"`
-						Albums []struct {
-							ID                       int           `json:"id"`
-							Year                     int           `json:"year"`
-							TrackCount               int           `json:"trackCount"`
-							Recent                   bool          `json:"recent"`
-							VeryImportant            bool          `json:"veryImportant"`
-							Available                bool          `json:"available"`
-							AvailableForPremiumUsers bool          `json:"availableForPremiumUsers"`
-							AvailableForMobile       bool          `json:"availableForMobile"`
-							AvailablePartially       bool          `json:"availablePartially"`
-							Title                    string        `json:"title"`
-							CoverURI                 string        `json:"coverUri"`
-							OgImage                  string        `json:"ogImage"`
-							Genre                    string        `json:"genre"`
-							Buy                      []interface{} `json:"buy"`
-							Bests                    []interface{} `json:"bests"`
-							Artists                  []struct {
-								ID       int    `json:"id"`
-								Name     string `json:"name"`
-								Various  bool   `json:"various"`
-								Composer bool   `json:"composer"`
-								Cover    struct {
-									Type   string `json:"type"`
-									Prefix string `json:"prefix"`
-									URI    string `json:"uri"`
-								} `json:"cover"`
-								Genres []interface{} `json:"genres"`
-							} `json:"artists"`
-							Labels []struct {
-								ID          int    `json:"id"`
-								Name        string `json:"name"`
-								Description string `json:"description"`
-								Image       string `json:"image"`
-							} `json:"labels"`
-							TrackPosition struct {
-								Volume int `json:"volume"`
-								Index  int `json:"index"`
-							} `json:"trackPosition"`
-						} `json:"albums"`
-					} `json:"tracks,omitempty"`
-				} `json:"events"`
-				TracksToPlay []struct {
-					DurationMs               int    `json:"durationMs"`
-					FileSize                 int    `json:"fileSize"`
-					Available                bool   `json:"available"`
-					AvailableForPremiumUsers bool   `json:"availableForPremiumUsers"`
-					Best                     bool   `json:"best,omitempty"`
-					LyricsAvailable          bool   `json:"lyricsAvailable"`
-					ID                       string `json:"id"`
-					RealID                   string `json:"realId"`
-					Title                    string `json:"title"`
-					StorageDir               string `json:"storageDir"`
-					CoverURI                 string `json:"coverUri"`
-					OgImage                  string `json:"ogImage"`
-					Version                  string `json:"version,omitempty"`
-					ContentWarning           string `json:"contentWarning,omitempty"`
-					Major                    struct {
-						ID   int    `json:"id"`
-						Name string `json:"name"`
-					} `json:"major"`
-					Normalization struct {
-						Gain float64 `json:"gain"`
-						Peak int     `json:"peak"`
-					} `json:"normalization"`
-					Artists []struct {
-						ID       int    `json:"id"`
-						Name     string `json:"name"`
-						Various  bool   `json:"various"`
-						Composer bool   `json:"composer"`
-						Cover    struct {
-							Type   string `json:"type"`
-							Prefix string `json:"prefix"`
-							URI    string `json:"uri"`
-						} `json:"cover"`
-						Genres []interface{} `json:"genres"`
-					} `json:"artists"`
-					Albums []struct {
-						ID                       int           `json:"id"`
-						Year                     int           `json:"year"`
-						TrackCount               int           `json:"trackCount"`
-						Recent                   bool          `json:"recent"`
-						VeryImportant            bool          `json:"veryImportant"`
-						Available                bool          `json:"available"`
-						AvailableForPremiumUsers bool          `json:"availableForPremiumUsers"`
-						AvailableForMobile       bool          `json:"availableForMobile"`
-						AvailablePartially       bool          `json:"availablePartially"`
-						Title                    string        `json:"title"`
-						Type                     string        `json:"type"`
-						CoverURI                 string        `json:"coverUri"`
-						OgImage                  string        `json:"ogImage"`
-						Genre                    string        `json:"genre"`
-						Bests                    []interface{} `json:"bests"`
-						Buy                      []interface{} `json:"buy"`
-						ReleaseDate              time.Time     `json:"releaseDate"`
-						Artists                  []struct {
-							ID       int    `json:"id"`
-							Name     string `json:"name"`
-							Various  bool   `json:"various"`
-							Composer bool   `json:"composer"`
-							Cover    struct {
-								Type   string `json:"type"`
-								Prefix string `json:"prefix"`
-								URI    string `json:"uri"`
-							} `json:"cover"`
-							Genres []interface{} `json:"genres"`
-						} `json:"artists"`
-						Labels []struct {
-							ID          int    `json:"id"`
-							Name        string `json:"name"`
-							Description string `json:"description"`
-							Image       string `json:"image"`
-						} `json:"labels"`
-						TrackPosition struct {
-							Volume int `json:"volume"`
-							Index  int `json:"index"`
-						} `json:"trackPosition"`
-					} `json:"albums"`
-				} `json:"tracksToPlay"`
-				TracksToPlayWithAds []struct {
-					Type  string `json:"type"`
-					Track struct {
-						ID                       string `json:"id"`
-						RealID                   string `json:"realId"`
-						Title                    string `json:"title"`
-						DurationMs               int    `json:"durationMs"`
-						FileSize                 int    `json:"fileSize"`
-						Available                bool   `json:"available"`
-						AvailableForPremiumUsers bool   `json:"availableForPremiumUsers"`
-						LyricsAvailable          bool   `json:"lyricsAvailable"`
-						StorageDir               string `json:"storageDir"`
-						CoverURI                 string `json:"coverUri"`
-						OgImage                  string `json:"ogImage"`
-						Major                    struct {
-							ID   int    `json:"id"`
-							Name string `json:"name"`
-						} `json:"major"`
-						Normalization struct {
-							Gain float64 `json:"gain"`
-							Peak int     `json:"peak"`
-						} `json:"normalization"`
-						Artists []struct {
-							ID       int    `json:"id"`
-							Name     string `json:"name"`
-							Various  bool   `json:"various"`
-							Composer bool   `json:"composer"`
-							Cover    struct {
-								Type   string `json:"type"`
-								Prefix string `json:"prefix"`
-								URI    string `json:"uri"`
-							} `json:"cover"`
-							Genres []interface{} `json:"genres"`
-						} `json:"artists"`
-						Albums []struct {
-							ID                       int           `json:"id"`
-							Year                     int           `json:"year"`
-							TrackCount               int           `json:"trackCount"`
-							Recent                   bool          `json:"recent"`
-							VeryImportant            bool          `json:"veryImportant"`
-							Available                bool          `json:"available"`
-							AvailableForPremiumUsers bool          `json:"availableForPremiumUsers"`
-							AvailableForMobile       bool          `json:"availableForMobile"`
-							AvailablePartially       bool          `json:"availablePartially"`
-							Title                    string        `json:"title"`
-							Type                     string        `json:"type"`
-							CoverURI                 string        `json:"coverUri"`
-							OgImage                  string        `json:"ogImage"`
-							Genre                    string        `json:"genre"`
-							Bests                    []interface{} `json:"bests"`
-							Buy                      []interface{} `json:"buy"`
-							ReleaseDate              time.Time     `json:"releaseDate"`
-							Artists                  []struct {
-								ID       int    `json:"id"`
-								Name     string `json:"name"`
-								Various  bool   `json:"various"`
-								Composer bool   `json:"composer"`
-								Cover    struct {
-									Type   string `json:"type"`
-									Prefix string `json:"prefix"`
-									URI    string `json:"uri"`
-								} `json:"cover"`
-								Genres []interface{} `json:"genres"`
-							} `json:"artists"`
-							Labels []struct {
-								ID          int    `json:"id"`
-								Name        string `json:"name"`
-								Description string `json:"description"`
-								Image       string `json:"image"`
-							} `json:"labels"`
-							TrackPosition struct {
-								Volume int `json:"volume"`
-								Index  int `json:"index"`
-							} `json:"trackPosition"`
-						} `json:"albums"`
-					} `json:"track"`
-				} `json:"tracksToPlayWithAds"`
-			} `json:"days"`
+			CanGetMoreEvents   bool                    `json:"canGetMoreEvents"`
+			Pumpkin            bool                    `json:"pumpkin"`
+			Today              string                  `json:"today"`
+			NextRevision       string                  `json:"nextRevision,omitempty"`
+			GeneratedPlaylists []FeedGeneratedPlaylist `json:"generatedPlaylists"`
+			Headlines          []FeedHeadline          `json:"headlines"`
+			Days               []FeedDay               `json:"days"`
 		} `json:"result"`
 	}
+
+	// FeedGeneratedPlaylist is an automatically generated playlist
+	// like "Playlist of the day" shown on top of the feed.
+	FeedGeneratedPlaylist struct {
+		Type      string          `json:"type"`
+		Ready     bool            `json:"ready"`
+		Notify    bool            `json:"notify"`
+		Data      PlaylistsResult `json:"data"`
+		HasTracks bool            `json:"hasTracks,omitempty"`
+	}
+
+	// FeedHeadline is a short message on top of the feed.
+	FeedHeadline struct {
+		Type    string `json:"type"`
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	}
+
+	// FeedDay is a group of feed events of one day.
+	FeedDay struct {
+		Day                 string        `json:"day"`
+		Events              FeedEvents    `json:"events"`
+		TracksToPlay        []Track       `json:"tracksToPlay"`
+		TracksToPlayWithAds []FeedAdTrack `json:"tracksToPlayWithAds"`
+	}
+
+	// FeedAdTrack is an item of FeedDay's TracksToPlayWithAds.
+	FeedAdTrack struct {
+		Type  string `json:"type"`
+		Track Track  `json:"track"`
+	}
+
+	// FeedEvent is a single event of the feed. Concrete type depends on
+	// the event's `type` field, see TracksEvent, PromoEvent, GenreTopEvent,
+	// NewAlbumsEvent, ArtistEvent, NotificationEvent and UnknownEvent.
+	FeedEvent interface {
+		// EventID returns id of the event.
+		EventID() string
+		// EventType returns raw type of the event.
+		EventType() string
+	}
+
+	// FeedEvents is a list of feed events decoded by their type.
+	FeedEvents []FeedEvent
+
+	// FeedEventTitle is a part of feed event's title.
+	FeedEventTitle struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+
+	// FeedEventBase contains fields common for all feed events.
+	FeedEventBase struct {
+		ID          string           `json:"id"`
+		Type        string           `json:"type"`
+		TypeForFrom string           `json:"typeForFrom,omitempty"`
+		Title       []FeedEventTitle `json:"title,omitempty"`
+	}
+
+	// TracksEvent is an event with a list of tracks
+	// (missed likes, recent tracks and so on).
+	TracksEvent struct {
+		FeedEventBase
+		Tracks []Track `json:"tracks"`
+	}
+
+	// PromoEvent is an editorial promotion with playlists.
+	PromoEvent struct {
+		FeedEventBase
+		Promo FeedPromo `json:"promo"`
+	}
+
+	// GenreTopEvent is an event with top tracks of a genre.
+	GenreTopEvent struct {
+		FeedEventBase
+		Genre            string  `json:"genre"`
+		RadioIsAvailable bool    `json:"radioIsAvailable"`
+		Tracks           []Track `json:"tracks"`
+	}
+
+	// NewAlbumsEvent is an event with new albums
+	// of favorite artists or genres.
+	NewAlbumsEvent struct {
+		FeedEventBase
+		Genre  string  `json:"genre,omitempty"`
+		Albums []Album `json:"albums"`
+	}
+
+	// ArtistEvent is an event about an artist with its tracks
+	// or with similar artists.
+	ArtistEvent struct {
+		FeedEventBase
+		Artist                      Artist   `json:"artist"`
+		Tracks                      []Track  `json:"tracks,omitempty"`
+		Artists                     []Artist `json:"artists,omitempty"`
+		SimilarToArtistsFromHistory []Artist `json:"similarToArtistsFromHistory,omitempty"`
+	}
+
+	// NotificationEvent is a text notification.
+	NotificationEvent struct {
+		FeedEventBase
+		Message string `json:"message"`
+	}
+
+	// UnknownEvent is an event of a type which is not known by the library.
+	// Raw contains the whole event JSON.
+	UnknownEvent struct {
+		FeedEventBase
+		Raw json.RawMessage `json:"-"`
+	}
+
+	// FeedPromo is promotion of PromoEvent.
+	FeedPromo struct {
+		PromoID       string              `json:"promoId"`
+		Category      string              `json:"category"`
+		TitleURL      string              `json:"titleUrl"`
+		SubtitleURL   string              `json:"subtitleUrl"`
+		Title         string              `json:"title"`
+		Subtitle      string              `json:"subtitle"`
+		Heading       string              `json:"heading"`
+		Description   string              `json:"description"`
+		Background    string              `json:"background"`
+		ImagePosition string              `json:"imagePosition"`
+		PromotionType string              `json:"promotionType"`
+		Tags          []interface{}       `json:"tags"`
+		StartDate     time.Time           `json:"startDate"`
+		Pager         FeedPager           `json:"pager"`
+		Playlists     []FeedPromoPlaylist `json:"playlists"`
+	}
+
+	// FeedPager describes pagination of promo's playlists and genre albums.
+	FeedPager struct {
+		Total   int `json:"total"`
+		Page    int `json:"page"`
+		PerPage int `json:"perPage"`
+	}
+
+	// FeedPromoPlaylist is a playlist of the promotion with some of its artists.
+	FeedPromoPlaylist struct {
+		Playlist     PlaylistsResult   `json:"playlist"`
+		SomeArtists  []FeedPromoArtist `json:"someArtists"`
+		ArtistsCount int               `json:"artistsCount"`
+	}
+
+	// FeedPromoArtist is an artist of FeedPromoPlaylist.
+	FeedPromoArtist struct {
+		Various          bool     `json:"various"`
+		Composer         bool     `json:"composer"`
+		Available        bool     `json:"available"`
+		TicketsAvailable bool     `json:"ticketsAvailable"`
+		ID               string   `json:"id"`
+		Name             string   `json:"name"`
+		OgImage          string   `json:"ogImage"`
+		Genres           []string `json:"genres"`
+		Cover            struct {
+			Type   string `json:"type"`
+			Prefix string `json:"prefix"`
+			URI    string `json:"uri"`
+		} `json:"cover,omitempty"`
+		Counts struct {
+			Tracks       int `json:"tracks"`
+			DirectAlbums int `json:"directAlbums"`
+			AlsoAlbums   int `json:"alsoAlbums"`
+			AlsoTracks   int `json:"alsoTracks"`
+		} `json:"counts"`
+		Ratings struct {
+			Day   int `json:"day"`
+			Week  int `json:"week"`
+			Month int `json:"month"`
+		} `json:"ratings,omitempty"`
+		Links []struct {
+			Title         string `json:"title"`
+			Href          string `json:"href"`
+			Type          string `json:"type"`
+			SocialNetwork string `json:"socialNetwork,omitempty"`
+		} `json:"links"`
+	}
 )
 
+// EventID returns id of the event.
+func (e FeedEventBase) EventID() string { return e.ID }
+
+// EventType returns raw type of the event.
+func (e FeedEventBase) EventType() string { return e.Type }
+
+// newFeedEvent returns pointer to an empty event of a concrete type
+// suitable for the raw event type.
+func newFeedEvent(typ string) FeedEvent {
+	switch typ {
+	case FeedEventTracks,
+		FeedEventMissedLikes,
+		FeedEventRecentTrack:
+		return &TracksEvent{}
+	case FeedEventPromotion:
+		return &PromoEvent{}
+	case FeedEventGenreTop:
+		return &GenreTopEvent{}
+	case FeedEventNewAlbumsOfFavoriteGenre,
+		FeedEventNewAlbumsOfFavoriteArtist:
+		return &NewAlbumsEvent{}
+	case FeedEventArtist,
+		FeedEventRecommendedSimilarArtists,
+		FeedEventRecommendedArtistsFromHist,
+		FeedEventTracksByArtistFromHistory:
+		return &ArtistEvent{}
+	case FeedEventNotification:
+		return &NotificationEvent{}
+	default:
+		return &UnknownEvent{}
+	}
+}
+
+// UnmarshalJSON decodes each event into a concrete type chosen by its
+// `type` field. Events of unknown types are decoded into UnknownEvent.
+func (events *FeedEvents) UnmarshalJSON(data []byte) error {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return err
+	}
+
+	if raws == nil {
+		*events = nil
+		return nil
+	}
+
+	result := make(FeedEvents, 0, len(raws))
+	for _, raw := range raws {
+		var base FeedEventBase
+		if err := json.Unmarshal(raw, &base); err != nil {
+			return err
+		}
+
+		event := newFeedEvent(base.Type)
+		if err := json.Unmarshal(raw, event); err != nil {
+			return fmt.Errorf("decode feed event %q of type %q: %w", base.ID, base.Type, err)
+		}
+
+		if unknown, ok := event.(*UnknownEvent); ok {
+			unknown.Raw = append(json.RawMessage(nil), raw...)
+		}
+
+		result = append(result, event)
+	}
+
+	*events = result
+	return nil
+}
+
+// MarshalJSON encodes unknown events with their raw JSON and all other
+// events as is.
+func (e UnknownEvent) MarshalJSON() ([]byte, error) {
+	if len(e.Raw) > 0 {
+		return e.Raw, nil
+	}
+
+	return json.Marshal(e.FeedEventBase)
+}
+
+// NextSince returns the day which should be passed to FeedService.GetMore
+// to load older events. The second value is false if there are no more
+// events to load.
+func (r *FeedResp) NextSince() (string, bool) {
+	if r == nil || !r.Result.CanGetMoreEvents || len(r.Result.Days) == 0 {
+		return "", false
+	}
+
+	return r.Result.Days[len(r.Result.Days)-1].Day, true
+}
+
 // Get returns feed of current user or base feed if there is no access token
 func (s *FeedService) Get(
 	ctx context.Context,
+	reqOpts ...RequestOption,
 ) (*FeedResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "FeedService.Get", reqOpts)
+	defer call.end()
+
 	req, err := s.client.NewRequest(http.MethodGet, "feed", nil)
 	if err != nil {
 		return nil, nil, err
@@ -377,3 +336,32 @@ func (s *FeedService) Get(
 	resp, err := s.client.Do(ctx, req, feed)
 	return feed, resp, err
 }
+
+// GetMore returns older feed days which come before the since day.
+// Use FeedResp.NextSince to get since from the previous response.
+// Empty since returns ErrNoMoreFeedEvents without a request.
+func (s *FeedService) GetMore(
+	ctx context.Context,
+	since string,
+	reqOpts ...RequestOption,
+) (*FeedResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "FeedService.GetMore", reqOpts)
+	defer call.end()
+
+	if since == "" {
+		return nil, nil, ErrNoMoreFeedEvents
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("since", since)
+
+	uri := fmt.Sprintf("feed?%v", queryParams.Encode())
+	req, err := s.client.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	feed := new(FeedResp)
+	resp, err := s.client.Do(ctx, req, feed)
+	return feed, resp, err
+}
@@ -30,3 +30,79 @@ func TestFeedService_Get(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
 }
+
+func TestFeedService_GetMore(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &FeedResp{}
+	want.InvocationInfo.ReqID = "Feed.GetMore"
+
+	mux.HandleFunc("/feed", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "2019-01-02", r.URL.Query().Get("since"))
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	result, _, err := client.Feed().GetMore(context.Background(), "2019-01-02")
+
+	assert.NoError(t, err)
+	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
+
+	_, _, err = client.Feed().GetMore(context.Background(), "")
+	assert.Equal(t, ErrNoMoreFeedEvents, err)
+}
+
+func TestFeedEvents_UnmarshalJSON(t *testing.T) {
+	data := `{
+		"result": {
+			"canGetMoreEvents": true,
+			"days": [
+				{"day": "2019-01-03", "events": []},
+				{"day": "2019-01-02", "events": [
+					{"id": "1", "type": "tracks", "tracks": [{"id": "10"}]},
+					{"id": "2", "type": "promotion", "promo": {"promoId": "p"}},
+					{"id": "3", "type": "genre-top", "genre": "rock", "radioIsAvailable": true},
+					{"id": "4", "type": "new-albums-of-favorite-artist", "albums": [{"id": 20}]},
+					{"id": "5", "type": "artist", "artist": {"id": 30}},
+					{"id": "6", "type": "notification", "message": "hello"},
+					{"id": "7", "type": "brand-new", "something": 1}
+				]}
+			]
+		}
+	}`
+
+	var feed FeedResp
+	assert.NoError(t, json.Unmarshal([]byte(data), &feed))
+
+	events := feed.Result.Days[1].Events
+	if !assert.Len(t, events, 7) {
+		return
+	}
+
+	assert.Equal(t, "10", events[0].(*TracksEvent).Tracks[0].ID)
+	assert.Equal(t, "p", events[1].(*PromoEvent).Promo.PromoID)
+	assert.Equal(t, "rock", events[2].(*GenreTopEvent).Genre)
+	assert.Equal(t, 20, events[3].(*NewAlbumsEvent).Albums[0].ID)
+	assert.Equal(t, 30, events[4].(*ArtistEvent).Artist.ID)
+	assert.Equal(t, "hello", events[5].(*NotificationEvent).Message)
+
+	unknown := events[6].(*UnknownEvent)
+	assert.Equal(t, "7", unknown.EventID())
+	assert.Equal(t, "brand-new", unknown.EventType())
+	assert.JSONEq(t, `{"id": "7", "type": "brand-new", "something": 1}`, string(unknown.Raw))
+
+	b, err := json.Marshal(events)
+	assert.NoError(t, err)
+	var decoded FeedEvents
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	b2, err := json.Marshal(decoded)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(b), string(b2))
+
+	since, ok := feed.NextSince()
+	assert.True(t, ok)
+	assert.Equal(t, "2019-01-02", since)
+}
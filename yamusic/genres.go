@@ -2,9 +2,28 @@ package yamusic
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
+// Sort orders of GenresService.Albums.
+const (
+	GenreAlbumsSortRating = "rating"
+	GenreAlbumsSortYear   = "year"
+)
+
+// DefaultGenreAlbumsPageSize is page size of GenresService.Albums if
+// GenreAlbumsOptions.PageSize is zero.
+const DefaultGenreAlbumsPageSize = 25
+
+// SkipSubGenres is returned by WalkFunc to skip sub-genres of the genre.
+var SkipSubGenres = errors.New("yamusic: skip sub-genres")
+
 type (
 	// GenresService is a service to deal with genres.
 	GenresService struct {
@@ -88,12 +107,106 @@ type (
 			} `json:"subGenres,omitempty"`
 		} `json:"result"`
 	}
+
+	// GenresTreeResp describes genres method response as a tree.
+	GenresTreeResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         GenreTree      `json:"result"`
+	}
+
+	// Genre is a genre with localized titles and sub-genres.
+	Genre struct {
+		ID string `json:"id"`
+		// DefaultTitle is title in the default language of the API
+		DefaultTitle string `json:"title"`
+		// DefaultFullTitle is full title in the default language
+		DefaultFullTitle string `json:"fullTitle,omitempty"`
+		// Titles are localized titles by language like "en" or "ru"
+		Titles      map[string]GenreTitle `json:"titles,omitempty"`
+		Weight      int                   `json:"weight"`
+		TracksCount int                   `json:"tracksCount"`
+		ComposerTop bool                  `json:"composerTop"`
+		ShowInMenu  bool                  `json:"showInMenu"`
+		URLPart     string                `json:"urlPart,omitempty"`
+		Color       string                `json:"color,omitempty"`
+		// Images are URLs of images by size like "300x300"
+		Images    map[string]string `json:"images,omitempty"`
+		RadioIcon GenreRadioIcon    `json:"radioIcon"`
+		SubGenres []Genre           `json:"subGenres,omitempty"`
+	}
+
+	// GenreTitle is a localized title of a genre.
+	GenreTitle struct {
+		Title     string `json:"title"`
+		FullTitle string `json:"fullTitle,omitempty"`
+	}
+
+	// GenreRadioIcon is an icon of the genre's radio station.
+	GenreRadioIcon struct {
+		BackgroundColor string `json:"backgroundColor,omitempty"`
+		// ImageURL is URI like "avatars.yandex.net/.../%%" where "%%"
+		// is a placeholder of size
+		ImageURL string `json:"imageUrl,omitempty"`
+	}
+
+	// GenreTree is a tree of genres with lookup by id.
+	GenreTree struct {
+		// Genres are top-level genres
+		Genres []Genre
+		byID   map[string]*Genre
+		parent map[string]*Genre
+	}
+
+	// GenreOverviewResp describes genre overview method response.
+	GenreOverviewResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         GenreOverview  `json:"result"`
+	}
+
+	// GenreOverview is top tracks, albums, artists and playlists of a genre.
+	GenreOverview struct {
+		ID        string            `json:"id"`
+		Title     GenreTitle        `json:"title"`
+		Tracks    []Track           `json:"tracks"`
+		Albums    []Album           `json:"albums"`
+		Artists   []Artist          `json:"artists"`
+		Playlists []PlaylistsResult `json:"playlists"`
+	}
+
+	// GenreAlbumsOptions are options for GenresService.Albums.
+	GenreAlbumsOptions struct {
+		// Page is zero-based page number
+		Page int
+		// PageSize defaults to DefaultGenreAlbumsPageSize
+		PageSize int
+	}
+
+	// GenreAlbumsResp describes genre albums method response.
+	GenreAlbumsResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         struct {
+			Albums []Album   `json:"albums"`
+			Pager  FeedPager `json:"pager"`
+		} `json:"result"`
+	}
+
+	// WalkFunc is called by GenreTree.Walk for each genre. Depth of
+	// top-level genres is 0. Returning SkipSubGenres skips sub-genres
+	// of the genre, other errors stop the walk.
+	WalkFunc func(genre *Genre, depth int) error
 )
 
 // List returns list of existed genres.
 func (s *GenresService) List(
 	ctx context.Context,
+	reqOpts ...RequestOption,
 ) (*GenresListResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "GenresService.List", reqOpts)
+	defer call.end()
+
 	req, err := s.client.NewRequest(http.MethodGet, "genres", nil)
 	if err != nil {
 		return nil, nil, err
@@ -103,3 +216,223 @@ func (s *GenresService) List(
 	resp, err := s.client.Do(ctx, req, genres)
 	return genres, resp, err
 }
+
+// Tree returns genres as a tree.
+func (s *GenresService) Tree(
+	ctx context.Context,
+	reqOpts ...RequestOption,
+) (*GenresTreeResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "GenresService.Tree", reqOpts)
+	defer call.end()
+
+	req, err := s.client.NewRequest(http.MethodGet, "genres", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tree := new(GenresTreeResp)
+	resp, err := s.client.Do(ctx, req, tree)
+	return tree, resp, err
+}
+
+// Overview returns top tracks, albums, artists and playlists of the genre
+// like "rock".
+func (s *GenresService) Overview(
+	ctx context.Context,
+	genreID string,
+	reqOpts ...RequestOption,
+) (*GenreOverviewResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "GenresService.Overview", reqOpts)
+	defer call.end()
+
+	uri := fmt.Sprintf("metatags/%v", url.PathEscape(genreID))
+	req, err := s.client.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	overview := new(GenreOverviewResp)
+	resp, err := s.client.Do(ctx, req, overview)
+	return overview, resp, err
+}
+
+// Albums returns page of albums of the genre sorted by sort order like
+// GenreAlbumsSortRating. Empty sort means server's default.
+func (s *GenresService) Albums(
+	ctx context.Context,
+	genreID string,
+	sort string,
+	opts *GenreAlbumsOptions,
+	reqOpts ...RequestOption,
+) (*GenreAlbumsResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "GenresService.Albums", reqOpts)
+	defer call.end()
+
+	if opts == nil {
+		opts = &GenreAlbumsOptions{}
+	}
+
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultGenreAlbumsPageSize
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("offset", strconv.Itoa(opts.Page*pageSize))
+	queryParams.Set("limit", strconv.Itoa(pageSize))
+	if sort != "" {
+		queryParams.Set("sortBy", sort)
+	}
+
+	uri := fmt.Sprintf("metatags/%v/albums?%v", url.PathEscape(genreID), queryParams.Encode())
+	req, err := s.client.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	albums := new(GenreAlbumsResp)
+	resp, err := s.client.Do(ctx, req, albums)
+	return albums, resp, err
+}
+
+// NewGenreTree returns tree of top-level genres.
+func NewGenreTree(genres []Genre) *GenreTree {
+	t := &GenreTree{Genres: genres}
+	t.index()
+	return t
+}
+
+// UnmarshalJSON decodes tree from array of top-level genres.
+func (t *GenreTree) UnmarshalJSON(data []byte) error {
+	var genres []Genre
+	if err := json.Unmarshal(data, &genres); err != nil {
+		return err
+	}
+
+	*t = GenreTree{Genres: genres}
+	t.index()
+	return nil
+}
+
+// MarshalJSON encodes tree as array of top-level genres.
+func (t GenreTree) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Genres)
+}
+
+// index builds lookup maps of the tree.
+func (t *GenreTree) index() {
+	t.byID = make(map[string]*Genre)
+	t.parent = make(map[string]*Genre)
+
+	var add func(genres []Genre, parent *Genre)
+	add = func(genres []Genre, parent *Genre) {
+		for i := range genres {
+			genre := &genres[i]
+			t.byID[genre.ID] = genre
+			if parent != nil {
+				t.parent[genre.ID] = parent
+			}
+			add(genre.SubGenres, genre)
+		}
+	}
+	add(t.Genres, nil)
+}
+
+// Find returns genre by id or nil if there is no such genre.
+func (t *GenreTree) Find(id string) *Genre {
+	return t.byID[id]
+}
+
+// Parent returns parent of the genre or nil if the genre is top-level
+// or unknown.
+func (t *GenreTree) Parent(id string) *Genre {
+	return t.parent[id]
+}
+
+// Walk calls fn for each genre depth-first, parents before their
+// sub-genres. It returns error returned by fn other than SkipSubGenres.
+func (t *GenreTree) Walk(fn WalkFunc) error {
+	return walkGenres(t.Genres, 0, fn)
+}
+
+func walkGenres(genres []Genre, depth int, fn WalkFunc) error {
+	for i := range genres {
+		err := fn(&genres[i], depth)
+		if err == SkipSubGenres {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := walkGenres(genres[i].SubGenres, depth+1, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Title returns title of the genre in the language like "en" or "en-US".
+// It falls back to the base language and then to the default title.
+func (g *Genre) Title(lang string) string {
+	if title, ok := g.localized(lang); ok && title.Title != "" {
+		return title.Title
+	}
+	return g.DefaultTitle
+}
+
+// FullTitle returns full title of the genre in the language with the same
+// fallbacks as Title. Title is used if there is no full title.
+func (g *Genre) FullTitle(lang string) string {
+	if title, ok := g.localized(lang); ok && title.Title != "" {
+		if title.FullTitle != "" {
+			return title.FullTitle
+		}
+		return title.Title
+	}
+	if g.DefaultFullTitle != "" {
+		return g.DefaultFullTitle
+	}
+	return g.DefaultTitle
+}
+
+// localized returns title in the language or its base language.
+func (g *Genre) localized(lang string) (GenreTitle, bool) {
+	lang = strings.ToLower(lang)
+	if title, ok := g.Titles[lang]; ok {
+		return title, true
+	}
+
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		title, ok := g.Titles[lang[:i]]
+		return title, ok
+	}
+
+	return GenreTitle{}, false
+}
+
+// ImageURL returns absolute URL of the genre's image of the size like
+// "300x300" or empty string if there is no such image.
+func (g *Genre) ImageURL(size string) string {
+	uri := g.Images[size]
+	if uri == "" || strings.Contains(uri, "://") {
+		return uri
+	}
+	return "https://" + uri
+}
+
+// RadioIconURL returns absolute URL of the radio icon of the size like
+// "200x200" or empty string if the genre has no radio.
+func (g *Genre) RadioIconURL(size string) string {
+	return CoverURL(g.RadioIcon.ImageURL, size)
+}
+
+// RadioStationID returns id of the genre's radio station like "genre:rock"
+// or empty string if the genre has no radio.
+func (g *Genre) RadioStationID() string {
+	if g.RadioIcon.ImageURL == "" {
+		return ""
+	}
+	return "genre:" + g.ID
+}
@@ -3,11 +3,13 @@ package yamusic
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGenresService_List(t *testing.T) {
@@ -30,3 +32,189 @@ func TestGenresService_List(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
 }
+
+const genresTreeJSON = `{
+	"invocationInfo": {"req-id": "Genres.Tree"},
+	"result": [
+		{
+			"id": "rock",
+			"title": "Рок",
+			"fullTitle": "Рок-музыка",
+			"titles": {"en": {"title": "Rock"}, "uk": {"title": "Рок", "fullTitle": "Рок-музика"}},
+			"images": {"208x208": "avatars.yandex.net/rock/208x208", "300x300": "https://avatars.yandex.net/rock/300x300"},
+			"radioIcon": {"backgroundColor": "#ff0000", "imageUrl": "avatars.yandex.net/rock-icon/%%"},
+			"subGenres": [
+				{"id": "punk", "title": "Панк", "titles": {"en": {"title": "Punk"}}},
+				{"id": "metal", "title": "Метал", "subGenres": [{"id": "doom", "title": "Дум"}]}
+			]
+		},
+		{"id": "jazz", "title": "Джаз"}
+	]
+}`
+
+func TestGenresService_Tree(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/genres", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		fmt.Fprint(w, genresTreeJSON)
+	})
+
+	result, _, err := client.Genres().Tree(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Genres.Tree", result.InvocationInfo.ReqID)
+
+	tree := &result.Result
+	require.Len(t, tree.Genres, 2)
+
+	assert.Equal(t, "Дум", tree.Find("doom").DefaultTitle)
+	assert.Nil(t, tree.Find("pop"))
+	assert.Equal(t, "metal", tree.Parent("doom").ID)
+	assert.Equal(t, "rock", tree.Parent("metal").ID)
+	assert.Nil(t, tree.Parent("rock"))
+	assert.Nil(t, tree.Parent("pop"))
+
+	// Tree is encoded as array of genres.
+	b, err := json.Marshal(tree)
+	require.NoError(t, err)
+	decoded := new(GenreTree)
+	require.NoError(t, json.Unmarshal(b, decoded))
+	assert.Equal(t, "metal", decoded.Parent("doom").ID)
+}
+
+func TestGenreTree_Walk(t *testing.T) {
+	var tree GenresTreeResp
+	require.NoError(t, json.Unmarshal([]byte(genresTreeJSON), &tree))
+
+	var visited []string
+	err := tree.Result.Walk(func(genre *Genre, depth int) error {
+		visited = append(visited, fmt.Sprintf("%d:%s", depth, genre.ID))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0:rock", "1:punk", "1:metal", "2:doom", "0:jazz"}, visited)
+
+	visited = nil
+	err = tree.Result.Walk(func(genre *Genre, depth int) error {
+		visited = append(visited, genre.ID)
+		if genre.ID == "metal" {
+			return SkipSubGenres
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rock", "punk", "metal", "jazz"}, visited)
+
+	stop := errors.New("stop")
+	visited = nil
+	err = tree.Result.Walk(func(genre *Genre, depth int) error {
+		visited = append(visited, genre.ID)
+		if genre.ID == "punk" {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, []string{"rock", "punk"}, visited)
+}
+
+func TestGenre_Title(t *testing.T) {
+	tree := NewGenreTree([]Genre{{
+		ID:               "rock",
+		DefaultTitle:     "Рок",
+		DefaultFullTitle: "Рок-музыка",
+		Titles: map[string]GenreTitle{
+			"en": {Title: "Rock"},
+			"uk": {Title: "Рок", FullTitle: "Рок-музика"},
+		},
+	}})
+	rock := tree.Find("rock")
+
+	assert.Equal(t, "Rock", rock.Title("en"))
+	assert.Equal(t, "Rock", rock.Title("en-US"))
+	assert.Equal(t, "Рок", rock.Title("de"))
+	assert.Equal(t, "Рок", rock.Title(""))
+
+	assert.Equal(t, "Rock", rock.FullTitle("EN"))
+	assert.Equal(t, "Рок-музика", rock.FullTitle("uk"))
+	assert.Equal(t, "Рок-музыка", rock.FullTitle("de"))
+}
+
+func TestGenre_Images(t *testing.T) {
+	var tree GenresTreeResp
+	require.NoError(t, json.Unmarshal([]byte(genresTreeJSON), &tree))
+
+	rock := tree.Result.Find("rock")
+	assert.Equal(t, "https://avatars.yandex.net/rock/208x208", rock.ImageURL("208x208"))
+	assert.Equal(t, "https://avatars.yandex.net/rock/300x300", rock.ImageURL("300x300"))
+	assert.Empty(t, rock.ImageURL("1000x1000"))
+	assert.Equal(t, "https://avatars.yandex.net/rock-icon/200x200", rock.RadioIconURL("200x200"))
+	assert.Equal(t, "genre:rock", rock.RadioStationID())
+
+	jazz := tree.Result.Find("jazz")
+	assert.Empty(t, jazz.RadioIconURL("200x200"))
+	assert.Empty(t, jazz.RadioStationID())
+}
+
+func TestGenresService_Overview(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/metatags/rock", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"result": {
+			"id": "rock",
+			"title": {"title": "Рок", "fullTitle": "Рок-музыка"},
+			"tracks": [{"id": "1", "title": "Smoke on the Water"}],
+			"albums": [{"id": 10, "title": "Machine Head"}],
+			"artists": [{"id": 100, "name": "Deep Purple"}],
+			"playlists": [{"uid": 1000, "kind": 3, "title": "Rock hits"}]
+		}}`)
+	})
+
+	result, _, err := client.Genres().Overview(context.Background(), "rock")
+	require.NoError(t, err)
+
+	overview := result.Result
+	assert.Equal(t, "rock", overview.ID)
+	assert.Equal(t, "Рок-музыка", overview.Title.FullTitle)
+	require.Len(t, overview.Tracks, 1)
+	assert.Equal(t, "Smoke on the Water", overview.Tracks[0].Title)
+	require.Len(t, overview.Albums, 1)
+	assert.Equal(t, 10, overview.Albums[0].ID)
+	require.Len(t, overview.Artists, 1)
+	assert.Equal(t, "Deep Purple", overview.Artists[0].Name)
+	require.Len(t, overview.Playlists, 1)
+	assert.Equal(t, 3, overview.Playlists[0].Kind)
+}
+
+func TestGenresService_Albums(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/metatags/rock/albums", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		fmt.Fprintf(w, `{"result": {
+			"albums": [{"id": 10, "title": "Machine Head"}],
+			"pager": {"total": 100, "page": 0, "perPage": 10}
+		}, "invocationInfo": {"req-id": %q}}`, r.URL.RawQuery)
+	})
+
+	result, _, err := client.Genres().Albums(
+		context.Background(),
+		"rock",
+		GenreAlbumsSortYear,
+		&GenreAlbumsOptions{Page: 2, PageSize: 10},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "limit=10&offset=20&sortBy=year", result.InvocationInfo.ReqID)
+	require.Len(t, result.Result.Albums, 1)
+	assert.Equal(t, "Machine Head", result.Result.Albums[0].Title)
+	assert.Equal(t, 100, result.Result.Pager.Total)
+
+	result, _, err = client.Genres().Albums(context.Background(), "rock", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "limit=25&offset=0", result.InvocationInfo.ReqID)
+}
@@ -0,0 +1,356 @@
+package yamusic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Landing block types which can be requested by LandingService.Get.
+const (
+	LandingBlockPersonalPlaylists = "personalplaylists"
+	LandingBlockPromotions        = "promotions"
+	LandingBlockNewReleases       = "new-releases"
+	LandingBlockNewPlaylists      = "new-playlists"
+	LandingBlockMixes             = "mixes"
+	LandingBlockChart             = "chart"
+	LandingBlockArtists           = "artists"
+	LandingBlockAlbums            = "albums"
+	LandingBlockPlaylists         = "playlists"
+	LandingBlockPlayContexts      = "play-contexts"
+)
+
+// AllLandingBlocks is a list of all known landing blocks.
+var AllLandingBlocks = []string{
+	LandingBlockPersonalPlaylists,
+	LandingBlockPromotions,
+	LandingBlockNewReleases,
+	LandingBlockNewPlaylists,
+	LandingBlockMixes,
+	LandingBlockChart,
+	LandingBlockArtists,
+	LandingBlockAlbums,
+	LandingBlockPlaylists,
+	LandingBlockPlayContexts,
+}
+
+// Chart regions for LandingService.Chart. Empty region means user's region.
+const (
+	ChartRegionWorld  = "world"
+	ChartRegionRussia = "russia"
+)
+
+type (
+	// LandingService is a service to deal with editorial landing page.
+	LandingService struct {
+		client *Client
+	}
+
+	// LandingResp describes get landing method response
+	LandingResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         struct {
+			PumpkinMode   bool           `json:"pumpkin"`
+			ContentID     string         `json:"contentId"`
+			Blocks        []LandingBlock `json:"blocks"`
+			ReloadDelayMs int            `json:"reloadDelayMs,omitempty"`
+		} `json:"result"`
+	}
+
+	// LandingBlock is a block of the landing page.
+	LandingBlock struct {
+		ID          string               `json:"id"`
+		Type        string               `json:"type"`
+		TypeForFrom string               `json:"typeForFrom"`
+		Title       string               `json:"title"`
+		Description string               `json:"description,omitempty"`
+		Entities    []LandingBlockEntity `json:"entities"`
+	}
+
+	// LandingBlockEntity is an item of the landing block. Data depends on
+	// Type: *FeedGeneratedPlaylist for "personal-playlist", *LandingPromotion
+	// for "promotion", *Album for "album", *PlaylistsResult for "playlist",
+	// *ChartItem for "chart-item", *LandingPlayContext for "play-context",
+	// *LandingMixLink for "mix-link" and *Artist for "artist".
+	// Data of unknown types is kept as json.RawMessage.
+	LandingBlockEntity struct {
+		ID   string      `json:"id"`
+		Type string      `json:"type"`
+		Data interface{} `json:"data"`
+	}
+
+	// LandingPromotion is a promotion on the landing page.
+	LandingPromotion struct {
+		PromoID     string `json:"promoId"`
+		Title       string `json:"title"`
+		Subtitle    string `json:"subtitle"`
+		Heading     string `json:"heading"`
+		URL         string `json:"url"`
+		URLScheme   string `json:"urlScheme"`
+		TextColor   string `json:"textColor"`
+		Gradient    string `json:"gradient"`
+		Image       string `json:"image"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// LandingMixLink is a link to a mix (editorial collection).
+	LandingMixLink struct {
+		Title              string `json:"title"`
+		URL                string `json:"url"`
+		URLScheme          string `json:"urlScheme"`
+		TextColor          string `json:"textColor"`
+		BackgroundColor    string `json:"backgroundColor"`
+		BackgroundImageURI string `json:"backgroundImageUri"`
+		CoverWhite         string `json:"coverWhite"`
+	}
+
+	// LandingPlayContext is a recently played context (album, playlist, artist).
+	LandingPlayContext struct {
+		Client      string `json:"client"`
+		Context     string `json:"context"`
+		ContextItem string `json:"contextItem"`
+		Tracks      []struct {
+			TrackID   string `json:"trackId"`
+			AlbumID   string `json:"albumId"`
+			Timestamp string `json:"timestamp"`
+		} `json:"tracks"`
+	}
+
+	// ChartItem is a track of the chart with its position.
+	ChartItem struct {
+		Track Track         `json:"track"`
+		Chart ChartPosition `json:"chart"`
+	}
+
+	// ChartPosition is position of a track in the chart and its change
+	// since the previous chart.
+	ChartPosition struct {
+		Position  int    `json:"position"`
+		Progress  string `json:"progress"`
+		Listeners int    `json:"listeners"`
+		Shift     int    `json:"shift"`
+		BgColor   string `json:"bgColor,omitempty"`
+	}
+
+	// ChartResp describes get chart method response
+	ChartResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         struct {
+			ID               string `json:"id"`
+			Type             string `json:"type"`
+			TypeForFrom      string `json:"typeForFrom"`
+			Title            string `json:"title"`
+			ChartDescription string `json:"chartDescription"`
+			Menu             struct {
+				Items []struct {
+					Title    string `json:"title"`
+					URL      string `json:"url"`
+					Selected bool   `json:"selected,omitempty"`
+				} `json:"items"`
+			} `json:"menu"`
+			Chart struct {
+				PlaylistsResult
+				Tracks Tracks `json:"tracks"`
+			} `json:"chart"`
+		} `json:"result"`
+	}
+
+	// NewReleasesResp describes get new releases method response.
+	// Albums can be resolved by AlbumsService.GetMany.
+	NewReleasesResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         struct {
+			ID          string `json:"id"`
+			Type        string `json:"type"`
+			TypeForFrom string `json:"typeForFrom"`
+			Title       string `json:"title"`
+			NewReleases []int  `json:"newReleases"`
+		} `json:"result"`
+	}
+
+	// NewPlaylistsResp describes get new playlists method response.
+	// Playlists can be resolved by PlaylistsService.GetByIDs.
+	NewPlaylistsResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         struct {
+			ID           string       `json:"id"`
+			Type         string       `json:"type"`
+			TypeForFrom  string       `json:"typeForFrom"`
+			Title        string       `json:"title"`
+			NewPlaylists []PlaylistID `json:"newPlaylists"`
+		} `json:"result"`
+	}
+)
+
+// Chart progress values of ChartPosition.
+const (
+	ChartProgressUp   = "up"
+	ChartProgressDown = "down"
+	ChartProgressSame = "same"
+	ChartProgressNew  = "new"
+)
+
+// IsNew reports whether the track has just entered the chart.
+func (p ChartPosition) IsNew() bool {
+	return p.Progress == ChartProgressNew
+}
+
+// Delta returns change of the position since the previous chart.
+// Positive value means the track went up.
+func (p ChartPosition) Delta() int {
+	switch p.Progress {
+	case ChartProgressDown:
+		if p.Shift > 0 {
+			return -p.Shift
+		}
+	case ChartProgressSame, ChartProgressNew:
+		return 0
+	}
+
+	return p.Shift
+}
+
+// UnmarshalJSON decodes entity's data into a concrete type chosen by its type.
+func (e *LandingBlockEntity) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID   string          `json:"id"`
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	e.ID = raw.ID
+	e.Type = raw.Type
+	e.Data = nil
+
+	if len(raw.Data) == 0 {
+		return nil
+	}
+
+	var v interface{}
+	switch raw.Type {
+	case "personal-playlist":
+		v = new(FeedGeneratedPlaylist)
+	case "promotion":
+		v = new(LandingPromotion)
+	case "album":
+		v = new(Album)
+	case "playlist":
+		v = new(PlaylistsResult)
+	case "chart-item":
+		v = new(ChartItem)
+	case "play-context":
+		v = new(LandingPlayContext)
+	case "mix-link":
+		v = new(LandingMixLink)
+	case "artist":
+		v = new(Artist)
+	default:
+		e.Data = append(json.RawMessage(nil), raw.Data...)
+		return nil
+	}
+
+	if err := json.Unmarshal(raw.Data, v); err != nil {
+		return fmt.Errorf("decode landing entity %q of type %q: %w", raw.ID, raw.Type, err)
+	}
+
+	e.Data = v
+	return nil
+}
+
+// Get returns landing page with requested blocks.
+// If no blocks are passed all known blocks are requested.
+func (s *LandingService) Get(
+	ctx context.Context,
+	blocks []string,
+	reqOpts ...RequestOption,
+) (*LandingResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LandingService.Get", reqOpts)
+	defer call.end()
+
+	if len(blocks) == 0 {
+		blocks = AllLandingBlocks
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("blocks", strings.Join(blocks, ","))
+
+	uri := fmt.Sprintf("landing3?%v", queryParams.Encode())
+	req, err := s.client.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	landing := new(LandingResp)
+	resp, err := s.client.Do(ctx, req, landing)
+	return landing, resp, err
+}
+
+// Chart returns chart of the region. Empty region means region of the user.
+func (s *LandingService) Chart(
+	ctx context.Context,
+	region string,
+	reqOpts ...RequestOption,
+) (*ChartResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LandingService.Chart", reqOpts)
+	defer call.end()
+
+	uri := "landing3/chart"
+	if region != "" {
+		uri += "/" + url.PathEscape(region)
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chart := new(ChartResp)
+	resp, err := s.client.Do(ctx, req, chart)
+	return chart, resp, err
+}
+
+// NewReleases returns ids of new albums
+func (s *LandingService) NewReleases(
+	ctx context.Context,
+	reqOpts ...RequestOption,
+) (*NewReleasesResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LandingService.NewReleases", reqOpts)
+	defer call.end()
+
+	req, err := s.client.NewRequest(http.MethodGet, "landing3/new-releases", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	releases := new(NewReleasesResp)
+	resp, err := s.client.Do(ctx, req, releases)
+	return releases, resp, err
+}
+
+// NewPlaylists returns ids of new playlists
+func (s *LandingService) NewPlaylists(
+	ctx context.Context,
+	reqOpts ...RequestOption,
+) (*NewPlaylistsResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LandingService.NewPlaylists", reqOpts)
+	defer call.end()
+
+	req, err := s.client.NewRequest(http.MethodGet, "landing3/new-playlists", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	playlists := new(NewPlaylistsResp)
+	resp, err := s.client.Do(ctx, req, playlists)
+	return playlists, resp, err
+}
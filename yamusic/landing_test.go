@@ -0,0 +1,121 @@
+package yamusic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLandingService_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/landing3", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+		assert.Equal(t, "chart,promotions", r.URL.Query().Get("blocks"))
+		fmt.Fprint(w, `{
+			"invocationInfo": {"req-id": "Landing.Get"},
+			"result": {"blocks": [{
+				"id": "b1",
+				"type": "chart",
+				"entities": [
+					{"id": "e1", "type": "chart-item", "data": {"track": {"id": "1"}, "chart": {"position": 1, "progress": "up", "shift": 2}}},
+					{"id": "e2", "type": "promotion", "data": {"promoId": "p"}},
+					{"id": "e3", "type": "something-new", "data": {"a": 1}}
+				]
+			}]}
+		}`)
+	})
+
+	result, _, err := client.Landing().Get(
+		context.Background(),
+		[]string{LandingBlockChart, LandingBlockPromotions},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Landing.Get", result.InvocationInfo.ReqID)
+
+	entities := result.Result.Blocks[0].Entities
+	assert.Len(t, entities, 3)
+	item := entities[0].Data.(*ChartItem)
+	assert.Equal(t, "1", item.Track.ID)
+	assert.Equal(t, 2, item.Chart.Delta())
+	assert.Equal(t, "p", entities[1].Data.(*LandingPromotion).PromoID)
+	assert.JSONEq(t, `{"a": 1}`, string(entities[2].Data.(json.RawMessage)))
+}
+
+func TestLandingService_Chart(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &ChartResp{}
+	want.InvocationInfo.ReqID = "Landing.Chart"
+
+	mux.HandleFunc("/landing3/chart/world", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	result, _, err := client.Landing().Chart(context.Background(), ChartRegionWorld)
+
+	assert.NoError(t, err)
+	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
+}
+
+func TestLandingService_NewReleases(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &NewReleasesResp{}
+	want.InvocationInfo.ReqID = "Landing.NewReleases"
+	want.Result.NewReleases = []int{1, 2}
+
+	mux.HandleFunc("/landing3/new-releases", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	result, _, err := client.Landing().NewReleases(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, want.Result.NewReleases, result.Result.NewReleases)
+}
+
+func TestLandingService_NewPlaylists(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &NewPlaylistsResp{}
+	want.InvocationInfo.ReqID = "Landing.NewPlaylists"
+	want.Result.NewPlaylists = []PlaylistID{{UID: 1, Kind: 2}}
+
+	mux.HandleFunc("/landing3/new-playlists", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	result, _, err := client.Landing().NewPlaylists(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, want.Result.NewPlaylists, result.Result.NewPlaylists)
+}
+
+func TestChartPosition_Delta(t *testing.T) {
+	assert.Equal(t, 3, ChartPosition{Progress: ChartProgressUp, Shift: 3}.Delta())
+	assert.Equal(t, -3, ChartPosition{Progress: ChartProgressDown, Shift: 3}.Delta())
+	assert.Equal(t, -3, ChartPosition{Progress: ChartProgressDown, Shift: -3}.Delta())
+	assert.Equal(t, 0, ChartPosition{Progress: ChartProgressNew}.Delta())
+	assert.True(t, ChartPosition{Progress: ChartProgressNew}.IsNew())
+}
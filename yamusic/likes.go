@@ -0,0 +1,299 @@
+package yamusic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type (
+	// LikesService is a service to deal with liked and disliked tracks,
+	// albums and artists of the user.
+	LikesService struct {
+		client *Client
+	}
+	// LikesTracksResp describes get liked or disliked tracks response
+	LikesTracksResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         struct {
+			Library struct {
+				UID      int          `json:"uid"`
+				Revision int          `json:"revision"`
+				Tracks   []LikedTrack `json:"tracks"`
+			} `json:"library"`
+		} `json:"result"`
+	}
+	// LikedTrack is a short info of liked or disliked track
+	LikedTrack struct {
+		ID        string    `json:"id"`
+		AlbumID   string    `json:"albumId,omitempty"`
+		Timestamp time.Time `json:"timestamp"`
+	}
+	// LikesAlbumsResp describes get liked albums response
+	LikesAlbumsResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         []LikedAlbum   `json:"result"`
+	}
+	// LikedAlbum is a liked album
+	LikedAlbum struct {
+		ID        int       `json:"id"`
+		Timestamp time.Time `json:"timestamp"`
+		Album     Album     `json:"album"`
+	}
+	// LikesArtistsResp describes get liked artists response
+	LikesArtistsResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         []LikedArtist  `json:"result"`
+	}
+	// LikedArtist is a liked artist
+	LikedArtist struct {
+		ID        int       `json:"id"`
+		Timestamp time.Time `json:"timestamp"`
+		Artist    Artist    `json:"artist"`
+	}
+	// LikesTracksChangeResp describes like or dislike tracks response
+	LikesTracksChangeResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         struct {
+			Revision int `json:"revision"`
+		} `json:"result"`
+	}
+	// LikesChangeResp describes like albums or artists response
+	LikesChangeResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         string         `json:"result"`
+	}
+)
+
+// String returns track id in "trackID:albumID" form
+func (t LikedTrack) String() string {
+	if t.AlbumID == "" {
+		return t.ID
+	}
+	return t.ID + ":" + t.AlbumID
+}
+
+// Tracks returns tracks liked by the user
+func (s *LikesService) Tracks(
+	ctx context.Context,
+	reqOpts ...RequestOption,
+) (*LikesTracksResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LikesService.Tracks", reqOpts)
+	defer call.end()
+
+	return s.tracks(ctx, "likes")
+}
+
+// DislikedTracks returns tracks disliked by the user
+func (s *LikesService) DislikedTracks(
+	ctx context.Context,
+	reqOpts ...RequestOption,
+) (*LikesTracksResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LikesService.DislikedTracks", reqOpts)
+	defer call.end()
+
+	return s.tracks(ctx, "dislikes")
+}
+
+func (s *LikesService) tracks(
+	ctx context.Context,
+	library string,
+) (*LikesTracksResp, *http.Response, error) {
+	uri := fmt.Sprintf(
+		"users/%v/%v/tracks?if-modified-since-revision=0",
+		s.client.userIDFrom(ctx),
+		library,
+	)
+
+	req, err := s.client.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tracks := new(LikesTracksResp)
+	resp, err := s.client.Do(ctx, req, tracks)
+	return tracks, resp, err
+}
+
+// Albums returns albums liked by the user
+func (s *LikesService) Albums(
+	ctx context.Context,
+	reqOpts ...RequestOption,
+) (*LikesAlbumsResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LikesService.Albums", reqOpts)
+	defer call.end()
+
+	uri := fmt.Sprintf("users/%v/likes/albums?rich=true", s.client.userIDFrom(ctx))
+	req, err := s.client.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	albums := new(LikesAlbumsResp)
+	resp, err := s.client.Do(ctx, req, albums)
+	return albums, resp, err
+}
+
+// Artists returns artists liked by the user
+func (s *LikesService) Artists(
+	ctx context.Context,
+	reqOpts ...RequestOption,
+) (*LikesArtistsResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LikesService.Artists", reqOpts)
+	defer call.end()
+
+	uri := fmt.Sprintf("users/%v/likes/artists?with-timestamps=true", s.client.userIDFrom(ctx))
+	req, err := s.client.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	artists := new(LikesArtistsResp)
+	resp, err := s.client.Do(ctx, req, artists)
+	return artists, resp, err
+}
+
+// LikeTracks likes tracks. IDs are either "trackID" or "trackID:albumID"
+func (s *LikesService) LikeTracks(
+	ctx context.Context,
+	ids []string,
+	reqOpts ...RequestOption,
+) (*LikesTracksChangeResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LikesService.LikeTracks", reqOpts)
+	defer call.end()
+
+	return s.changeTracks(ctx, "likes", "add-multiple", ids)
+}
+
+// UnlikeTracks removes tracks from liked
+func (s *LikesService) UnlikeTracks(
+	ctx context.Context,
+	ids []string,
+	reqOpts ...RequestOption,
+) (*LikesTracksChangeResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LikesService.UnlikeTracks", reqOpts)
+	defer call.end()
+
+	return s.changeTracks(ctx, "likes", "remove", ids)
+}
+
+// DislikeTracks dislikes tracks
+func (s *LikesService) DislikeTracks(
+	ctx context.Context,
+	ids []string,
+	reqOpts ...RequestOption,
+) (*LikesTracksChangeResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LikesService.DislikeTracks", reqOpts)
+	defer call.end()
+
+	return s.changeTracks(ctx, "dislikes", "add-multiple", ids)
+}
+
+// UndislikeTracks removes tracks from disliked
+func (s *LikesService) UndislikeTracks(
+	ctx context.Context,
+	ids []string,
+	reqOpts ...RequestOption,
+) (*LikesTracksChangeResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LikesService.UndislikeTracks", reqOpts)
+	defer call.end()
+
+	return s.changeTracks(ctx, "dislikes", "remove", ids)
+}
+
+func (s *LikesService) changeTracks(
+	ctx context.Context,
+	library string,
+	action string,
+	ids []string,
+) (*LikesTracksChangeResp, *http.Response, error) {
+	form := url.Values{}
+	form.Set("track-ids", strings.Join(ids, ","))
+
+	uri := fmt.Sprintf("users/%v/%v/tracks/%v", s.client.userIDFrom(ctx), library, action)
+	req, err := s.client.NewRequest(http.MethodPost, uri, form)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	change := new(LikesTracksChangeResp)
+	resp, err := s.client.Do(ctx, req, change)
+	return change, resp, err
+}
+
+// LikeAlbums likes albums
+func (s *LikesService) LikeAlbums(
+	ctx context.Context,
+	ids []int,
+	reqOpts ...RequestOption,
+) (*LikesChangeResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LikesService.LikeAlbums", reqOpts)
+	defer call.end()
+
+	return s.change(ctx, "albums", "add-multiple", ids)
+}
+
+// UnlikeAlbums removes albums from liked
+func (s *LikesService) UnlikeAlbums(
+	ctx context.Context,
+	ids []int,
+	reqOpts ...RequestOption,
+) (*LikesChangeResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LikesService.UnlikeAlbums", reqOpts)
+	defer call.end()
+
+	return s.change(ctx, "albums", "remove", ids)
+}
+
+// LikeArtists likes artists
+func (s *LikesService) LikeArtists(
+	ctx context.Context,
+	ids []int,
+	reqOpts ...RequestOption,
+) (*LikesChangeResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LikesService.LikeArtists", reqOpts)
+	defer call.end()
+
+	return s.change(ctx, "artists", "add-multiple", ids)
+}
+
+// UnlikeArtists removes artists from liked
+func (s *LikesService) UnlikeArtists(
+	ctx context.Context,
+	ids []int,
+	reqOpts ...RequestOption,
+) (*LikesChangeResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "LikesService.UnlikeArtists", reqOpts)
+	defer call.end()
+
+	return s.change(ctx, "artists", "remove", ids)
+}
+
+func (s *LikesService) change(
+	ctx context.Context,
+	objectType string,
+	action string,
+	ids []int,
+) (*LikesChangeResp, *http.Response, error) {
+	form := url.Values{}
+	// album-ids or artist-ids
+	form.Set(strings.TrimSuffix(objectType, "s")+"-ids", joinInts(ids))
+
+	uri := fmt.Sprintf("users/%v/likes/%v/%v", s.client.userIDFrom(ctx), objectType, action)
+	req, err := s.client.NewRequest(http.MethodPost, uri, form)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	change := new(LikesChangeResp)
+	resp, err := s.client.Do(ctx, req, change)
+	return change, resp, err
+}
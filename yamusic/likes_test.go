@@ -0,0 +1,150 @@
+package yamusic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLikesService_Tracks(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &LikesTracksResp{}
+	want.InvocationInfo.ReqID = "Likes.Tracks"
+	want.Result.Library.UID = userID
+	want.Result.Library.Revision = 10
+	want.Result.Library.Tracks = []LikedTrack{{ID: "1", AlbumID: "2"}, {ID: "3"}}
+
+	mux.HandleFunc(
+		fmt.Sprintf("/users/%v/likes/tracks", userID),
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "0", r.URL.Query().Get("if-modified-since-revision"))
+			b, err := json.Marshal(want)
+			assert.NoError(t, err)
+			fmt.Fprint(w, string(b))
+		},
+	)
+
+	result, _, err := client.Likes().Tracks(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, want, result)
+	assert.Equal(t, "1:2", result.Result.Library.Tracks[0].String())
+	assert.Equal(t, "3", result.Result.Library.Tracks[1].String())
+}
+
+func TestLikesService_DislikedTracks(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(
+		fmt.Sprintf("/users/%v/dislikes/tracks", userID),
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"result":{"library":{"uid":2000,"tracks":[{"id":"5","albumId":"6"}]}}}`)
+		},
+	)
+
+	result, _, err := client.Likes().DislikedTracks(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []LikedTrack{{ID: "5", AlbumID: "6"}}, result.Result.Library.Tracks)
+}
+
+func TestLikesService_AlbumsAndArtists(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(
+		fmt.Sprintf("/users/%v/likes/albums", userID),
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "true", r.URL.Query().Get("rich"))
+			fmt.Fprint(w, `{"result":[{"id":1,"album":{"id":1,"title":"Help!"}}]}`)
+		},
+	)
+	mux.HandleFunc(
+		fmt.Sprintf("/users/%v/likes/artists", userID),
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "true", r.URL.Query().Get("with-timestamps"))
+			fmt.Fprint(w, `{"result":[{"id":2,"artist":{"id":2,"name":"The Beatles"}}]}`)
+		},
+	)
+
+	albums, _, err := client.Likes().Albums(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, albums.Result, 1)
+	assert.Equal(t, "Help!", albums.Result[0].Album.Title)
+
+	artists, _, err := client.Likes().Artists(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, artists.Result, 1)
+	assert.Equal(t, "The Beatles", artists.Result[0].Artist.Name)
+}
+
+func TestLikesService_ChangeTracks(t *testing.T) {
+	setup()
+	defer teardown()
+
+	tests := []struct {
+		path   string
+		change func(context.Context, []string, ...RequestOption) (*LikesTracksChangeResp, *http.Response, error)
+	}{
+		{"likes/tracks/add-multiple", client.Likes().LikeTracks},
+		{"likes/tracks/remove", client.Likes().UnlikeTracks},
+		{"dislikes/tracks/add-multiple", client.Likes().DislikeTracks},
+		{"dislikes/tracks/remove", client.Likes().UndislikeTracks},
+	}
+
+	for i, tt := range tests {
+		revision := i + 1
+		mux.HandleFunc(
+			fmt.Sprintf("/users/%v/%v", userID, tt.path),
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "1:2,3", r.FormValue("track-ids"))
+				fmt.Fprintf(w, `{"result":{"revision":%d}}`, revision)
+			},
+		)
+
+		result, _, err := tt.change(context.Background(), []string{"1:2", "3"})
+		assert.NoError(t, err, tt.path)
+		assert.Equal(t, revision, result.Result.Revision, tt.path)
+	}
+}
+
+func TestLikesService_Change(t *testing.T) {
+	setup()
+	defer teardown()
+
+	tests := []struct {
+		path   string
+		field  string
+		change func(context.Context, []int, ...RequestOption) (*LikesChangeResp, *http.Response, error)
+	}{
+		{"likes/albums/add-multiple", "album-ids", client.Likes().LikeAlbums},
+		{"likes/albums/remove", "album-ids", client.Likes().UnlikeAlbums},
+		{"likes/artists/add-multiple", "artist-ids", client.Likes().LikeArtists},
+		{"likes/artists/remove", "artist-ids", client.Likes().UnlikeArtists},
+	}
+
+	for _, tt := range tests {
+		field := tt.field
+		mux.HandleFunc(
+			fmt.Sprintf("/users/%v/%v", userID, tt.path),
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "1,2", r.FormValue(field))
+				fmt.Fprint(w, `{"result":"ok"}`)
+			},
+		)
+
+		result, _, err := tt.change(context.Background(), []int{1, 2})
+		assert.NoError(t, err, tt.path)
+		assert.Equal(t, "ok", result.Result, tt.path)
+	}
+}
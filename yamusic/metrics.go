@@ -0,0 +1,488 @@
+package yamusic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// StatusClassError is status class of requests failed without response.
+const StatusClassError = "error"
+
+var (
+	// DefaultDurationBuckets are upper bounds in seconds of request duration
+	// histogram buckets
+	DefaultDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	// DefaultSizeBuckets are upper bounds in bytes of response size
+	// histogram buckets
+	DefaultSizeBuckets = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+)
+
+// endpointTemplates are templates of known API endpoints. If a path
+// matches several templates of the same length the one with more literal
+// segments wins, then the first one.
+var endpointTemplates = []string{
+	"users/{uid}/playlists",
+	"users/{uid}/playlists/list",
+	"users/{uid}/playlists/create",
+	"users/{uid}/playlists/{kind}",
+	"users/{uid}/playlists/{kind}/name",
+	"users/{uid}/playlists/{kind}/delete",
+	"users/{uid}/playlists/{kind}/change-relative",
+	"users/{uid}/likes/tracks",
+	"users/{uid}/likes/albums",
+	"users/{uid}/likes/artists",
+	"users/{uid}/dislikes/tracks",
+	"users/{uid}/likes/{type}/{action}",
+	"users/{uid}/dislikes/tracks/{action}",
+	"albums/{id}",
+	"albums/{id}/with-tracks",
+	"tracks/{id}",
+	"tracks/{id}/download-info",
+	"tracks/{id}/supplement",
+	"tracks/{id}/similar",
+	"queues/{id}",
+	"queues/{id}/update-position",
+	"metatags/{id}",
+	"metatags/{id}/albums",
+}
+
+type (
+	// RequestMetrics are metrics of a finished API request
+	RequestMetrics struct {
+		Method string
+		// Endpoint is template of URL path like "users/{uid}/playlists/{kind}"
+		Endpoint string
+		// StatusClass is class of response status code like "2xx" or
+		// StatusClassError if request failed without response
+		StatusClass string
+		// ErrorName is Error.Name of failed request's response or kind
+		// of transport error: "canceled", "timeout" or "transport"
+		ErrorName string
+		// Duration is time until response headers are received
+		Duration time.Duration
+		// ResponseSize is count of response body bytes read by the caller
+		ResponseSize int64
+	}
+
+	// MetricsRecorder records metrics of API requests. Implementations must
+	// be safe for concurrent use
+	MetricsRecorder interface {
+		RecordRequest(m RequestMetrics)
+	}
+
+	// MetricsOptions are options for MetricsMiddleware
+	MetricsOptions struct {
+		// Endpoint returns endpoint label of the request.
+		// Defaults to EndpointTemplate of the request's URL path
+		Endpoint func(req *http.Request) string
+		// Now returns current time. Defaults to time.Now
+		Now func() time.Time
+	}
+
+	metricsDoer struct {
+		next     Doer
+		recorder MetricsRecorder
+		opts     MetricsOptions
+	}
+
+	// metricsBody records metrics when response body is read till EOF
+	// or closed
+	metricsBody struct {
+		io.ReadCloser
+		metrics RequestMetrics
+		record  func(RequestMetrics)
+		once    sync.Once
+	}
+)
+
+// MetricsMiddleware returns middleware which records metrics of each
+// request to the recorder. Metrics are recorded once response body is read
+// till EOF or closed so that response size is known.
+func MetricsMiddleware(recorder MetricsRecorder, opts *MetricsOptions) Middleware {
+	if opts == nil {
+		opts = &MetricsOptions{}
+	}
+
+	o := *opts
+	if o.Endpoint == nil {
+		o.Endpoint = func(req *http.Request) string {
+			return EndpointTemplate(req.URL.Path)
+		}
+	}
+	if o.Now == nil {
+		o.Now = time.Now
+	}
+
+	return func(next Doer) Doer {
+		return &metricsDoer{next: next, recorder: recorder, opts: o}
+	}
+}
+
+func (d *metricsDoer) Do(req *http.Request) (*http.Response, error) {
+	start := d.opts.Now()
+	resp, err := d.next.Do(req)
+
+	metrics := RequestMetrics{
+		Method:   req.Method,
+		Endpoint: d.opts.Endpoint(req),
+		Duration: d.opts.Now().Sub(start),
+	}
+
+	if err != nil {
+		metrics.StatusClass = StatusClassError
+		metrics.ErrorName = transportErrorName(err)
+		d.recorder.RecordRequest(metrics)
+		return nil, err
+	}
+
+	metrics.StatusClass = strconv.Itoa(resp.StatusCode/100) + "xx"
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		// Error responses are small, so read them to get the error name.
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			metrics.ErrorName = transportErrorName(err)
+			d.recorder.RecordRequest(metrics)
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		metrics.ErrorName = errorName(body)
+		metrics.ResponseSize = int64(len(body))
+		d.recorder.RecordRequest(metrics)
+		return resp, nil
+	}
+
+	if resp.ContentLength == 0 || req.Method == http.MethodHead {
+		d.recorder.RecordRequest(metrics)
+		return resp, nil
+	}
+
+	resp.Body = &metricsBody{ReadCloser: resp.Body, metrics: metrics, record: d.recorder.RecordRequest}
+	return resp, nil
+}
+
+func (b *metricsBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.metrics.ResponseSize += int64(n)
+	if err == io.EOF {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *metricsBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish()
+	return err
+}
+
+func (b *metricsBody) finish() {
+	b.once.Do(func() {
+		b.record(b.metrics)
+	})
+}
+
+// errorName returns name of API error in the response body.
+func errorName(body []byte) string {
+	var errResp struct {
+		Error Error `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errResp); err != nil {
+		return ""
+	}
+	return errResp.Error.Name
+}
+
+// transportErrorName returns kind of error of request failed without
+// response.
+func transportErrorName(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	default:
+		return "transport"
+	}
+}
+
+// EndpointTemplate returns template of the API URL path, so that requests
+// to the same endpoint have the same metrics label. For example
+// "/users/1/playlists/3" becomes "users/{uid}/playlists/{kind}".
+// Segments of unknown paths which look like ids are replaced with "{id}".
+func EndpointTemplate(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	best, bestLiterals := "", -1
+	for _, template := range endpointTemplates {
+		parts := strings.Split(template, "/")
+		if len(parts) != len(segments) {
+			continue
+		}
+
+		literals := 0
+		for i, part := range parts {
+			if strings.HasPrefix(part, "{") {
+				continue
+			}
+			if part != segments[i] {
+				literals = -1
+				break
+			}
+			literals++
+		}
+
+		if literals > bestLiterals {
+			best, bestLiterals = template, literals
+		}
+	}
+
+	if best != "" {
+		return best
+	}
+
+	for i, segment := range segments {
+		if looksLikeID(segment) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// looksLikeID reports whether the path segment is an id rather than a name
+// like "landing3": it starts with a digit or it's a long hash.
+func looksLikeID(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	if unicode.IsDigit(rune(segment[0])) {
+		return true
+	}
+	return len(segment) >= 16 && strings.IndexFunc(segment, unicode.IsDigit) >= 0
+}
+
+type (
+	// PrometheusOptions are options for PrometheusMetrics
+	PrometheusOptions struct {
+		// Namespace is prefix of metric names. Defaults to "yamusic_client"
+		Namespace string
+		// DurationBuckets default to DefaultDurationBuckets
+		DurationBuckets []float64
+		// SizeBuckets default to DefaultSizeBuckets
+		SizeBuckets []float64
+	}
+
+	// PrometheusMetrics is MetricsRecorder which exposes metrics in
+	// Prometheus text format. It's an http.Handler, so it can be mounted
+	// at /metrics. It is safe for concurrent use
+	PrometheusMetrics struct {
+		opts PrometheusOptions
+
+		mu        sync.Mutex
+		requests  map[requestLabels]uint64
+		durations map[requestLabels]*histogram
+		sizes     map[requestLabels]*histogram
+		errors    map[errorLabels]uint64
+	}
+
+	requestLabels struct {
+		method, endpoint, status string
+	}
+
+	errorLabels struct {
+		requestLabels
+		name string
+	}
+
+	histogram struct {
+		// counts are counts of observations per bucket, not cumulative
+		counts []uint64
+		sum    float64
+		count  uint64
+	}
+)
+
+// NewPrometheusMetrics returns empty Prometheus metrics.
+func NewPrometheusMetrics(opts *PrometheusOptions) *PrometheusMetrics {
+	if opts == nil {
+		opts = &PrometheusOptions{}
+	}
+
+	o := *opts
+	if o.Namespace == "" {
+		o.Namespace = "yamusic_client"
+	}
+	if o.DurationBuckets == nil {
+		o.DurationBuckets = DefaultDurationBuckets
+	}
+	if o.SizeBuckets == nil {
+		o.SizeBuckets = DefaultSizeBuckets
+	}
+
+	return &PrometheusMetrics{
+		opts:      o,
+		requests:  make(map[requestLabels]uint64),
+		durations: make(map[requestLabels]*histogram),
+		sizes:     make(map[requestLabels]*histogram),
+		errors:    make(map[errorLabels]uint64),
+	}
+}
+
+// RecordRequest records metrics of the request.
+func (p *PrometheusMetrics) RecordRequest(m RequestMetrics) {
+	labels := requestLabels{method: m.Method, endpoint: m.Endpoint, status: m.StatusClass}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests[labels]++
+
+	duration, ok := p.durations[labels]
+	if !ok {
+		duration = &histogram{counts: make([]uint64, len(p.opts.DurationBuckets))}
+		p.durations[labels] = duration
+	}
+	duration.observe(p.opts.DurationBuckets, m.Duration.Seconds())
+
+	size, ok := p.sizes[labels]
+	if !ok {
+		size = &histogram{counts: make([]uint64, len(p.opts.SizeBuckets))}
+		p.sizes[labels] = size
+	}
+	size.observe(p.opts.SizeBuckets, float64(m.ResponseSize))
+
+	switch m.StatusClass {
+	case StatusClassError, "4xx", "5xx":
+		p.errors[errorLabels{requestLabels: labels, name: m.ErrorName}]++
+	}
+}
+
+func (h *histogram) observe(buckets []float64, value float64) {
+	for i, bound := range buckets {
+		if value <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+// ServeHTTP writes metrics in Prometheus text format.
+func (p *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w) //nolint:errcheck
+}
+
+// WriteTo writes metrics in Prometheus text format to w.
+func (p *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := new(bytes.Buffer)
+	name := func(metric string) string {
+		return p.opts.Namespace + "_" + metric
+	}
+
+	labels := make([]requestLabels, 0, len(p.requests))
+	for l := range p.requests {
+		labels = append(labels, l)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].less(labels[j])
+	})
+
+	metric := name("requests_total")
+	fmt.Fprintf(out, "# HELP %s Count of Yandex.Music API requests.\n", metric)
+	fmt.Fprintf(out, "# TYPE %s counter\n", metric)
+	for _, l := range labels {
+		fmt.Fprintf(out, "%s{%s} %d\n", metric, l, p.requests[l])
+	}
+
+	metric = name("request_duration_seconds")
+	fmt.Fprintf(out, "# HELP %s Time until Yandex.Music API response headers are received.\n", metric)
+	fmt.Fprintf(out, "# TYPE %s histogram\n", metric)
+	for _, l := range labels {
+		p.durations[l].write(out, metric, l.String(), p.opts.DurationBuckets)
+	}
+
+	metric = name("response_size_bytes")
+	fmt.Fprintf(out, "# HELP %s Size of Yandex.Music API response bodies.\n", metric)
+	fmt.Fprintf(out, "# TYPE %s histogram\n", metric)
+	for _, l := range labels {
+		p.sizes[l].write(out, metric, l.String(), p.opts.SizeBuckets)
+	}
+
+	errLabels := make([]errorLabels, 0, len(p.errors))
+	for l := range p.errors {
+		errLabels = append(errLabels, l)
+	}
+	sort.Slice(errLabels, func(i, j int) bool {
+		if errLabels[i].requestLabels != errLabels[j].requestLabels {
+			return errLabels[i].requestLabels.less(errLabels[j].requestLabels)
+		}
+		return errLabels[i].name < errLabels[j].name
+	})
+
+	metric = name("errors_total")
+	fmt.Fprintf(out, "# HELP %s Count of failed Yandex.Music API requests.\n", metric)
+	fmt.Fprintf(out, "# TYPE %s counter\n", metric)
+	for _, l := range errLabels {
+		fmt.Fprintf(out, "%s{%s,error=%s} %d\n", metric, l.requestLabels, quoteLabel(l.name), p.errors[l])
+	}
+
+	return out.WriteTo(w)
+}
+
+func (h *histogram) write(w io.Writer, metric, labels string, buckets []float64) {
+	var cumulative uint64
+	for i, bound := range buckets {
+		cumulative += h.counts[i]
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n",
+			metric, labels, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", metric, labels, h.count)
+	fmt.Fprintf(w, "%s_sum{%s} %s\n", metric, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(w, "%s_count{%s} %d\n", metric, labels, h.count)
+}
+
+func (l requestLabels) less(other requestLabels) bool {
+	if l.endpoint != other.endpoint {
+		return l.endpoint < other.endpoint
+	}
+	if l.method != other.method {
+		return l.method < other.method
+	}
+	return l.status < other.status
+}
+
+// String returns labels in Prometheus text format without braces.
+func (l requestLabels) String() string {
+	return "method=" + quoteLabel(l.method) +
+		",endpoint=" + quoteLabel(l.endpoint) +
+		",status=" + quoteLabel(l.status)
+}
+
+// quoteLabel quotes label value escaping backslashes, quotes and newlines.
+func quoteLabel(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+	return `"` + value + `"`
+}
@@ -0,0 +1,178 @@
+package yamusic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// Each request takes 15ms.
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		now = now.Add(15 * time.Millisecond)
+		return now
+	}
+
+	metrics := NewPrometheusMetrics(nil)
+	baseURL, _ := url.Parse(server.URL + "/")
+	metricsClient := NewClient(
+		BaseURL(baseURL),
+		AccessToken(userID, accessToken),
+		Middlewares(MetricsMiddleware(metrics, &MetricsOptions{Now: clock})),
+	)
+
+	genres := `{"invocationInfo": {"req-id": "Genres.List"}}`
+	mux.HandleFunc("/genres", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, genres)
+	})
+	mux.HandleFunc("/users/2000/playlists/3", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"name":"playlist-not-found","message":"Playlist not found"}}`)
+	})
+
+	ctx := context.Background()
+
+	_, _, err := metricsClient.Genres().List(ctx)
+	require.NoError(t, err)
+	_, _, err = metricsClient.Genres().List(ctx)
+	require.NoError(t, err)
+
+	result, _, err := metricsClient.Playlists().Get(ctx, userID, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "playlist-not-found", result.Error.Name)
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	output := rec.Body.String()
+	size := len(genres)
+	for _, line := range []string{
+		`# TYPE yamusic_client_requests_total counter`,
+		`yamusic_client_requests_total{method="GET",endpoint="genres",status="2xx"} 2`,
+		`yamusic_client_requests_total{method="GET",endpoint="users/{uid}/playlists/{kind}",status="4xx"} 1`,
+		`# TYPE yamusic_client_request_duration_seconds histogram`,
+		`yamusic_client_request_duration_seconds_bucket{method="GET",endpoint="genres",status="2xx",le="0.01"} 0`,
+		`yamusic_client_request_duration_seconds_bucket{method="GET",endpoint="genres",status="2xx",le="0.025"} 2`,
+		`yamusic_client_request_duration_seconds_bucket{method="GET",endpoint="genres",status="2xx",le="+Inf"} 2`,
+		`yamusic_client_request_duration_seconds_sum{method="GET",endpoint="genres",status="2xx"} 0.03`,
+		`yamusic_client_request_duration_seconds_count{method="GET",endpoint="genres",status="2xx"} 2`,
+		`yamusic_client_response_size_bytes_bucket{method="GET",endpoint="genres",status="2xx",le="100"} 2`,
+		fmt.Sprintf(`yamusic_client_response_size_bytes_sum{method="GET",endpoint="genres",status="2xx"} %d`, 2*size),
+		`# TYPE yamusic_client_errors_total counter`,
+		`yamusic_client_errors_total{method="GET",endpoint="users/{uid}/playlists/{kind}",status="4xx",error="playlist-not-found"} 1`,
+	} {
+		assert.Contains(t, output, line+"\n")
+	}
+	assert.NotContains(t, output, `errors_total{method="GET",endpoint="genres"`)
+}
+
+func TestMetricsMiddleware_TransportError(t *testing.T) {
+	var recorded []RequestMetrics
+	recorder := recorderFunc(func(m RequestMetrics) {
+		recorded = append(recorded, m)
+	})
+
+	failing := DoerFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, req.Context().Err()
+	})
+	metricsClient := NewClient(
+		HTTPClient(failing),
+		Middlewares(MetricsMiddleware(recorder, &MetricsOptions{
+			Endpoint: func(req *http.Request) string { return "custom" },
+		})),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := metricsClient.Genres().List(ctx)
+	assert.Error(t, err)
+
+	require.Len(t, recorded, 1)
+	assert.Equal(t, "custom", recorded[0].Endpoint)
+	assert.Equal(t, StatusClassError, recorded[0].StatusClass)
+	assert.Equal(t, "canceled", recorded[0].ErrorName)
+}
+
+// recorderFunc is an adapter to use function as MetricsRecorder.
+type recorderFunc func(m RequestMetrics)
+
+func (f recorderFunc) RecordRequest(m RequestMetrics) {
+	f(m)
+}
+
+func TestPrometheusMetrics_Options(t *testing.T) {
+	metrics := NewPrometheusMetrics(&PrometheusOptions{
+		Namespace:       "music",
+		DurationBuckets: []float64{1},
+		SizeBuckets:     []float64{1024},
+	})
+	metrics.RecordRequest(RequestMetrics{
+		Method:       http.MethodPost,
+		Endpoint:     `quo"te`,
+		StatusClass:  "5xx",
+		Duration:     2 * time.Second,
+		ResponseSize: 10,
+	})
+
+	output := new(strings.Builder)
+	_, err := metrics.WriteTo(output)
+	require.NoError(t, err)
+
+	labels := `method="POST",endpoint="quo\"te",status="5xx"`
+	assert.Equal(t, `# HELP music_requests_total Count of Yandex.Music API requests.
+# TYPE music_requests_total counter
+music_requests_total{`+labels+`} 1
+# HELP music_request_duration_seconds Time until Yandex.Music API response headers are received.
+# TYPE music_request_duration_seconds histogram
+music_request_duration_seconds_bucket{`+labels+`,le="1"} 0
+music_request_duration_seconds_bucket{`+labels+`,le="+Inf"} 1
+music_request_duration_seconds_sum{`+labels+`} 2
+music_request_duration_seconds_count{`+labels+`} 1
+# HELP music_response_size_bytes Size of Yandex.Music API response bodies.
+# TYPE music_response_size_bytes histogram
+music_response_size_bytes_bucket{`+labels+`,le="1024"} 1
+music_response_size_bytes_bucket{`+labels+`,le="+Inf"} 1
+music_response_size_bytes_sum{`+labels+`} 10
+music_response_size_bytes_count{`+labels+`} 1
+# HELP music_errors_total Count of failed Yandex.Music API requests.
+# TYPE music_errors_total counter
+music_errors_total{`+labels+`,error=""} 1
+`, output.String())
+}
+
+func TestEndpointTemplate(t *testing.T) {
+	tests := map[string]string{
+		"/genres":                                 "genres",
+		"/users/2000/playlists/list":              "users/{uid}/playlists/list",
+		"/users/2000/playlists/3":                 "users/{uid}/playlists/{kind}",
+		"/users/2000/playlists/3/change-relative": "users/{uid}/playlists/{kind}/change-relative",
+		"/users/2000/likes/tracks":                "users/{uid}/likes/tracks",
+		"/users/2000/likes/albums/add-multiple":   "users/{uid}/likes/{type}/{action}",
+		"/tracks/10994777:1193829/download-info":  "tracks/{id}/download-info",
+		"/queues/5e9b3c9f0e3e2c6b1b0e7d8e":        "queues/{id}",
+		"/download-info/9b0e3f1a/2":               "download-info/{id}/{id}",
+		"/users/2000/settings":                    "users/{id}/settings",
+		"/landing3/new-releases":                  "landing3/new-releases",
+		"/metatags/rock/albums":                   "metatags/{id}/albums",
+		"/queues/queue-5e9b3c9f0e3e2c6b1b0e7d8e":  "queues/{id}",
+		"/feed/wizard/is-passed":                  "feed/wizard/is-passed",
+	}
+
+	for path, template := range tests {
+		assert.Equal(t, template, EndpointTemplate(path), path)
+	}
+}
@@ -34,7 +34,8 @@ type (
 			Prefix string `json:"prefix"`
 			URI    string `json:"uri"`
 		} `json:"cover"`
-		Genres []interface{} `json:"genres"`
+		Genres     []interface{} `json:"genres"`
+		Decomposed []interface{} `json:"decomposed,omitempty"`
 	}
 
 	Artists []Artist
@@ -53,6 +54,8 @@ type (
 		Title                    string        `json:"title"`
 		Type                     string        `json:"type,omitempty"`
 		MetaType                 string        `json:"metaType"`
+		Description              string        `json:"description,omitempty"`
+		ShortDescription         string        `json:"shortDescription,omitempty"`
 		ContentWarning           string        `json:"contentWarning,omitempty"`
 		Year                     int           `json:"year"`
 		ReleaseDate              time.Time     `json:"releaseDate"`
@@ -114,6 +117,9 @@ type (
 		Type             string `json:"type"`
 		RememberPosition bool   `json:"rememberPosition"`
 		TrackSharingFlag string `json:"trackSharingFlag"`
+		// PubDate is publication date of podcast episode.
+		PubDate          time.Time `json:"pubDate"`
+		ShortDescription string    `json:"shortDescription,omitempty"`
 	}
 
 	TrackFull struct {
@@ -121,6 +127,8 @@ type (
 		Timestamp time.Time `json:"timestamp"`
 		Recent    bool      `json:"recent"`
 		Track     Track     `json:"track"`
+		// Chart is set only for tracks of a chart playlist.
+		Chart *ChartPosition `json:"chart,omitempty"`
 	}
 
 	Tracks []TrackFull
@@ -148,6 +156,13 @@ type (
 		} `json:"result"`
 	}
 
+	// PlaylistsGetByIDsResp describes get playlists by ids response
+	PlaylistsGetByIDsResp struct {
+		InvocationInfo InvocationInfo    `json:"invocationInfo"`
+		Error          Error             `json:"error"`
+		Result         []PlaylistsResult `json:"result"`
+	}
+
 	// PlaylistsRenameResp describes method rename playlist response
 	PlaylistsRenameResp struct {
 		InvocationInfo InvocationInfo  `json:"invocationInfo"`
@@ -238,13 +253,32 @@ type (
 	}
 )
 
+// Names joins names of the artists with comma.
+func (a Artists) Names() string {
+	names := make([]string, 0, len(a))
+	for _, artist := range a {
+		names = append(names, artist.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// ArtistNames joins names of the track's artists with comma.
+func (t Track) ArtistNames() string {
+	return t.Artists.Names()
+}
+
 // List returns playlists of the user
 func (s *PlaylistsService) List(
 	ctx context.Context,
 	userID int,
+	reqOpts ...RequestOption,
 ) (*PlaylistsListResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "PlaylistsService.List", reqOpts)
+	defer call.end()
+
 	if userID == 0 {
-		userID = s.client.userID
+		userID = s.client.userIDFrom(ctx)
 	}
 
 	uri := fmt.Sprintf("users/%v/playlists/list", userID)
@@ -263,9 +297,13 @@ func (s *PlaylistsService) Get(
 	ctx context.Context,
 	userID int,
 	kind int,
+	reqOpts ...RequestOption,
 ) (*PlaylistsGetResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "PlaylistsService.Get", reqOpts)
+	defer call.end()
+
 	if userID == 0 {
-		userID = s.client.userID
+		userID = s.client.userIDFrom(ctx)
 	}
 
 	uri := fmt.Sprintf("users/%v/playlists/%v", userID, kind)
@@ -283,9 +321,13 @@ func (s *PlaylistsService) GetByUserIDAndKind(
 	ctx context.Context,
 	userID string,
 	kind int,
+	reqOpts ...RequestOption,
 ) (*PlaylistsGetResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "PlaylistsService.GetByUserIDAndKind", reqOpts)
+	defer call.end()
+
 	if len(userID) == 0 {
-		userID = strconv.Itoa(s.client.userID)
+		userID = strconv.Itoa(s.client.userIDFrom(ctx))
 	}
 
 	uri := fmt.Sprintf("users/%v/playlists/%v", userID, kind)
@@ -313,9 +355,13 @@ func (s *PlaylistsService) GetByKinds(
 	ctx context.Context,
 	userID int,
 	opts *PlaylistsGetByKindOptions,
+	reqOpts ...RequestOption,
 ) (*PlaylistsGetByKindsResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "PlaylistsService.GetByKinds", reqOpts)
+	defer call.end()
+
 	if userID == 0 {
-		userID = s.client.userID
+		userID = s.client.userIDFrom(ctx)
 	}
 
 	if opts == nil {
@@ -344,13 +390,57 @@ func (s *PlaylistsService) GetByKinds(
 	return playlists, resp, err
 }
 
+type (
+	// PlaylistID identifies playlist of any user by owner's uid and kind
+	PlaylistID struct {
+		UID  int `json:"uid"`
+		Kind int `json:"kind"`
+	}
+)
+
+// String returns playlist id in "uid:kind" form used by the API
+func (id PlaylistID) String() string {
+	return fmt.Sprintf("%v:%v", id.UID, id.Kind)
+}
+
+// GetByIDs returns several playlists of any users in one request
+func (s *PlaylistsService) GetByIDs(
+	ctx context.Context,
+	ids []PlaylistID,
+	reqOpts ...RequestOption,
+) (*PlaylistsGetByIDsResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "PlaylistsService.GetByIDs", reqOpts)
+	defer call.end()
+
+	stringIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		stringIDs = append(stringIDs, id.String())
+	}
+
+	form := url.Values{}
+	form.Set("playlistIds", strings.Join(stringIDs, ","))
+
+	req, err := s.client.NewRequest(http.MethodPost, "playlists/list", form)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	playlists := new(PlaylistsGetByIDsResp)
+	resp, err := s.client.Do(ctx, req, playlists)
+	return playlists, resp, err
+}
+
 // Rename renames playlist of current user
 func (s *PlaylistsService) Rename(
 	ctx context.Context,
 	kind int,
 	newName string,
+	reqOpts ...RequestOption,
 ) (*PlaylistsRenameResp, *http.Response, error) {
-	uri := fmt.Sprintf("users/%v/playlists/%v/name", s.client.userID, kind)
+	ctx, call := s.client.startCall(ctx, "PlaylistsService.Rename", reqOpts)
+	defer call.end()
+
+	uri := fmt.Sprintf("users/%v/playlists/%v/name", s.client.userIDFrom(ctx), kind)
 
 	form := url.Values{}
 	form.Set("value", newName)
@@ -372,7 +462,11 @@ func (s *PlaylistsService) Create(
 	ctx context.Context,
 	title string,
 	isPublic bool,
+	reqOpts ...RequestOption,
 ) (*PlaylistsCreateResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "PlaylistsService.Create", reqOpts)
+	defer call.end()
+
 	var visibility string
 	if isPublic {
 		visibility = "public"
@@ -384,7 +478,7 @@ func (s *PlaylistsService) Create(
 	form.Set("title", title)
 	form.Set("visibility", visibility)
 
-	uri := fmt.Sprintf("users/%v/playlists/create", s.client.userID)
+	uri := fmt.Sprintf("users/%v/playlists/create", s.client.userIDFrom(ctx))
 
 	req, err := s.client.NewRequest(http.MethodPost, uri, form)
 	if err != nil {
@@ -400,8 +494,12 @@ func (s *PlaylistsService) Create(
 func (s *PlaylistsService) Delete(
 	ctx context.Context,
 	kind int,
+	reqOpts ...RequestOption,
 ) (*PlaylistsDeleteResp, *http.Response, error) {
-	uri := fmt.Sprintf("users/%v/playlists/%v/delete", s.client.userID, kind)
+	ctx, call := s.client.startCall(ctx, "PlaylistsService.Delete", reqOpts)
+	defer call.end()
+
+	uri := fmt.Sprintf("users/%v/playlists/%v/delete", s.client.userIDFrom(ctx), kind)
 	req, err := s.client.NewRequest(http.MethodPost, uri, nil)
 	if err != nil {
 		return nil, nil, err
@@ -425,6 +523,14 @@ type (
 	}
 )
 
+// String returns track id in "trackID:albumID" form used by the API
+func (t PlaylistsTrack) String() string {
+	if t.AlbumID == 0 {
+		return strconv.Itoa(t.ID)
+	}
+	return fmt.Sprintf("%v:%v", t.ID, t.AlbumID)
+}
+
 // AddTracks adds tracks to playlist
 func (s *PlaylistsService) AddTracks(
 	ctx context.Context,
@@ -432,7 +538,11 @@ func (s *PlaylistsService) AddTracks(
 	revision int,
 	tracks []PlaylistsTrack,
 	opts *PlaylistsAddTracksOptions,
+	reqOpts ...RequestOption,
 ) (*PlaylistsAddTracksResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "PlaylistsService.AddTracks", reqOpts)
+	defer call.end()
+
 	if opts == nil {
 		opts = &PlaylistsAddTracksOptions{
 			At: 0,
@@ -462,7 +572,7 @@ func (s *PlaylistsService) AddTracks(
 
 	uri := fmt.Sprintf(
 		"users/%v/playlists/%v/change-relative",
-		s.client.userID,
+		s.client.userIDFrom(ctx),
 		kind,
 	)
 
@@ -491,7 +601,11 @@ func (s *PlaylistsService) RemoveTracks(
 	revision int,
 	tracks []PlaylistsTrack,
 	opts *PlaylistsRemoveTracksOptions,
+	reqOpts ...RequestOption,
 ) (*PlaylistsRemoveTracksResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "PlaylistsService.RemoveTracks", reqOpts)
+	defer call.end()
+
 	if opts == nil {
 		opts = &PlaylistsRemoveTracksOptions{
 			From: 0,
@@ -524,7 +638,7 @@ func (s *PlaylistsService) RemoveTracks(
 
 	uri := fmt.Sprintf(
 		"users/%v/playlists/%v/change-relative",
-		s.client.userID,
+		s.client.userIDFrom(ctx),
 		kind,
 	)
 
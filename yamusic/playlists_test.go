@@ -114,6 +114,35 @@ func TestPlaylistsService_GetByKinds(t *testing.T) {
 	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
 }
 
+func TestPlaylistsService_GetByIDs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &PlaylistsGetByIDsResp{}
+	want.InvocationInfo.ReqID = "Playlists.GetByIDs"
+
+	mux.HandleFunc("/playlists/list", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		err := r.ParseForm()
+		assert.NoError(t, err)
+		assert.Equal(t, "1:2,3:4", r.FormValue("playlistIds"))
+		assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	result, _, err := client.Playlists().GetByIDs(
+		context.Background(),
+		[]PlaylistID{{UID: 1, Kind: 2}, {UID: 3, Kind: 4}},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
+}
+
 func TestPlaylistsService_Rename(t *testing.T) {
 	setup()
 	defer teardown()
@@ -321,3 +350,9 @@ func TestPlaylistsService_RemoveTracks(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
 }
+
+func TestTrack_ArtistNames(t *testing.T) {
+	track := Track{Artists: Artists{{Name: "Queen"}, {Name: "David Bowie"}}}
+	assert.Equal(t, "Queen, David Bowie", track.ArtistNames())
+	assert.Empty(t, Track{}.ArtistNames())
+}
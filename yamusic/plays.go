@@ -0,0 +1,207 @@
+package yamusic
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type (
+	// PlayAudio describes a single playback of a track reported by
+	// TracksService.ReportPlay.
+	PlayAudio struct {
+		// TrackID is id of the track.
+		TrackID string `json:"trackId"`
+		// AlbumID is id of the album the track was played from.
+		AlbumID int `json:"albumId,omitempty"`
+		// PlaylistID is id of the playlist in "uid:kind" form if the track
+		// was played from a playlist.
+		PlaylistID string `json:"playlistId,omitempty"`
+		// From is a source of the playback like "desktop_win-home-playlist_of_the_day".
+		From string `json:"from"`
+		// FromCache is true if the track was played from local cache.
+		FromCache bool `json:"fromCache,omitempty"`
+		// PlayID is unique id of the playback. Generated if empty.
+		PlayID             string  `json:"playId"`
+		TrackLengthSeconds float64 `json:"trackLengthSeconds"`
+		TotalPlayedSeconds float64 `json:"totalPlayedSeconds"`
+		EndPositionSeconds float64 `json:"endPositionSeconds"`
+		// Timestamp is time when the playback happened. Now if zero.
+		Timestamp time.Time `json:"timestamp"`
+	}
+
+	// PlayAudioResp describes play audio report response
+	PlayAudioResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         string         `json:"result"`
+	}
+)
+
+// NewPlayID returns random UUID v4 to be used as PlayAudio's PlayID.
+func NewPlayID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand never fails on supported platforms.
+		panic(err)
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// withDefaults returns copy of the play with generated play id and timestamp.
+func (p PlayAudio) withDefaults() PlayAudio {
+	if p.PlayID == "" {
+		p.PlayID = NewPlayID()
+	}
+
+	if p.Timestamp.IsZero() {
+		p.Timestamp = time.Now()
+	}
+
+	return p
+}
+
+// form encodes the play the way play-audio method expects it.
+func (p PlayAudio) form(userID int, now time.Time) url.Values {
+	const timeLayout = "2006-01-02T15:04:05.000Z"
+
+	formatSeconds := func(seconds float64) string {
+		return strconv.FormatFloat(seconds, 'f', -1, 64)
+	}
+
+	form := url.Values{}
+	form.Set("track-id", p.TrackID)
+	form.Set("from-cache", strconv.FormatBool(p.FromCache))
+	form.Set("from", p.From)
+	form.Set("play-id", p.PlayID)
+	form.Set("uid", strconv.Itoa(userID))
+	form.Set("timestamp", p.Timestamp.UTC().Format(timeLayout))
+	form.Set("track-length-seconds", formatSeconds(p.TrackLengthSeconds))
+	form.Set("total-played-seconds", formatSeconds(p.TotalPlayedSeconds))
+	form.Set("end-position-seconds", formatSeconds(p.EndPositionSeconds))
+	if p.AlbumID != 0 {
+		form.Set("album-id", strconv.Itoa(p.AlbumID))
+	}
+	if p.PlaylistID != "" {
+		form.Set("playlist-id", p.PlaylistID)
+	}
+	form.Set("client-now", now.UTC().Format(timeLayout))
+
+	return form
+}
+
+// ReportPlay reports playback of a track so it counts as a listen
+// and affects recommendations
+func (t *TracksService) ReportPlay(ctx context.Context, play PlayAudio, reqOpts ...RequestOption) (*PlayAudioResp, *http.Response, error) {
+	ctx, call := t.client.startCall(ctx, "TracksService.ReportPlay", reqOpts)
+	defer call.end()
+
+	play = play.withDefaults()
+
+	req, err := t.client.NewRequest(
+		http.MethodPost,
+		"play-audio",
+		play.form(t.client.userIDFrom(ctx), time.Now()),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	playResp := new(PlayAudioResp)
+	resp, err := t.client.Do(ctx, req, playResp)
+	return playResp, resp, err
+}
+
+// PlayReporter collects plays while offline and reports them later.
+// It is safe for concurrent use.
+type PlayReporter struct {
+	tracks *TracksService
+
+	// flushMu serializes flushes, so that a play isn't reported twice.
+	flushMu sync.Mutex
+
+	mu      sync.Mutex
+	pending []PlayAudio
+}
+
+// NewPlayReporter returns reporter which sends plays with tracks service.
+func NewPlayReporter(tracks *TracksService) *PlayReporter {
+	return &PlayReporter{tracks: tracks}
+}
+
+// Add queues plays. PlayID and Timestamp are filled at the moment of adding
+// if they are empty, so the report keeps actual time of the playback.
+func (r *PlayReporter) Add(plays ...PlayAudio) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, play := range plays {
+		r.pending = append(r.pending, play.withDefaults())
+	}
+}
+
+// Pending returns copy of queued plays, e.g. to persist them between runs.
+func (r *PlayReporter) Pending() []PlayAudio {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]PlayAudio(nil), r.pending...)
+}
+
+// Flush reports all queued plays in order. It stops on the first failure
+// and keeps the failed play and the rest of them in the queue. Plays which
+// the API rejects (4xx status except auth and rate limit ones) are dropped,
+// since reporting them again can't succeed, and the error of the first one
+// is returned after the rest are reported.
+func (r *PlayReporter) Flush(ctx context.Context) error {
+	r.flushMu.Lock()
+	defer r.flushMu.Unlock()
+
+	var rejectErr error
+	for {
+		r.mu.Lock()
+		if len(r.pending) == 0 {
+			r.mu.Unlock()
+			return rejectErr
+		}
+		play := r.pending[0]
+		r.mu.Unlock()
+
+		playResp, resp, err := r.tracks.ReportPlay(ctx, play)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			err := fmt.Errorf("report play %v: %v %v", play.PlayID, resp.Status, playResp.Error.Message)
+			if !playRejected(resp.StatusCode) {
+				return err
+			}
+			if rejectErr == nil {
+				rejectErr = err
+			}
+		}
+
+		r.mu.Lock()
+		r.pending = r.pending[1:]
+		r.mu.Unlock()
+	}
+}
+
+// playRejected reports whether the status means that the play can't be
+// reported by retrying.
+func playRejected(statusCode int) bool {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError
+}
@@ -0,0 +1,161 @@
+package yamusic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPlayID(t *testing.T) {
+	id := NewPlayID()
+	assert.Regexp(
+		t,
+		regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+		id,
+	)
+	assert.NotEqual(t, id, NewPlayID())
+}
+
+func TestTracksService_ReportPlay(t *testing.T) {
+	setup()
+	defer teardown()
+
+	play := PlayAudio{
+		TrackID:            "42",
+		AlbumID:            7,
+		PlaylistID:         "2000:3",
+		From:               "test",
+		PlayID:             "play-id",
+		TrackLengthSeconds: 180,
+		TotalPlayedSeconds: 90.5,
+		EndPositionSeconds: 90.5,
+		Timestamp:          time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	mux.HandleFunc("/play-audio", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "42", r.FormValue("track-id"))
+		assert.Equal(t, "7", r.FormValue("album-id"))
+		assert.Equal(t, "2000:3", r.FormValue("playlist-id"))
+		assert.Equal(t, "test", r.FormValue("from"))
+		assert.Equal(t, "false", r.FormValue("from-cache"))
+		assert.Equal(t, "play-id", r.FormValue("play-id"))
+		assert.Equal(t, fmt.Sprint(userID), r.FormValue("uid"))
+		assert.Equal(t, "2020-01-02T03:04:05.000Z", r.FormValue("timestamp"))
+		assert.Equal(t, "180", r.FormValue("track-length-seconds"))
+		assert.Equal(t, "90.5", r.FormValue("total-played-seconds"))
+		assert.Equal(t, "90.5", r.FormValue("end-position-seconds"))
+		assert.NotEmpty(t, r.FormValue("client-now"))
+		fmt.Fprint(w, `{"invocationInfo": {"req-id": "Tracks.ReportPlay"}, "result": "ok"}`)
+	})
+
+	result, _, err := client.Tracks().ReportPlay(context.Background(), play)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", result.Result)
+}
+
+func TestPlayReporter_Flush(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var reported []string
+	fail := true
+	mux.HandleFunc("/play-audio", func(w http.ResponseWriter, r *http.Request) {
+		trackID := r.FormValue("track-id")
+		if trackID == "2" && fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error": {"name": "internal", "message": "boom"}}`)
+			return
+		}
+		assert.NotEmpty(t, r.FormValue("play-id"))
+		reported = append(reported, trackID)
+		fmt.Fprint(w, `{"result": "ok"}`)
+	})
+
+	reporter := NewPlayReporter(client.Tracks())
+	reporter.Add(PlayAudio{TrackID: "1"}, PlayAudio{TrackID: "2"}, PlayAudio{TrackID: "3"})
+
+	pending := reporter.Pending()
+	assert.Len(t, pending, 3)
+	assert.NotEmpty(t, pending[0].PlayID)
+	assert.False(t, pending[0].Timestamp.IsZero())
+
+	err := reporter.Flush(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, []string{"1"}, reported)
+	assert.Len(t, reporter.Pending(), 2)
+
+	fail = false
+	assert.NoError(t, reporter.Flush(context.Background()))
+	assert.Equal(t, []string{"1", "2", "3"}, reported)
+	assert.Empty(t, reporter.Pending())
+}
+
+func TestPlayReporter_FlushRejected(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var reported []string
+	mux.HandleFunc("/play-audio", func(w http.ResponseWriter, r *http.Request) {
+		trackID := r.FormValue("track-id")
+		if trackID == "2" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error": {"name": "validate", "message": "bad play"}}`)
+			return
+		}
+		reported = append(reported, trackID)
+		fmt.Fprint(w, `{"result": "ok"}`)
+	})
+
+	reporter := NewPlayReporter(client.Tracks())
+	reporter.Add(PlayAudio{TrackID: "1"}, PlayAudio{TrackID: "2"}, PlayAudio{TrackID: "3"})
+
+	// Rejected play doesn't block the rest of them.
+	err := reporter.Flush(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "bad play")
+	assert.Equal(t, []string{"1", "3"}, reported)
+	assert.Empty(t, reporter.Pending())
+}
+
+func TestPlayReporter_FlushConcurrent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var (
+		mu       sync.Mutex
+		reported []string
+	)
+	mux.HandleFunc("/play-audio", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		reported = append(reported, r.FormValue("track-id"))
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		fmt.Fprint(w, `{"result": "ok"}`)
+	})
+
+	reporter := NewPlayReporter(client.Tracks())
+	reporter.Add(PlayAudio{TrackID: "1"}, PlayAudio{TrackID: "2"}, PlayAudio{TrackID: "3"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, reporter.Flush(context.Background()))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, []string{"1", "2", "3"}, reported)
+	assert.Empty(t, reporter.Pending())
+}
@@ -0,0 +1,245 @@
+package yamusic
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Album meta types and track types of podcasts and audiobooks.
+const (
+	AlbumMetaTypePodcast    = "podcast"
+	AlbumMetaTypeAudiobook  = "audiobook"
+	TrackTypePodcastEpisode = "podcast-episode"
+)
+
+// IsPodcast reports whether the album is a podcast.
+func (a Album) IsPodcast() bool {
+	return a.MetaType == AlbumMetaTypePodcast || a.Type == AlbumMetaTypePodcast
+}
+
+// IsPodcastEpisode reports whether the track is an episode of a podcast.
+func (t Track) IsPodcastEpisode() bool {
+	return t.Type == TrackTypePodcastEpisode
+}
+
+// CoverURL returns absolute URL of a cover from its URI (like coverUri
+// field of albums and tracks) and size like "400x400".
+func CoverURL(uri, size string) string {
+	if uri == "" {
+		return ""
+	}
+
+	return "https://" + strings.Replace(uri, "%%", size, 1)
+}
+
+// PodcastEpisodes returns episodes of a podcast album ordered from the newest
+// to the oldest.
+func (s *AlbumsService) PodcastEpisodes(
+	ctx context.Context,
+	albumID int,
+	reqOpts ...RequestOption,
+) ([]Track, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "AlbumsService.PodcastEpisodes", reqOpts)
+	defer call.end()
+
+	album, resp, err := s.GetWithTracks(ctx, albumID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return album.Result.Episodes(), resp, nil
+}
+
+// Episodes returns all tracks of the album ordered by publication date from
+// the newest to the oldest.
+func (a *AlbumWithTracks) Episodes() []Track {
+	var episodes []Track
+	for _, volume := range a.Volumes {
+		episodes = append(episodes, volume...)
+	}
+
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return episodes[i].PubDate.After(episodes[j].PubDate)
+	})
+
+	return episodes
+}
+
+type (
+	// PodcastRSSOptions are options for WritePodcastRSS
+	PodcastRSSOptions struct {
+		// Link is link to the podcast's page. Defaults to the album page
+		// on music.yandex.ru.
+		Link string
+		// Language of the podcast like "ru".
+		Language string
+		// EnclosureURL returns URL of the episode's audio file. Yandex.Music
+		// download URLs expire, so it should point to a service which
+		// resolves them on demand. Defaults to the track page URL.
+		EnclosureURL func(album Album, episode Track) string
+		// EnclosureType is MIME type of enclosures. Defaults to "audio/mpeg".
+		EnclosureType string
+	}
+
+	podcastRSS struct {
+		XMLName xml.Name          `xml:"rss"`
+		Version string            `xml:"version,attr"`
+		ITunes  string            `xml:"xmlns:itunes,attr"`
+		Channel podcastRSSChannel `xml:"channel"`
+	}
+
+	podcastRSSChannel struct {
+		Title       string               `xml:"title"`
+		Link        string               `xml:"link"`
+		Description string               `xml:"description"`
+		Language    string               `xml:"language,omitempty"`
+		Author      string               `xml:"itunes:author,omitempty"`
+		Image       *podcastRSSImage     `xml:"itunes:image,omitempty"`
+		Explicit    string               `xml:"itunes:explicit,omitempty"`
+		Categories  []podcastRSSCategory `xml:"itunes:category,omitempty"`
+		Items       []podcastRSSItem     `xml:"item"`
+	}
+
+	podcastRSSImage struct {
+		Href string `xml:"href,attr"`
+	}
+
+	podcastRSSCategory struct {
+		Text string `xml:"text,attr"`
+	}
+
+	podcastRSSItem struct {
+		Title       string              `xml:"title"`
+		Description string              `xml:"description,omitempty"`
+		GUID        podcastRSSGUID      `xml:"guid"`
+		PubDate     string              `xml:"pubDate,omitempty"`
+		Enclosure   podcastRSSEnclosure `xml:"enclosure"`
+		Duration    string              `xml:"itunes:duration,omitempty"`
+		Image       *podcastRSSImage    `xml:"itunes:image,omitempty"`
+	}
+
+	podcastRSSGUID struct {
+		IsPermaLink bool   `xml:"isPermaLink,attr"`
+		Value       string `xml:",chardata"`
+	}
+
+	podcastRSSEnclosure struct {
+		URL    string `xml:"url,attr"`
+		Length int    `xml:"length,attr"`
+		Type   string `xml:"type,attr"`
+	}
+)
+
+// WritePodcastRSS writes RSS 2.0 feed of the podcast album to w so it can be
+// subscribed to in a podcast player.
+func WritePodcastRSS(w io.Writer, podcast *AlbumWithTracks, opts *PodcastRSSOptions) error {
+	if podcast == nil {
+		return fmt.Errorf("yamusic: nil podcast")
+	}
+
+	if opts == nil {
+		opts = &PodcastRSSOptions{}
+	}
+
+	link := opts.Link
+	if link == "" {
+		link = fmt.Sprintf("https://music.yandex.ru/album/%v", podcast.ID)
+	}
+
+	enclosureURL := opts.EnclosureURL
+	if enclosureURL == nil {
+		enclosureURL = func(album Album, episode Track) string {
+			return fmt.Sprintf("https://music.yandex.ru/album/%v/track/%v", album.ID, episode.ID)
+		}
+	}
+
+	enclosureType := opts.EnclosureType
+	if enclosureType == "" {
+		enclosureType = "audio/mpeg"
+	}
+
+	description := podcast.Description
+	if description == "" {
+		description = podcast.ShortDescription
+	}
+
+	channel := podcastRSSChannel{
+		Title:       podcast.Title,
+		Link:        link,
+		Description: description,
+		Language:    opts.Language,
+		Author:      podcast.Artists.Names(),
+		Explicit:    "false",
+	}
+
+	if podcast.ContentWarning == "explicit" {
+		channel.Explicit = "true"
+	}
+
+	if cover := CoverURL(podcast.CoverURI, "1000x1000"); cover != "" {
+		channel.Image = &podcastRSSImage{Href: cover}
+	}
+
+	if podcast.Genre != "" {
+		channel.Categories = append(channel.Categories, podcastRSSCategory{Text: podcast.Genre})
+	}
+
+	for _, episode := range podcast.Episodes() {
+		item := podcastRSSItem{
+			Title:       episode.Title,
+			Description: episode.ShortDescription,
+			GUID:        podcastRSSGUID{Value: episode.ID},
+			Enclosure: podcastRSSEnclosure{
+				URL:    enclosureURL(podcast.Album, episode),
+				Length: episode.FileSize,
+				Type:   enclosureType,
+			},
+			Duration: formatRSSDuration(episode.DurationMs),
+		}
+
+		if !episode.PubDate.IsZero() {
+			item.PubDate = episode.PubDate.Format(time.RFC1123Z)
+		}
+
+		if cover := CoverURL(episode.CoverURI, "1000x1000"); cover != "" {
+			item.Image = &podcastRSSImage{Href: cover}
+		}
+
+		channel.Items = append(channel.Items, item)
+	}
+
+	rss := podcastRSS{
+		Version: "2.0",
+		ITunes:  "http://www.itunes.com/dtds/podcast-1.0.dtd",
+		Channel: channel,
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(rss); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// formatRSSDuration formats duration as HH:MM:SS.
+func formatRSSDuration(durationMs int) string {
+	if durationMs <= 0 {
+		return ""
+	}
+
+	seconds := durationMs / 1000
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
@@ -0,0 +1,107 @@
+package yamusic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPodcast() *AlbumWithTracks {
+	podcast := &AlbumWithTracks{}
+	podcast.ID = 42
+	podcast.Title = "Podcast"
+	podcast.MetaType = AlbumMetaTypePodcast
+	podcast.Description = "About everything"
+	podcast.CoverURI = "avatars.yandex.net/get-music-content/1/%%"
+	podcast.Artists = Artists{{Name: "Host"}}
+	podcast.Volumes = [][]Track{{
+		{
+			ID:         "1",
+			Title:      "Old",
+			Type:       TrackTypePodcastEpisode,
+			DurationMs: 3723000,
+			PubDate:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:      "2",
+			Title:   "New",
+			Type:    TrackTypePodcastEpisode,
+			PubDate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}}
+
+	return podcast
+}
+
+func TestAlbumsService_PodcastEpisodes(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &AlbumsGetWithTracksResp{Result: *testPodcast()}
+
+	mux.HandleFunc("/albums/42/with-tracks", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	episodes, _, err := client.Albums().PodcastEpisodes(context.Background(), 42)
+
+	assert.NoError(t, err)
+	if assert.Len(t, episodes, 2) {
+		assert.Equal(t, "2", episodes[0].ID)
+		assert.Equal(t, "1", episodes[1].ID)
+		assert.True(t, episodes[0].IsPodcastEpisode())
+	}
+}
+
+func TestWritePodcastRSS(t *testing.T) {
+	podcast := testPodcast()
+	assert.True(t, podcast.IsPodcast())
+
+	var buf bytes.Buffer
+	err := WritePodcastRSS(&buf, podcast, &PodcastRSSOptions{
+		Language: "ru",
+		EnclosureURL: func(album Album, episode Track) string {
+			return fmt.Sprintf("http://localhost/track/%v", episode.ID)
+		},
+	})
+	assert.NoError(t, err)
+
+	var rss struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title    string `xml:"title"`
+			Language string `xml:"language"`
+			Items    []struct {
+				Title     string `xml:"title"`
+				GUID      string `xml:"guid"`
+				PubDate   string `xml:"pubDate"`
+				Duration  string `xml:"duration"`
+				Enclosure struct {
+					URL string `xml:"url,attr"`
+				} `xml:"enclosure"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &rss))
+
+	assert.Equal(t, "2.0", rss.Version)
+	assert.Equal(t, "Podcast", rss.Channel.Title)
+	assert.Equal(t, "ru", rss.Channel.Language)
+	if assert.Len(t, rss.Channel.Items, 2) {
+		assert.Equal(t, "New", rss.Channel.Items[0].Title)
+		assert.Equal(t, "http://localhost/track/2", rss.Channel.Items[0].Enclosure.URL)
+		assert.Equal(t, "Wed, 01 Jan 2020 00:00:00 +0000", rss.Channel.Items[1].PubDate)
+		assert.Equal(t, "01:02:03", rss.Channel.Items[1].Duration)
+	}
+	assert.Contains(t, buf.String(), `<itunes:image href="https://avatars.yandex.net/get-music-content/1/1000x1000">`)
+}
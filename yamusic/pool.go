@@ -0,0 +1,160 @@
+package yamusic
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultPoolIdleTimeout is how long Pool keeps clients which aren't used
+// if PoolOptions.IdleTimeout is zero.
+const DefaultPoolIdleTimeout = 10 * time.Minute
+
+type (
+	// PoolOptions are options for Pool.
+	PoolOptions struct {
+		// ClientOptions are applied once to build the part shared by all
+		// clients of the pool: HTTP client, base URL, middlewares like
+		// CacheMiddleware and RateLimitMiddleware, tracer. AccessToken
+		// option is ignored. Device option sets device info of all
+		// clients except device_id and uuid, which are generated per user.
+		ClientOptions []func(*Client)
+		// IdleTimeout is how long clients which aren't used are kept.
+		// Defaults to DefaultPoolIdleTimeout.
+		IdleTimeout time.Duration
+		// Now returns current time. Defaults to time.Now.
+		Now func() time.Time
+	}
+
+	// Pool keeps clients of many users which share HTTP client and
+	// middlewares. Clients are created on demand and evicted when they
+	// aren't used for the idle timeout. It is safe for concurrent use.
+	Pool struct {
+		template    *Client
+		idleTimeout time.Duration
+		now         func() time.Time
+
+		mu        sync.Mutex
+		sessions  map[int]*poolSession
+		lastSweep time.Time
+	}
+
+	poolSession struct {
+		client      *Client
+		accessToken string
+		lastUsed    time.Time
+	}
+)
+
+// NewPool returns empty pool of clients.
+func NewPool(opts *PoolOptions) *Pool {
+	if opts == nil {
+		opts = &PoolOptions{}
+	}
+
+	o := *opts
+	if o.IdleTimeout <= 0 {
+		o.IdleTimeout = DefaultPoolIdleTimeout
+	}
+	if o.Now == nil {
+		o.Now = time.Now
+	}
+
+	template := NewClient(o.ClientOptions...)
+	template.accessToken = ""
+	template.SetUserID(0)
+
+	return &Pool{
+		template:    template,
+		idleTimeout: o.IdleTimeout,
+		now:         o.Now,
+		sessions:    make(map[int]*poolSession),
+		lastSweep:   o.Now(),
+	}
+}
+
+// Client returns client of the user. The same client is returned until
+// it's evicted or the access token changes.
+func (p *Pool) Client(userID int, accessToken string) *Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if now.Sub(p.lastSweep) >= p.idleTimeout {
+		p.evictLocked(now)
+	}
+
+	session, ok := p.sessions[userID]
+	if !ok || session.accessToken != accessToken {
+		session = &poolSession{
+			client:      p.newClient(userID, accessToken),
+			accessToken: accessToken,
+		}
+		p.sessions[userID] = session
+	}
+	session.lastUsed = now
+
+	return session.client
+}
+
+// newClient returns client of the user sharing everything but
+// credentials and device with the pool's template. Each user gets own
+// device_id and uuid, so that their queues aren't mixed up.
+func (p *Pool) newClient(userID int, accessToken string) *Client {
+	device := p.template.device
+	fresh := defaultDeviceInfo()
+	device.DeviceID = fresh.DeviceID
+	device.UUID = fresh.UUID
+
+	c := &Client{
+		client:      p.template.client,
+		baseURL:     p.template.baseURL,
+		accessToken: accessToken,
+		device:      device,
+		middlewares: p.template.middlewares,
+		tracer:      p.template.tracer,
+		Debug:       p.template.Debug,
+	}
+	c.SetUserID(userID)
+	c.initServices()
+
+	return c
+}
+
+// Remove removes client of the user from the pool.
+func (p *Pool) Remove(userID int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.sessions, userID)
+}
+
+// EvictIdle removes clients which weren't used for the idle timeout and
+// returns count of removed ones. Pool also evicts them on its own when
+// clients are requested, so it's only needed to free memory sooner.
+func (p *Pool) EvictIdle() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.evictLocked(p.now())
+}
+
+func (p *Pool) evictLocked(now time.Time) int {
+	evicted := 0
+	for userID, session := range p.sessions {
+		if now.Sub(session.lastUsed) >= p.idleTimeout {
+			delete(p.sessions, userID)
+			evicted++
+		}
+	}
+	p.lastSweep = now
+
+	return evicted
+}
+
+// Len returns count of clients in the pool.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.sessions)
+}
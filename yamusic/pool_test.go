@@ -0,0 +1,125 @@
+package yamusic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for _, user := range []int{1, 2} {
+		user := user
+		mux.HandleFunc(fmt.Sprintf("/users/%d/playlists/list", user), func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, fmt.Sprintf("OAuth token%d", user), r.Header.Get("Authorization"))
+			fmt.Fprint(w, `{"result":[]}`)
+		})
+	}
+
+	// Middlewares are built once and shared by all clients.
+	var (
+		mu       sync.Mutex
+		built    int
+		requests int
+	)
+	counting := func(next Doer) Doer {
+		built++
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			requests++
+			mu.Unlock()
+			return next.Do(req)
+		})
+	}
+
+	baseURL, _ := url.Parse(server.URL + "/")
+	pool := NewPool(&PoolOptions{
+		ClientOptions: []func(*Client){
+			BaseURL(baseURL),
+			AccessToken(userID, accessToken),
+			Middlewares(counting),
+			Device(DeviceInfo{OS: "test", Model: "test"}),
+		},
+	})
+
+	first := pool.Client(1, "token1")
+	second := pool.Client(2, "token2")
+	assert.Equal(t, 1, first.UserID())
+	assert.Equal(t, 2, second.UserID())
+	assert.True(t, first == pool.Client(1, "token1"))
+	assert.Equal(t, 2, pool.Len())
+
+	_, _, err := first.Playlists().List(context.Background(), 0)
+	require.NoError(t, err)
+	_, _, err = second.Playlists().List(context.Background(), 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, built)
+	assert.Equal(t, 2, requests)
+
+	// Each user has own device.
+	assert.Equal(t, "test", first.device.Model)
+	assert.Equal(t, "test", second.device.Model)
+	assert.NotEmpty(t, first.device.DeviceID)
+	assert.NotEqual(t, first.device.DeviceID, second.device.DeviceID)
+	assert.NotEqual(t, first.device.UUID, second.device.UUID)
+
+	// New token replaces the client.
+	assert.True(t, first != pool.Client(1, "newToken"))
+	assert.Equal(t, 2, pool.Len())
+
+	pool.Remove(2)
+	assert.Equal(t, 1, pool.Len())
+}
+
+func TestPool_EvictIdle(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	pool := NewPool(&PoolOptions{
+		IdleTimeout: time.Minute,
+		Now:         func() time.Time { return now },
+	})
+
+	pool.Client(1, "token1")
+	pool.Client(2, "token2")
+
+	now = now.Add(30 * time.Second)
+	pool.Client(2, "token2")
+
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, 1, pool.EvictIdle())
+	assert.Equal(t, 1, pool.Len())
+
+	// Idle clients are also evicted when clients are requested.
+	now = now.Add(time.Minute)
+	pool.Client(3, "token3")
+	assert.Equal(t, 1, pool.Len())
+}
+
+func TestPool_Concurrent(t *testing.T) {
+	pool := NewPool(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			c := pool.Client(i%3, "token")
+			c.SetUserID(i)
+			_ = c.UserID()
+			_ = c.Playlists()
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 3, pool.Len())
+}
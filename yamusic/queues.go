@@ -0,0 +1,245 @@
+package yamusic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Queue context types.
+const (
+	QueueContextPlaylist = "playlist"
+	QueueContextAlbum    = "album"
+	QueueContextArtist   = "artist"
+	QueueContextRadio    = "radio"
+	QueueContextVarious  = "various"
+)
+
+type (
+	// QueuesService is a service to deal with play queues which are shared
+	// between user's devices.
+	QueuesService struct {
+		client *Client
+	}
+
+	// DeviceInfo describes device which reads and writes play queues.
+	// It is sent in X-Yandex-Music-Device header.
+	DeviceInfo struct {
+		OS           string
+		OSVersion    string
+		Manufacturer string
+		Model        string
+		Clid         string
+		DeviceID     string
+		UUID         string
+	}
+
+	// QueueContext describes where the queue was started from.
+	QueueContext struct {
+		Type        string `json:"type"`
+		ID          string `json:"id,omitempty"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// QueueTrack is a track of the queue.
+	QueueTrack struct {
+		TrackID string `json:"trackId"`
+		AlbumID string `json:"albumId,omitempty"`
+		From    string `json:"from,omitempty"`
+	}
+
+	// Queue is a play queue.
+	Queue struct {
+		ID           string       `json:"id,omitempty"`
+		Context      QueueContext `json:"context"`
+		Tracks       []QueueTrack `json:"tracks"`
+		CurrentIndex int          `json:"currentIndex"`
+		// Modified is set by the API and omitted when the queue is created.
+		Modified      *time.Time `json:"modified,omitempty"`
+		From          string     `json:"from,omitempty"`
+		IsInteractive bool       `json:"isInteractive"`
+	}
+
+	// QueueItem is a short description of the queue in the list of queues.
+	QueueItem struct {
+		ID       string       `json:"id"`
+		Context  QueueContext `json:"context"`
+		Modified time.Time    `json:"modified"`
+	}
+
+	// QueuesListResp describes list queues method response
+	QueuesListResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         struct {
+			Queues []QueueItem `json:"queues"`
+		} `json:"result"`
+	}
+
+	// QueuesGetResp describes get queue method response
+	QueuesGetResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         Queue          `json:"result"`
+	}
+
+	// QueuesCreateResp describes create queue method response
+	QueuesCreateResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         struct {
+			ID                string `json:"id"`
+			MostRecentQueueID string `json:"mostRecentQueueId"`
+		} `json:"result"`
+	}
+
+	// QueuesUpdatePositionResp describes update queue position method response
+	QueuesUpdatePositionResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         string         `json:"result"`
+	}
+)
+
+// defaultDeviceInfo returns device info used if none was set for the client.
+func defaultDeviceInfo() DeviceInfo {
+	id := strings.Replace(NewPlayID(), "-", "", -1)
+	return DeviceInfo{
+		OS:           "go",
+		Manufacturer: "go-yamusic",
+		Model:        "go-yamusic",
+		DeviceID:     id,
+		UUID:         id,
+	}
+}
+
+// String returns device info in the format of X-Yandex-Music-Device header.
+func (d DeviceInfo) String() string {
+	return fmt.Sprintf(
+		"os=%s; os_version=%s; manufacturer=%s; model=%s; clid=%s; device_id=%s; uuid=%s",
+		d.OS,
+		d.OSVersion,
+		d.Manufacturer,
+		d.Model,
+		d.Clid,
+		d.DeviceID,
+		d.UUID,
+	)
+}
+
+// newRequest creates request with X-Yandex-Music-Device header of the device
+// or of the client's device if device is nil.
+func (s *QueuesService) newRequest(
+	method,
+	urlStr string,
+	body interface{},
+	device *DeviceInfo,
+) (*http.Request, error) {
+	req, err := s.client.NewRequest(method, urlStr, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if device == nil {
+		device = &s.client.device
+	}
+
+	req.Header.Set("X-Yandex-Music-Device", device.String())
+	return req, nil
+}
+
+// List returns queues of the user known to the device.
+// If device is nil device of the client is used.
+func (s *QueuesService) List(
+	ctx context.Context,
+	device *DeviceInfo,
+	reqOpts ...RequestOption,
+) (*QueuesListResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "QueuesService.List", reqOpts)
+	defer call.end()
+
+	req, err := s.newRequest(http.MethodGet, "queues", nil, device)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queues := new(QueuesListResp)
+	resp, err := s.client.Do(ctx, req, queues)
+	return queues, resp, err
+}
+
+// Get returns queue by its ID
+func (s *QueuesService) Get(
+	ctx context.Context,
+	queueID string,
+	reqOpts ...RequestOption,
+) (*QueuesGetResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "QueuesService.Get", reqOpts)
+	defer call.end()
+
+	uri := fmt.Sprintf("queues/%v", url.PathEscape(queueID))
+	req, err := s.newRequest(http.MethodGet, uri, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queue := new(QueuesGetResp)
+	resp, err := s.client.Do(ctx, req, queue)
+	return queue, resp, err
+}
+
+// Create creates new queue. Returned ID should be used
+// to update position of the queue
+func (s *QueuesService) Create(
+	ctx context.Context,
+	queue Queue,
+	reqOpts ...RequestOption,
+) (*QueuesCreateResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "QueuesService.Create", reqOpts)
+	defer call.end()
+
+	req, err := s.newRequest(http.MethodPost, "queues", queue, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	createdQueue := new(QueuesCreateResp)
+	resp, err := s.client.Do(ctx, req, createdQueue)
+	return createdQueue, resp, err
+}
+
+// UpdatePosition sets index of the current track of the queue
+func (s *QueuesService) UpdatePosition(
+	ctx context.Context,
+	queueID string,
+	index int,
+	reqOpts ...RequestOption,
+) (*QueuesUpdatePositionResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "QueuesService.UpdatePosition", reqOpts)
+	defer call.end()
+
+	queryParams := url.Values{}
+	queryParams.Set("currentIndex", strconv.Itoa(index))
+	queryParams.Set("isInteractive", "false")
+
+	uri := fmt.Sprintf(
+		"queues/%v/update-position?%v",
+		url.PathEscape(queueID),
+		queryParams.Encode(),
+	)
+
+	req, err := s.newRequest(http.MethodPost, uri, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updatedQueue := new(QueuesUpdatePositionResp)
+	resp, err := s.client.Do(ctx, req, updatedQueue)
+	return updatedQueue, resp, err
+}
@@ -0,0 +1,131 @@
+package yamusic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceInfo_String(t *testing.T) {
+	device := DeviceInfo{
+		OS:           "linux",
+		OSVersion:    "5.0",
+		Manufacturer: "me",
+		Model:        "terminal",
+		Clid:         "clid",
+		DeviceID:     "device",
+		UUID:         "uuid",
+	}
+
+	assert.Equal(
+		t,
+		"os=linux; os_version=5.0; manufacturer=me; model=terminal; clid=clid; device_id=device; uuid=uuid",
+		device.String(),
+	)
+}
+
+func TestQueuesService_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &QueuesListResp{}
+	want.InvocationInfo.ReqID = "Queues.List"
+
+	device := &DeviceInfo{OS: "phone"}
+
+	mux.HandleFunc("/queues", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+		assert.Equal(t, device.String(), r.Header.Get("X-Yandex-Music-Device"))
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	result, _, err := client.Queues().List(context.Background(), device)
+
+	assert.NoError(t, err)
+	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
+}
+
+func TestQueuesService_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &QueuesGetResp{}
+	want.InvocationInfo.ReqID = "Queues.Get"
+	want.Result.CurrentIndex = 3
+
+	mux.HandleFunc("/queues/q1", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Contains(t, r.Header.Get("X-Yandex-Music-Device"), "manufacturer=go-yamusic")
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	result, _, err := client.Queues().Get(context.Background(), "q1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Result.CurrentIndex)
+}
+
+func TestQueuesService_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &QueuesCreateResp{}
+	want.Result.ID = "q1"
+
+	queue := Queue{
+		Context:      QueueContext{Type: QueueContextPlaylist, ID: "2000:3"},
+		Tracks:       []QueueTrack{{TrackID: "1", AlbumID: "2"}},
+		CurrentIndex: 0,
+	}
+
+	mux.HandleFunc("/queues", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NotEmpty(t, r.Header.Get("X-Yandex-Music-Device"))
+
+		var raw map[string]json.RawMessage
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+		assert.NotContains(t, raw, "modified")
+
+		var got Queue
+		assert.NoError(t, json.Unmarshal(raw["context"], &got.Context))
+		assert.NoError(t, json.Unmarshal(raw["tracks"], &got.Tracks))
+		assert.Equal(t, queue.Context, got.Context)
+		assert.Equal(t, queue.Tracks, got.Tracks)
+
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	result, _, err := client.Queues().Create(context.Background(), queue)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "q1", result.Result.ID)
+}
+
+func TestQueuesService_UpdatePosition(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/queues/q1/update-position", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "5", r.URL.Query().Get("currentIndex"))
+		assert.Equal(t, "false", r.URL.Query().Get("isInteractive"))
+		fmt.Fprint(w, `{"result": "ok"}`)
+	})
+
+	result, _, err := client.Queues().UpdatePosition(context.Background(), "q1", 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", result.Result)
+}
@@ -0,0 +1,93 @@
+package yamusic
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// RateLimiter limits rate of requests allowing bursts. It is safe for
+	// concurrent use, so that one limiter can be shared by many clients
+	RateLimiter struct {
+		// interval is zero if rate isn't limited
+		interval time.Duration
+		burst    int
+
+		mu sync.Mutex
+		// next is time when the next request is allowed if there were
+		// no bursts
+		next time.Time
+	}
+
+	rateLimitDoer struct {
+		next    Doer
+		limiter *RateLimiter
+	}
+)
+
+// NewRateLimiter returns limiter which allows requestsPerSecond requests
+// on average and up to burst requests at once. Zero or negative
+// requestsPerSecond means no limit.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	limiter := &RateLimiter{burst: burst}
+	if requestsPerSecond > 0 {
+		limiter.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+
+	return limiter
+}
+
+// Wait blocks until a request is allowed or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l.interval <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now) - time.Duration(l.burst-1)*l.interval
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give the reserved slot back.
+		l.mu.Lock()
+		l.next = l.next.Add(-l.interval)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// RateLimitMiddleware returns middleware which waits for the limiter
+// before each request.
+func RateLimitMiddleware(limiter *RateLimiter) Middleware {
+	return func(next Doer) Doer {
+		return &rateLimitDoer{next: next, limiter: limiter}
+	}
+}
+
+func (d *rateLimitDoer) Do(req *http.Request) (*http.Response, error) {
+	if err := d.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return d.next.Do(req)
+}
@@ -0,0 +1,61 @@
+package yamusic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/genres", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	})
+
+	limiter := NewRateLimiter(20, 2)
+	baseURL, _ := url.Parse(server.URL + "/")
+	limitedClient := NewClient(BaseURL(baseURL), Middlewares(RateLimitMiddleware(limiter)))
+
+	// Two requests are allowed at once, the next two are delayed by 50ms each.
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, _, err := limitedClient.Genres().List(context.Background())
+		require.NoError(t, err)
+	}
+	assert.True(t, time.Since(start) >= 100*time.Millisecond, time.Since(start))
+}
+
+func TestRateLimiter_Canceled(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, limiter.Wait(ctx))
+
+	// Canceled wait gives its slot back.
+	limiter.mu.Lock()
+	next := limiter.next
+	limiter.mu.Unlock()
+	assert.True(t, time.Until(next) <= time.Second, time.Until(next))
+}
+
+func TestRateLimiter_Unlimited(t *testing.T) {
+	for _, rps := range []float64{0, -1} {
+		limiter := NewRateLimiter(rps, 1)
+		start := time.Now()
+		for i := 0; i < 100; i++ {
+			require.NoError(t, limiter.Wait(context.Background()))
+		}
+		assert.True(t, time.Since(start) < 50*time.Millisecond, time.Since(start))
+	}
+}
@@ -0,0 +1,171 @@
+package yamusic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// RequestOption customizes a single call of a service method. Options
+	// are passed down with the context, so that they also apply to calls
+	// made by composite methods like GetDownloadURL
+	RequestOption func(*requestOptions)
+
+	// ResponseHook is called with raw response of each request made by
+	// the call. Body is nil if the response was streamed to io.Writer
+	ResponseHook func(resp *http.Response, body []byte)
+
+	requestOptions struct {
+		header      http.Header
+		timeout     time.Duration
+		userID      int
+		accessToken string
+		hooks       []ResponseHook
+	}
+
+	requestOptionsKey struct{}
+
+	// call is a call of a service method.
+	call struct {
+		span   Span
+		cancel context.CancelFunc
+	}
+)
+
+// WithHeader sets header of requests
+func WithHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set(key, value)
+	}
+}
+
+// WithLanguage sets Accept-Language of requests, so that the API returns
+// localized titles like "en" or "ru"
+func WithLanguage(language string) RequestOption {
+	return WithHeader("Accept-Language", language)
+}
+
+// WithTimeout sets timeout of the whole call
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithUser makes the call on behalf of another user. Empty access token
+// keeps the client's one
+func WithUser(userID int, accessToken string) RequestOption {
+	return func(o *requestOptions) {
+		o.userID = userID
+		if accessToken != "" {
+			o.accessToken = accessToken
+		}
+	}
+}
+
+// WithResponseHook adds hook which is called with raw responses
+func WithResponseHook(hook ResponseHook) RequestOption {
+	return func(o *requestOptions) {
+		o.hooks = append(o.hooks, hook)
+	}
+}
+
+// requestOptionsFrom returns request options of ctx or nil.
+func requestOptionsFrom(ctx context.Context) *requestOptions {
+	o, _ := ctx.Value(requestOptionsKey{}).(*requestOptions)
+	return o
+}
+
+// RequestContext returns context with request options applied on top of
+// ones of ctx. Calls made with the context use the options. Cancel func
+// releases resources of WithTimeout and must be called.
+func RequestContext(ctx context.Context, opts ...RequestOption) (context.Context, context.CancelFunc) {
+	if len(opts) == 0 {
+		return ctx, func() {}
+	}
+
+	o := &requestOptions{header: http.Header{}}
+	if parent := requestOptionsFrom(ctx); parent != nil {
+		*o = *parent
+		o.header = parent.header.Clone()
+		o.hooks = append([]ResponseHook(nil), parent.hooks...)
+	}
+	// Timeout of the parent call is already in ctx.
+	o.timeout = 0
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	ctx = context.WithValue(ctx, requestOptionsKey{}, o)
+	if o.timeout > 0 {
+		return context.WithTimeout(ctx, o.timeout)
+	}
+
+	return ctx, func() {}
+}
+
+// scope returns string which is equal for options making equal requests.
+// Hooks and timeout don't change requests, so that they are ignored.
+func (o *requestOptions) scope() string {
+	if o == nil {
+		return ""
+	}
+
+	keys := make([]string, 0, len(o.header))
+	for key := range o.header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d\x00%s", o.userID, o.accessToken)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "\x00%s=%q", key, o.header[key])
+	}
+
+	return b.String()
+}
+
+// apply sets headers of the request.
+func (o *requestOptions) apply(req *http.Request) {
+	for key, values := range o.header {
+		req.Header[key] = values
+	}
+	if o.accessToken != "" {
+		req.Header.Set("Authorization", "OAuth "+o.accessToken)
+	}
+}
+
+// onResponse calls response hooks.
+func (o *requestOptions) onResponse(resp *http.Response, body []byte) {
+	for _, hook := range o.hooks {
+		hook(resp, body)
+	}
+}
+
+// startCall applies request options to ctx and starts span of the service
+// method. The call must be ended.
+func (c *Client) startCall(ctx context.Context, operation string, opts []RequestOption) (context.Context, *call) {
+	ctx, cancel := RequestContext(ctx, opts...)
+	ctx, span := c.startSpan(ctx, operation)
+	return ctx, &call{span: span, cancel: cancel}
+}
+
+// end ends the call's span and releases its timeout.
+func (c *call) end() {
+	c.span.End()
+	c.cancel()
+}
+
+// userIDFrom returns id of the user the call is made on behalf of.
+func (c *Client) userIDFrom(ctx context.Context) int {
+	if o := requestOptionsFrom(ctx); o != nil && o.userID != 0 {
+		return o.userID
+	}
+	return c.UserID()
+}
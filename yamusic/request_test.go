@@ -0,0 +1,132 @@
+package yamusic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/3000/playlists/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "OAuth otherToken", r.Header.Get("Authorization"))
+		assert.Equal(t, "en", r.Header.Get("Accept-Language"))
+		assert.Equal(t, "42", r.Header.Get("X-Request-Id"))
+		fmt.Fprint(w, `{"result":{"kind":1003}}`)
+	})
+
+	var captured []string
+	hook := func(resp *http.Response, body []byte) {
+		captured = append(captured, resp.Status+" "+string(body))
+	}
+
+	result, _, err := client.Playlists().Create(
+		context.Background(),
+		"Music",
+		false,
+		WithUser(3000, "otherToken"),
+		WithLanguage("en"),
+		WithHeader("X-Request-Id", "42"),
+		WithResponseHook(hook),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 1003, result.Result.Kind)
+	assert.Equal(t, []string{`200 OK {"result":{"kind":1003}}`}, captured)
+}
+
+func TestRequestOptions_Defaults(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/2000/playlists/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+		assert.Empty(t, r.Header.Get("Accept-Language"))
+		fmt.Fprint(w, `{"result":{"kind":1003}}`)
+	})
+
+	_, _, err := client.Playlists().Create(context.Background(), "Music", false)
+	require.NoError(t, err)
+
+	// Empty token keeps the client's one.
+	mux.HandleFunc("/users/3000/playlists/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"result":{"kind":1003}}`)
+	})
+
+	_, _, err = client.Playlists().Create(context.Background(), "Music", false, WithUser(3000, ""))
+	require.NoError(t, err)
+}
+
+func TestRequestOptions_Timeout(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/genres", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+			fmt.Fprint(w, `{}`)
+		}
+	})
+
+	_, _, err := client.Genres().List(context.Background(), WithTimeout(10*time.Millisecond))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+}
+
+func TestRequestOptions_Composite(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/tracks/42/download-info", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "OAuth otherToken", r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"result": [{"downloadInfoUrl": "download-info/42"}]}`)
+	})
+	mux.HandleFunc("/download-info/42", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "OAuth otherToken", r.Header.Get("Authorization"))
+		fmt.Fprint(w, `<download-info><host>storage.net</host><path>/track</path><ts>1</ts><s>s</s></download-info>`)
+	})
+
+	var requests int
+	hook := func(resp *http.Response, body []byte) {
+		requests++
+	}
+
+	_, err := client.Tracks().GetDownloadURL(
+		context.Background(),
+		42,
+		WithUser(3000, "otherToken"),
+		WithResponseHook(hook),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestRequestContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/landing3", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ru", r.Header.Get("Accept-Language"))
+		assert.Equal(t, "yes", r.Header.Get("X-Parent"))
+		assert.Equal(t, "yes", r.Header.Get("X-Call"))
+		fmt.Fprint(w, `{}`)
+	})
+
+	ctx, cancel := RequestContext(context.Background(), WithHeader("X-Parent", "yes"), WithLanguage("en"))
+	defer cancel()
+
+	// Options of the call are applied on top of ones of the context.
+	ctx, cancel = RequestContext(ctx, WithLanguage("ru"))
+	defer cancel()
+
+	_, _, err := client.Landing().Get(ctx, nil, WithHeader("X-Call", "yes"))
+	require.NoError(t, err)
+}
@@ -0,0 +1,361 @@
+package yamusic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by Resolver when the API didn't return
+// requested item.
+var ErrNotFound = errors.New("yamusic: not found")
+
+// ResolveError is an error of resolving a single item by Resolver.
+type ResolveError struct {
+	// Kind is kind of the item: "track", "album" or "artist".
+	Kind string
+	ID   string
+	Err  error
+}
+
+func (e *ResolveError) Error() string {
+	return fmt.Sprintf("resolve %s %s: %v", e.Kind, e.ID, e.Err)
+}
+
+// Unwrap returns underlying error.
+func (e *ResolveError) Unwrap() error { return e.Err }
+
+type (
+	// ResolverOptions are options for Resolver.
+	ResolverOptions struct {
+		// Window is how long the resolver waits for other lookups before
+		// sending a batch. Defaults to 10ms.
+		Window time.Duration
+		// MaxBatch is max count of IDs in one request. Defaults to 100.
+		MaxBatch int
+		// Timeout is timeout of one batched request. Defaults to 30s.
+		Timeout time.Duration
+	}
+
+	// Resolver looks up tracks, albums and artists by ID. Concurrent lookups
+	// made within a small window are merged into one batched request and
+	// lookups of the same ID share one in-flight call. Only lookups with the
+	// same request options (user, headers) are merged, and the batched
+	// request is made with the context of the batch's first lookup, so that
+	// it's traced as its child. It is safe for concurrent use.
+	Resolver struct {
+		tracks  *batcher
+		albums  *batcher
+		artists *batcher
+	}
+
+	// batchFetchFunc fetches items by keys and returns found ones by key.
+	batchFetchFunc func(ctx context.Context, keys []string) (map[string]interface{}, error)
+
+	batcher struct {
+		kind  string
+		fetch batchFetchFunc
+		opts  ResolverOptions
+
+		mu sync.Mutex
+		// pending and inflight are keyed by scope of request options
+		pending  map[string]*batch
+		inflight map[string]*batchCall
+	}
+
+	batch struct {
+		scope   string
+		ctx     context.Context
+		keys    []string
+		calls   map[string]*batchCall
+		timer   *time.Timer
+		flushed bool
+	}
+
+	batchCall struct {
+		done chan struct{}
+		val  interface{}
+		err  error
+	}
+
+	// detachedContext keeps values of the parent context but not its
+	// deadline and cancellation.
+	detachedContext struct {
+		context.Context
+	}
+)
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// NewResolver returns resolver which uses the client to fetch items.
+func NewResolver(client *Client, opts *ResolverOptions) *Resolver {
+	if opts == nil {
+		opts = &ResolverOptions{}
+	}
+
+	o := *opts
+	if o.Window <= 0 {
+		o.Window = 10 * time.Millisecond
+	}
+	if o.MaxBatch <= 0 {
+		o.MaxBatch = 100
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 30 * time.Second
+	}
+
+	return &Resolver{
+		tracks:  newBatcher("track", o, fetchTracks(client)),
+		albums:  newBatcher("album", o, fetchAlbums(client)),
+		artists: newBatcher("artist", o, fetchArtists(client)),
+	}
+}
+
+// Track returns track by ID. ID can be either track ID or "trackID:albumID".
+func (r *Resolver) Track(ctx context.Context, id string, reqOpts ...RequestOption) (*Track, error) {
+	ctx, cancel := RequestContext(ctx, reqOpts...)
+	defer cancel()
+
+	v, err := r.tracks.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	track := v.(Track)
+	return &track, nil
+}
+
+// Album returns album by ID.
+func (r *Resolver) Album(ctx context.Context, id int, reqOpts ...RequestOption) (*Album, error) {
+	ctx, cancel := RequestContext(ctx, reqOpts...)
+	defer cancel()
+
+	v, err := r.albums.load(ctx, strconv.Itoa(id))
+	if err != nil {
+		return nil, err
+	}
+
+	album := v.(Album)
+	return &album, nil
+}
+
+// Artist returns artist by ID.
+func (r *Resolver) Artist(ctx context.Context, id int, reqOpts ...RequestOption) (*Artist, error) {
+	ctx, cancel := RequestContext(ctx, reqOpts...)
+	defer cancel()
+
+	v, err := r.artists.load(ctx, strconv.Itoa(id))
+	if err != nil {
+		return nil, err
+	}
+
+	artist := v.(Artist)
+	return &artist, nil
+}
+
+// Tracks returns tracks by IDs concurrently. Errors are returned per ID:
+// errs[i] is an error of ids[i].
+func (r *Resolver) Tracks(ctx context.Context, ids []string, reqOpts ...RequestOption) ([]*Track, []error) {
+	ctx, cancel := RequestContext(ctx, reqOpts...)
+	defer cancel()
+
+	tracks := make([]*Track, len(ids))
+	errs := make([]error, len(ids))
+
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			tracks[i], errs[i] = r.Track(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	return tracks, errs
+}
+
+func newBatcher(kind string, opts ResolverOptions, fetch batchFetchFunc) *batcher {
+	return &batcher{
+		kind:     kind,
+		fetch:    fetch,
+		opts:     opts,
+		pending:  make(map[string]*batch),
+		inflight: make(map[string]*batchCall),
+	}
+}
+
+// load returns item by key joining current batch or in-flight call of
+// the same scope of request options.
+func (b *batcher) load(ctx context.Context, key string) (interface{}, error) {
+	scope := requestOptionsFrom(ctx).scope()
+
+	b.mu.Lock()
+	call, ok := b.inflight[scope+"\x00"+key]
+	if !ok {
+		call = &batchCall{done: make(chan struct{})}
+		b.inflight[scope+"\x00"+key] = call
+
+		pending, ok := b.pending[scope]
+		if !ok {
+			pending = &batch{
+				scope: scope,
+				ctx:   detachedContext{ctx},
+				calls: make(map[string]*batchCall),
+			}
+			pending.timer = time.AfterFunc(b.opts.Window, func() { b.flush(pending) })
+			b.pending[scope] = pending
+		}
+
+		pending.keys = append(pending.keys, key)
+		pending.calls[key] = call
+
+		if len(pending.keys) >= b.opts.MaxBatch {
+			delete(b.pending, scope)
+			pending.timer.Stop()
+			go b.flush(pending)
+		}
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// flush fetches the batch and fans results out to its calls.
+func (b *batcher) flush(pending *batch) {
+	b.mu.Lock()
+	if pending.flushed {
+		// Timer fired while the full batch was already being flushed.
+		b.mu.Unlock()
+		return
+	}
+	pending.flushed = true
+	if b.pending[pending.scope] == pending {
+		delete(b.pending, pending.scope)
+	}
+	b.mu.Unlock()
+
+	// The first lookup may be canceled, while others still wait.
+	ctx, cancel := context.WithTimeout(pending.ctx, b.opts.Timeout)
+	defer cancel()
+
+	results, err := b.fetch(ctx, pending.keys)
+
+	b.mu.Lock()
+	for _, key := range pending.keys {
+		call := pending.calls[key]
+		if err != nil {
+			call.err = &ResolveError{Kind: b.kind, ID: key, Err: err}
+		} else if val, ok := results[key]; ok {
+			call.val = val
+		} else {
+			call.err = &ResolveError{Kind: b.kind, ID: key, Err: ErrNotFound}
+		}
+		delete(b.inflight, pending.scope+"\x00"+key)
+		close(call.done)
+	}
+	b.mu.Unlock()
+}
+
+func fetchTracks(client *Client) batchFetchFunc {
+	return func(ctx context.Context, keys []string) (map[string]interface{}, error) {
+		tracks, resp, err := client.Tracks().GetMany(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+		if err := CheckResponse(resp, tracks.Error); err != nil {
+			return nil, err
+		}
+
+		byID := make(map[string]Track, len(tracks.Result))
+		for _, track := range tracks.Result {
+			byID[track.ID] = track
+		}
+
+		results := make(map[string]interface{}, len(keys))
+		for _, key := range keys {
+			trackID := key
+			if i := strings.Index(key, ":"); i >= 0 {
+				trackID = key[:i]
+			}
+			if track, ok := byID[trackID]; ok {
+				results[key] = track
+			}
+		}
+
+		return results, nil
+	}
+}
+
+func fetchAlbums(client *Client) batchFetchFunc {
+	return func(ctx context.Context, keys []string) (map[string]interface{}, error) {
+		ids, err := atois(keys)
+		if err != nil {
+			return nil, err
+		}
+
+		albums, resp, err := client.Albums().GetMany(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		if err := CheckResponse(resp, albums.Error); err != nil {
+			return nil, err
+		}
+
+		results := make(map[string]interface{}, len(albums.Result))
+		for _, album := range albums.Result {
+			results[strconv.Itoa(album.ID)] = album
+		}
+
+		return results, nil
+	}
+}
+
+func fetchArtists(client *Client) batchFetchFunc {
+	return func(ctx context.Context, keys []string) (map[string]interface{}, error) {
+		ids, err := atois(keys)
+		if err != nil {
+			return nil, err
+		}
+
+		artists, resp, err := client.Artists().GetMany(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		if err := CheckResponse(resp, artists.Error); err != nil {
+			return nil, err
+		}
+
+		results := make(map[string]interface{}, len(artists.Result))
+		for _, artist := range artists.Result {
+			results[strconv.Itoa(artist.ID)] = artist
+		}
+
+		return results, nil
+	}
+}
+
+// atois converts strings to ints.
+func atois(values []string) ([]int, error) {
+	ints := make([]int, 0, len(values))
+	for _, value := range values {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+		ints = append(ints, i)
+	}
+
+	return ints, nil
+}
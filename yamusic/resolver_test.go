@@ -0,0 +1,168 @@
+package yamusic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolver_Track(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	var batches []string
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		ids := r.FormValue("track-ids")
+
+		mu.Lock()
+		batches = append(batches, ids)
+		mu.Unlock()
+
+		want := &TrackResp{}
+		for _, id := range strings.Split(ids, ",") {
+			id = strings.Split(id, ":")[0]
+			if id == "404" {
+				continue
+			}
+			want.Result = append(want.Result, Track{ID: id, Title: "Track " + id})
+		}
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	resolver := NewResolver(client, &ResolverOptions{Window: 50 * time.Millisecond})
+	ids := []string{"1", "2", "1", "3:30", "404"}
+	tracks, errs := resolver.Tracks(context.Background(), ids)
+
+	assert.Len(t, batches, 1)
+	assert.Len(t, strings.Split(batches[0], ","), 4)
+
+	for i, id := range ids[:4] {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, strings.Split(id, ":")[0], tracks[i].ID)
+	}
+
+	assert.Nil(t, tracks[4])
+	assert.True(t, errors.Is(errs[4], ErrNotFound))
+	var resolveErr *ResolveError
+	assert.True(t, errors.As(errs[4], &resolveErr))
+	assert.Equal(t, "404", resolveErr.ID)
+}
+
+func TestResolver_MaxBatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	calls := 0
+	mux.HandleFunc("/albums", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+
+		want := &AlbumsGetManyResp{}
+		for _, id := range strings.Split(r.FormValue("album-ids"), ",") {
+			var album Album
+			fmt.Sscan(id, &album.ID)
+			want.Result = append(want.Result, album)
+		}
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	resolver := NewResolver(client, &ResolverOptions{Window: time.Hour, MaxBatch: 2})
+
+	var wg sync.WaitGroup
+	for _, id := range []int{1, 2} {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			album, err := resolver.Album(context.Background(), id)
+			assert.NoError(t, err)
+			assert.Equal(t, id, album.ID)
+		}(id)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestResolver_Error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/artists", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"error": {"name": "internal-error", "message": "boom"}}`)
+	})
+
+	resolver := NewResolver(client, nil)
+	_, err := resolver.Artist(context.Background(), 1)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "internal-error")
+}
+
+func TestResolver_RequestOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	batches := make(map[string]string)
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		ids := r.FormValue("track-ids")
+
+		sorted := strings.Split(ids, ",")
+		sort.Strings(sorted)
+
+		mu.Lock()
+		batches[auth+" "+r.Header.Get("Accept-Language")] = strings.Join(sorted, ",")
+		mu.Unlock()
+
+		want := &TrackResp{}
+		for _, id := range sorted {
+			want.Result = append(want.Result, Track{ID: id, Title: auth})
+		}
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	resolver := NewResolver(client, &ResolverOptions{Window: 50 * time.Millisecond})
+
+	var wg sync.WaitGroup
+	lookup := func(id, wantAuth string, reqOpts ...RequestOption) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			track, err := resolver.Track(context.Background(), id, reqOpts...)
+			assert.NoError(t, err)
+			assert.Equal(t, wantAuth, track.Title)
+		}()
+	}
+	lookup("1", "OAuth "+accessToken)
+	lookup("1", "OAuth otherToken", WithUser(3000, "otherToken"))
+	lookup("2", "OAuth otherToken", WithUser(3000, "otherToken"))
+	lookup("3", "OAuth otherToken", WithUser(3000, "otherToken"), WithLanguage("en"))
+	wg.Wait()
+
+	assert.Equal(t, map[string]string{
+		"OAuth " + accessToken + " ": "1",
+		"OAuth otherToken ":          "1,2",
+		"OAuth otherToken en":        "3",
+	}, batches)
+}
@@ -2,6 +2,7 @@ package yamusic
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -17,6 +18,12 @@ const (
 	searchTypeAlbum  searchType = "album"
 	searchTypeTrack  searchType = "track"
 	searchTypeAll    searchType = "all"
+
+	searchTypePlaylist       searchType = "playlist"
+	searchTypeVideo          searchType = "video"
+	searchTypeUser           searchType = "user"
+	searchTypePodcast        searchType = "podcast"
+	searchTypePodcastEpisode searchType = "podcast_episode"
 )
 
 type (
@@ -28,6 +35,10 @@ type (
 	SearchOptions struct {
 		Page      int
 		NoCorrect bool
+		// PageSize is count of results per page. Zero means server's default.
+		PageSize int
+		// PlaylistInBest allows playlist to be the best result.
+		PlaylistInBest bool
 	}
 	// SearchResp describes search method response.
 	SearchResp struct {
@@ -73,18 +84,7 @@ type (
 							Index  int `json:"index"`
 						} `json:"trackPosition"`
 					} `json:"albums"`
-					Artists []struct {
-						ID    int `json:"id"`
-						Cover struct {
-							Type   string `json:"type"`
-							Prefix string `json:"prefix"`
-							URI    string `json:"uri"`
-						} `json:"cover"`
-						Composer   bool          `json:"composer"`
-						Various    bool          `json:"various"`
-						Name       string        `json:"name"`
-						Decomposed []interface{} `json:"decomposed"`
-					} `json:"artists"`
+					Artists Artists `json:"artists"`
 				} `json:"results"`
 			} `json:"tracks"`
 			Playlists struct {
@@ -132,33 +132,68 @@ type (
 				Total   int `json:"total"`
 				PerPage int `json:"perPage"`
 				Results []struct {
-					ID                  int    `json:"id"`
-					StorageDir          string `json:"storageDir"`
-					OriginalReleaseYear int    `json:"originalReleaseYear"`
-					Year                int    `json:"year"`
-					Title               string `json:"title"`
-					Artists             []struct {
-						ID    int `json:"id"`
-						Cover struct {
-							Type   string `json:"type"`
-							Prefix string `json:"prefix"`
-							URI    string `json:"uri"`
-						} `json:"cover"`
-						Composer   bool          `json:"composer"`
-						Various    bool          `json:"various"`
-						Name       string        `json:"name"`
-						Decomposed []interface{} `json:"decomposed"`
-					} `json:"artists"`
-					CoverURI   string   `json:"coverUri"`
-					TrackCount int      `json:"trackCount"`
-					Genre      string   `json:"genre"`
-					Available  bool     `json:"available"`
-					Regions    []string `json:"regions"`
+					ID                  int      `json:"id"`
+					StorageDir          string   `json:"storageDir"`
+					OriginalReleaseYear int      `json:"originalReleaseYear"`
+					Year                int      `json:"year"`
+					Title               string   `json:"title"`
+					Artists             Artists  `json:"artists"`
+					CoverURI            string   `json:"coverUri"`
+					TrackCount          int      `json:"trackCount"`
+					Genre               string   `json:"genre"`
+					Available           bool     `json:"available"`
+					Regions             []string `json:"regions"`
 				} `json:"results"`
 			} `json:"albums"`
+			Podcasts struct {
+				Total   int     `json:"total"`
+				PerPage int     `json:"perPage"`
+				Results []Album `json:"results"`
+			} `json:"podcasts"`
+			PodcastEpisodes struct {
+				Total   int     `json:"total"`
+				PerPage int     `json:"perPage"`
+				Results []Track `json:"results"`
+			} `json:"podcast_episodes"`
+			Users struct {
+				Total   int          `json:"total"`
+				PerPage int          `json:"perPage"`
+				Results []SearchUser `json:"results"`
+			} `json:"users"`
+		} `json:"result"`
+	}
+
+	// SearchUser is user found by search
+	SearchUser struct {
+		UID         int      `json:"uid"`
+		Login       string   `json:"login"`
+		Name        string   `json:"name"`
+		DisplayName string   `json:"displayName"`
+		FullName    string   `json:"fullName"`
+		Sex         string   `json:"sex"`
+		Verified    bool     `json:"verified"`
+		Regions     []string `json:"regions"`
+	}
+
+	// SearchSuggestResp describes search suggest method response.
+	SearchSuggestResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         struct {
+			Best        *SearchSuggestBest `json:"best,omitempty"`
+			Suggestions []string           `json:"suggestions"`
 		} `json:"result"`
 	}
 
+	// SearchSuggestBest is the best result of search suggest. Type of Result
+	// depends on Type ("artist", "album", "track", "playlist" and so on),
+	// use Decode to decode it.
+	SearchSuggestBest struct {
+		Type   string          `json:"type"`
+		Text   string          `json:"text"`
+		Result json.RawMessage `json:"result"`
+	}
+
 	// SearchResult search result json
 	SearchResult struct {
 		ID               int      `json:"id"`
@@ -212,7 +247,11 @@ func (s *SearchService) Artists(
 	ctx context.Context,
 	query string,
 	opts *SearchOptions,
+	reqOpts ...RequestOption,
 ) (*SearchResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "SearchService.Artists", reqOpts)
+	defer call.end()
+
 	return s.search(ctx, searchTypeArtist, query, opts)
 }
 
@@ -221,7 +260,11 @@ func (s *SearchService) Tracks(
 	ctx context.Context,
 	query string,
 	opts *SearchOptions,
+	reqOpts ...RequestOption,
 ) (*SearchResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "SearchService.Tracks", reqOpts)
+	defer call.end()
+
 	return s.search(ctx, searchTypeTrack, query, opts)
 }
 
@@ -230,7 +273,11 @@ func (s *SearchService) Albums(
 	ctx context.Context,
 	query string,
 	opts *SearchOptions,
+	reqOpts ...RequestOption,
 ) (*SearchResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "SearchService.Albums", reqOpts)
+	defer call.end()
+
 	return s.search(ctx, searchTypeAlbum, query, opts)
 }
 
@@ -239,10 +286,79 @@ func (s *SearchService) All(
 	ctx context.Context,
 	query string,
 	opts *SearchOptions,
+	reqOpts ...RequestOption,
 ) (*SearchResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "SearchService.All", reqOpts)
+	defer call.end()
+
 	return s.search(ctx, searchTypeAll, query, opts)
 }
 
+// Playlists searches playlists by query
+func (s *SearchService) Playlists(
+	ctx context.Context,
+	query string,
+	opts *SearchOptions,
+	reqOpts ...RequestOption,
+) (*SearchResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "SearchService.Playlists", reqOpts)
+	defer call.end()
+
+	return s.search(ctx, searchTypePlaylist, query, opts)
+}
+
+// Videos searches videos by query
+func (s *SearchService) Videos(
+	ctx context.Context,
+	query string,
+	opts *SearchOptions,
+	reqOpts ...RequestOption,
+) (*SearchResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "SearchService.Videos", reqOpts)
+	defer call.end()
+
+	return s.search(ctx, searchTypeVideo, query, opts)
+}
+
+// Users searches users by query
+func (s *SearchService) Users(
+	ctx context.Context,
+	query string,
+	opts *SearchOptions,
+	reqOpts ...RequestOption,
+) (*SearchResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "SearchService.Users", reqOpts)
+	defer call.end()
+
+	return s.search(ctx, searchTypeUser, query, opts)
+}
+
+// Podcasts searches podcasts by query
+func (s *SearchService) Podcasts(
+	ctx context.Context,
+	query string,
+	opts *SearchOptions,
+	reqOpts ...RequestOption,
+) (*SearchResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "SearchService.Podcasts", reqOpts)
+	defer call.end()
+
+	return s.search(ctx, searchTypePodcast, query, opts)
+}
+
+// PodcastEpisodes searches podcast episodes by query
+func (s *SearchService) PodcastEpisodes(
+	ctx context.Context,
+	query string,
+	opts *SearchOptions,
+	reqOpts ...RequestOption,
+) (*SearchResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "SearchService.PodcastEpisodes", reqOpts)
+	defer call.end()
+
+	return s.search(ctx, searchTypePodcastEpisode, query, opts)
+}
+
 func (s *SearchService) search(
 	ctx context.Context,
 	searchTyp searchType,
@@ -258,6 +374,12 @@ func (s *SearchService) search(
 	queryParams.Set("text", query)
 	queryParams.Set("page", strconv.Itoa(opts.Page))
 	queryParams.Set("nocorrect", strconv.FormatBool(opts.NoCorrect))
+	if opts.PageSize > 0 {
+		queryParams.Set("page-size", strconv.Itoa(opts.PageSize))
+	}
+	if opts.PlaylistInBest {
+		queryParams.Set("playlist-in-best", strconv.FormatBool(opts.PlaylistInBest))
+	}
 
 	uri := fmt.Sprintf("search?%v", queryParams.Encode())
 
@@ -270,3 +392,34 @@ func (s *SearchService) search(
 	resp, err := s.client.Do(ctx, req, result)
 	return result, resp, err
 }
+
+// Suggest returns autocomplete suggestions and the best result for the
+// beginning of a search query
+func (s *SearchService) Suggest(
+	ctx context.Context,
+	part string,
+	reqOpts ...RequestOption,
+) (*SearchSuggestResp, *http.Response, error) {
+	ctx, call := s.client.startCall(ctx, "SearchService.Suggest", reqOpts)
+	defer call.end()
+
+	queryParams := url.Values{}
+	queryParams.Set("part", part)
+
+	uri := fmt.Sprintf("search/suggest?%v", queryParams.Encode())
+
+	req, err := s.client.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(SearchSuggestResp)
+	resp, err := s.client.Do(ctx, req, result)
+	return result, resp, err
+}
+
+// Decode decodes the best result into v, e.g. *Track for "track" type,
+// *Album for "album" and *Artist for "artist".
+func (b *SearchSuggestBest) Decode(v interface{}) error {
+	return json.Unmarshal(b.Result, v)
+}
@@ -34,10 +34,41 @@ func TestSearchService_All(t *testing.T) {
 	testSearch(t, "all", client.Search().All)
 }
 
+func TestSearchService_Playlists(t *testing.T) {
+	setup()
+	defer teardown()
+	testSearch(t, "playlist", client.Search().Playlists)
+}
+
+func TestSearchService_Videos(t *testing.T) {
+	setup()
+	defer teardown()
+	testSearch(t, "video", client.Search().Videos)
+}
+
+func TestSearchService_Users(t *testing.T) {
+	setup()
+	defer teardown()
+	testSearch(t, "user", client.Search().Users)
+}
+
+func TestSearchService_Podcasts(t *testing.T) {
+	setup()
+	defer teardown()
+	testSearch(t, "podcast", client.Search().Podcasts)
+}
+
+func TestSearchService_PodcastEpisodes(t *testing.T) {
+	setup()
+	defer teardown()
+	testSearch(t, "podcast_episode", client.Search().PodcastEpisodes)
+}
+
 func testSearch(t *testing.T, searchType string, searchFunc func(
 	ctx context.Context,
 	query string,
 	opts *SearchOptions,
+	reqOpts ...RequestOption,
 ) (*SearchResp, *http.Response, error),
 ) {
 	want := &SearchResp{}
@@ -60,3 +91,46 @@ func testSearch(t *testing.T, searchType string, searchFunc func(
 	assert.NoError(t, err)
 	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
 }
+
+func TestSearchService_PageSize(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t,
+			"/search?nocorrect=false&page=0&page-size=5&playlist-in-best=true&text=blah&type=all",
+			r.URL.String())
+		fmt.Fprint(w, "{}")
+	})
+
+	opts := &SearchOptions{PageSize: 5, PlaylistInBest: true}
+	_, _, err := client.Search().All(context.Background(), "blah", opts)
+	assert.NoError(t, err)
+}
+
+func TestSearchService_Suggest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/search/suggest", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+		assert.Equal(t, "oxxy", r.URL.Query().Get("part"))
+		fmt.Fprint(w, `{
+			"invocationInfo": {"req-id": "Search.Suggest"},
+			"result": {
+				"best": {"type": "artist", "text": "Oxxxymiron", "result": {"id": 1, "name": "Oxxxymiron"}},
+				"suggestions": ["oxxxymiron", "oxxxymiron горгород"]
+			}
+		}`)
+	})
+
+	result, _, err := client.Search().Suggest(context.Background(), "oxxy")
+	assert.NoError(t, err)
+	assert.Equal(t, "Search.Suggest", result.InvocationInfo.ReqID)
+	assert.Len(t, result.Result.Suggestions, 2)
+
+	var artist Artist
+	assert.NoError(t, result.Result.Best.Decode(&artist))
+	assert.Equal(t, "Oxxxymiron", artist.Name)
+}
@@ -0,0 +1,253 @@
+package yamusic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Attribute keys of spans. HTTP ones follow OpenTelemetry semantic
+// conventions
+const (
+	AttrEndpoint   = "http.route"
+	AttrMethod     = "http.request.method"
+	AttrStatusCode = "http.response.status_code"
+	AttrUserID     = "yamusic.user_id"
+	AttrReqID      = "yamusic.req_id"
+	AttrHostname   = "yamusic.hostname"
+	AttrErrorName  = "yamusic.error_name"
+)
+
+type (
+	// Tracer starts spans. It mirrors OpenTelemetry trace.Tracer, so that
+	// an adapter to it takes a few lines
+	Tracer interface {
+		// Start starts span which is a child of the span in ctx if any
+		// and returns context with the new span
+		Start(ctx context.Context, name string) (context.Context, Span)
+	}
+
+	// Span is a traced operation
+	Span interface {
+		SetAttributes(attrs ...Attribute)
+		RecordError(err error)
+		End()
+	}
+
+	// Attribute is key-value pair describing span
+	Attribute struct {
+		Key   string
+		Value interface{}
+	}
+
+	// operationSpanKey is context key of the span of a service method
+	operationSpanKey struct{}
+
+	noopSpan struct{}
+)
+
+// Tracing sets tracer for Yandex.Music client. Each service method opens
+// a span named after the operation like "PlaylistsService.AddTracks" and
+// each HTTP request opens a child span like "GET users/{uid}/playlists/{kind}"
+func Tracing(tracer Tracer) func(*Client) {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+func (noopSpan) RecordError(err error)            {}
+func (noopSpan) End()                             {}
+
+// startSpan starts span of the service method.
+func (c *Client) startSpan(ctx context.Context, operation string) (context.Context, Span) {
+	if c.tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	ctx, span := c.tracer.Start(ctx, operation)
+	span.SetAttributes(Attribute{Key: AttrUserID, Value: c.userIDFrom(ctx)})
+	return context.WithValue(ctx, operationSpanKey{}, span), span
+}
+
+// startRequestSpan starts span of the HTTP request. Attributes set on it are
+// also set on the span of the service method, so that the latter describes
+// its last request.
+func (c *Client) startRequestSpan(ctx context.Context, req *http.Request) (context.Context, *requestSpan) {
+	if c.tracer == nil {
+		return ctx, nil
+	}
+
+	endpoint := EndpointTemplate(req.URL.Path)
+	operation, _ := ctx.Value(operationSpanKey{}).(Span)
+
+	ctx, span := c.tracer.Start(ctx, req.Method+" "+endpoint)
+	s := &requestSpan{span: span, operation: operation}
+	s.SetAttributes(
+		Attribute{Key: AttrEndpoint, Value: endpoint},
+		Attribute{Key: AttrMethod, Value: req.Method},
+		Attribute{Key: AttrUserID, Value: c.userIDFrom(ctx)},
+	)
+
+	return ctx, s
+}
+
+// requestSpan is span of the HTTP request.
+type requestSpan struct {
+	span      Span
+	operation Span
+}
+
+func (s *requestSpan) SetAttributes(attrs ...Attribute) {
+	s.span.SetAttributes(attrs...)
+	if s.operation != nil {
+		s.operation.SetAttributes(attrs...)
+	}
+}
+
+func (s *requestSpan) RecordError(err error) {
+	s.span.RecordError(err)
+	if s.operation != nil {
+		s.operation.RecordError(err)
+	}
+}
+
+// finish sets attributes of the response with body and ends the span.
+// Body is nil if it wasn't read.
+func (s *requestSpan) finish(resp *http.Response, body []byte, err error) {
+	if s == nil {
+		return
+	}
+	defer s.span.End()
+
+	if resp != nil {
+		s.SetAttributes(Attribute{Key: AttrStatusCode, Value: resp.StatusCode})
+	}
+
+	var info struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+	}
+	if len(body) > 0 && json.Unmarshal(body, &info) == nil {
+		if info.InvocationInfo.ReqID != "" {
+			s.SetAttributes(
+				Attribute{Key: AttrReqID, Value: info.InvocationInfo.ReqID},
+				Attribute{Key: AttrHostname, Value: info.InvocationInfo.Hostname},
+			)
+		}
+		if info.Error.Name != "" {
+			s.SetAttributes(Attribute{Key: AttrErrorName, Value: info.Error.Name})
+		}
+	}
+
+	switch {
+	case err != nil:
+		s.RecordError(err)
+	case resp != nil && resp.StatusCode >= http.StatusBadRequest:
+		message := info.Error.Message
+		if message == "" {
+			message = info.Error.Name
+		}
+		s.RecordError(fmt.Errorf("%s: %s", resp.Status, message))
+	}
+}
+
+type (
+	// MemoryTracer is Tracer which records spans in memory. It's useful in
+	// tests. It is safe for concurrent use
+	MemoryTracer struct {
+		mu    sync.Mutex
+		spans []*memorySpan
+	}
+
+	// RecordedSpan is a span recorded by MemoryTracer
+	RecordedSpan struct {
+		// ID is index of the span in order of start
+		ID int
+		// ParentID is ID of the parent span or -1 for root spans
+		ParentID   int
+		Name       string
+		Attributes map[string]interface{}
+		Errors     []error
+		Ended      bool
+	}
+
+	memorySpan struct {
+		tracer *MemoryTracer
+		span   RecordedSpan
+	}
+
+	memorySpanKey struct{}
+)
+
+// NewMemoryTracer returns tracer which records spans in memory.
+func NewMemoryTracer() *MemoryTracer {
+	return &MemoryTracer{}
+}
+
+// Start starts span which is a child of the MemoryTracer's span in ctx.
+func (t *MemoryTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parentID := -1
+	if parent, ok := ctx.Value(memorySpanKey{}).(*memorySpan); ok {
+		parentID = parent.span.ID
+	}
+
+	t.mu.Lock()
+	span := &memorySpan{
+		tracer: t,
+		span: RecordedSpan{
+			ID:         len(t.spans),
+			ParentID:   parentID,
+			Name:       name,
+			Attributes: map[string]interface{}{},
+		},
+	}
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+
+	return context.WithValue(ctx, memorySpanKey{}, span), span
+}
+
+// Spans returns copies of recorded spans in order of start.
+func (t *MemoryTracer) Spans() []RecordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	spans := make([]RecordedSpan, 0, len(t.spans))
+	for _, s := range t.spans {
+		span := s.span
+		span.Attributes = make(map[string]interface{}, len(s.span.Attributes))
+		for key, value := range s.span.Attributes {
+			span.Attributes[key] = value
+		}
+		span.Errors = append([]error(nil), s.span.Errors...)
+		spans = append(spans, span)
+	}
+
+	return spans
+}
+
+func (s *memorySpan) SetAttributes(attrs ...Attribute) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	for _, attr := range attrs {
+		s.span.Attributes[attr.Key] = attr.Value
+	}
+}
+
+func (s *memorySpan) RecordError(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	s.span.Errors = append(s.span.Errors, err)
+}
+
+func (s *memorySpan) End() {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	s.span.Ended = true
+}
@@ -0,0 +1,98 @@
+package yamusic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracing(t *testing.T) {
+	setup()
+	defer teardown()
+
+	tracer := NewMemoryTracer()
+	baseURL, _ := url.Parse(server.URL + "/")
+	tracedClient := NewClient(BaseURL(baseURL), AccessToken(userID, accessToken), Tracing(tracer))
+
+	mux.HandleFunc("/tracks/42/download-info", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"invocationInfo": {"hostname": "music-back1", "req-id": "download-info-42"},
+			"result": [{"downloadInfoUrl": "download-info/42"}]
+		}`)
+	})
+	mux.HandleFunc("/download-info/42", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<download-info><host>storage.net</host><path>/track</path><ts>1</ts><s>s</s></download-info>`)
+	})
+
+	_, err := tracedClient.Tracks().GetDownloadURL(context.Background(), 42)
+	require.NoError(t, err)
+
+	spans := tracer.Spans()
+	require.Len(t, spans, 5)
+
+	names := make([]string, 0, len(spans))
+	parents := make([]int, 0, len(spans))
+	for _, span := range spans {
+		assert.True(t, span.Ended, span.Name)
+		assert.Empty(t, span.Errors, span.Name)
+		names = append(names, span.Name)
+		parents = append(parents, span.ParentID)
+	}
+
+	assert.Equal(t, []string{
+		"TracksService.GetDownloadURL",
+		"TracksService.GetDownloadInfo",
+		"TracksService.GetDownloadInfoResp",
+		"GET tracks/{id}/download-info",
+		"GET download-info/{id}",
+	}, names)
+	assert.Equal(t, []int{-1, 0, 1, 2, 1}, parents)
+
+	assert.Equal(t, map[string]interface{}{
+		AttrEndpoint:   "tracks/{id}/download-info",
+		AttrMethod:     http.MethodGet,
+		AttrStatusCode: http.StatusOK,
+		AttrUserID:     userID,
+		AttrReqID:      "download-info-42",
+		AttrHostname:   "music-back1",
+	}, spans[3].Attributes)
+
+	// Span of the operation describes its last request.
+	assert.Equal(t, spans[3].Attributes, spans[2].Attributes)
+	assert.Equal(t, "download-info/{id}", spans[1].Attributes[AttrEndpoint])
+	assert.Equal(t, map[string]interface{}{AttrUserID: userID}, spans[0].Attributes)
+}
+
+func TestTracing_Error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	tracer := NewMemoryTracer()
+	baseURL, _ := url.Parse(server.URL + "/")
+	tracedClient := NewClient(BaseURL(baseURL), AccessToken(userID, accessToken), Tracing(tracer))
+
+	mux.HandleFunc("/users/2000/playlists/3", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"name":"playlist-not-found","message":"Playlist not found"}}`)
+	})
+
+	_, _, err := tracedClient.Playlists().Get(context.Background(), userID, 3)
+	require.NoError(t, err)
+
+	spans := tracer.Spans()
+	require.Len(t, spans, 2)
+
+	for _, span := range spans {
+		assert.Equal(t, http.StatusNotFound, span.Attributes[AttrStatusCode], span.Name)
+		assert.Equal(t, "playlist-not-found", span.Attributes[AttrErrorName], span.Name)
+		require.Len(t, span.Errors, 1, span.Name)
+		assert.EqualError(t, span.Errors[0], "404 Not Found: Playlist not found")
+	}
+	assert.Equal(t, "PlaylistsService.Get", spans[0].Name)
+	assert.Equal(t, "GET users/{uid}/playlists/{kind}", spans[1].Name)
+}
@@ -7,6 +7,8 @@ import (
 	"encoding/xml"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
 type (
@@ -19,6 +21,52 @@ type (
 		Error          Error          `json:"error"`
 		Result         []Track        `json:"result"`
 	}
+	// TrackSupplementResp is a response of tracks/%d/supplement
+	TrackSupplementResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         struct {
+			ID               string       `json:"id"`
+			Lyrics           *TrackLyrics `json:"lyrics,omitempty"`
+			Videos           []TrackVideo `json:"videos"`
+			RadioIsAvailable bool         `json:"radioIsAvailable"`
+			Description      string       `json:"description,omitempty"`
+		} `json:"result"`
+	}
+	// TrackLyrics is lyrics of a track
+	TrackLyrics struct {
+		ID              int    `json:"id"`
+		Lyrics          string `json:"lyrics"`
+		FullLyrics      string `json:"fullLyrics"`
+		HasRights       bool   `json:"hasRights"`
+		TextLanguage    string `json:"textLanguage"`
+		ShowTranslation bool   `json:"showTranslation"`
+		URL             string `json:"url,omitempty"`
+	}
+	// TrackVideo is a video clip of a track
+	TrackVideo struct {
+		Title           string `json:"title"`
+		Cover           string `json:"cover"`
+		EmbedURL        string `json:"embedUrl"`
+		Provider        string `json:"provider"`
+		ProviderVideoID string `json:"providerVideoId"`
+		Duration        int    `json:"duration,omitempty"`
+	}
+	// TrackSimilarResp is a response of tracks/%d/similar
+	TrackSimilarResp struct {
+		InvocationInfo InvocationInfo `json:"invocationInfo"`
+		Error          Error          `json:"error"`
+		Result         struct {
+			Track         Track   `json:"track"`
+			SimilarTracks []Track `json:"similarTracks"`
+		} `json:"result"`
+	}
+	// TrackFullInfo is a track with its album and similar tracks
+	TrackFullInfo struct {
+		Track   Track
+		Album   *Album
+		Similar []Track
+	}
 	// Response of track/%d/download_info
 	DownloadInfoResp struct {
 		InvocationInfo InvocationInfo `json:"invocationInfo"`
@@ -54,10 +102,14 @@ var (
 	ErrNilPath             = TrackError("got nil path")
 	ErrEmptyPath           = TrackError("got empty path")
 	ErrZeroResultLen       = TrackError("len of download inf response's result field is zero")
+	ErrTrackNotFound       = TrackError("track not found")
 )
 
 // Get returns track by its ID
-func (t *TracksService) Get(ctx context.Context, id int) (*TrackResp, *http.Response, error) {
+func (t *TracksService) Get(ctx context.Context, id int, reqOpts ...RequestOption) (*TrackResp, *http.Response, error) {
+	ctx, call := t.client.startCall(ctx, "TracksService.Get", reqOpts)
+	defer call.end()
+
 	uri := fmt.Sprintf("tracks/%v", id)
 	req, err := t.client.NewRequest(http.MethodGet, uri, nil)
 	if err != nil {
@@ -68,10 +120,101 @@ func (t *TracksService) Get(ctx context.Context, id int) (*TrackResp, *http.Resp
 	return track, resp, err
 }
 
+// GetMany returns several tracks by their IDs in one request.
+// Each ID can be either a track ID or "trackID:albumID".
+func (t *TracksService) GetMany(ctx context.Context, ids []string, reqOpts ...RequestOption) (*TrackResp, *http.Response, error) {
+	ctx, call := t.client.startCall(ctx, "TracksService.GetMany", reqOpts)
+	defer call.end()
+
+	form := url.Values{}
+	form.Set("track-ids", strings.Join(ids, ","))
+	form.Set("with-positions", "true")
+
+	req, err := t.client.NewRequest(http.MethodPost, "tracks", form)
+	if err != nil {
+		return nil, nil, err
+	}
+	tracks := new(TrackResp)
+	resp, err := t.client.Do(ctx, req, tracks)
+	return tracks, resp, err
+}
+
+// Supplement returns lyrics, videos and radio availability of track
+func (t *TracksService) Supplement(ctx context.Context, id int, reqOpts ...RequestOption) (*TrackSupplementResp, *http.Response, error) {
+	ctx, call := t.client.startCall(ctx, "TracksService.Supplement", reqOpts)
+	defer call.end()
+
+	uri := fmt.Sprintf("tracks/%v/supplement", id)
+	req, err := t.client.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	supplement := new(TrackSupplementResp)
+	resp, err := t.client.Do(ctx, req, supplement)
+	return supplement, resp, err
+}
+
+// Similar returns tracks similar to the track
+func (t *TracksService) Similar(ctx context.Context, id int, reqOpts ...RequestOption) (*TrackSimilarResp, *http.Response, error) {
+	ctx, call := t.client.startCall(ctx, "TracksService.Similar", reqOpts)
+	defer call.end()
+
+	uri := fmt.Sprintf("tracks/%v/similar", id)
+	req, err := t.client.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	similar := new(TrackSimilarResp)
+	resp, err := t.client.Do(ctx, req, similar)
+	return similar, resp, err
+}
+
+// FullInfo returns track with its album and similar tracks.
+// Album is nil if track doesn't belong to any album.
+func (t *TracksService) FullInfo(ctx context.Context, id int, reqOpts ...RequestOption) (*TrackFullInfo, error) {
+	ctx, call := t.client.startCall(ctx, "TracksService.FullInfo", reqOpts)
+	defer call.end()
+
+	similar, _, err := t.Similar(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	track := similar.Result.Track
+	if track.ID == "" {
+		tracks, _, err := t.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if len(tracks.Result) == 0 {
+			return nil, ErrTrackNotFound
+		}
+		track = tracks.Result[0]
+	}
+
+	info := &TrackFullInfo{
+		Track:   track,
+		Similar: similar.Result.SimilarTracks,
+	}
+
+	if len(track.Albums) > 0 {
+		album, _, err := t.client.Albums().Get(ctx, track.Albums[0].ID)
+		if err != nil {
+			return nil, err
+		}
+		info.Album = &album.Result
+	}
+
+	return info, nil
+}
+
 // GetDownloadInfoResp returns DownloadInfoResp byt track's ID
 // Be careful: you can get DownloadInfo by DownloadInfoURL only
 // for one minute since you called GetDownloadInfoResp
-func (t *TracksService) GetDownloadInfoResp(ctx context.Context, id int) (*DownloadInfoResp, *http.Response, error) {
+func (t *TracksService) GetDownloadInfoResp(ctx context.Context, id int, reqOpts ...RequestOption) (*DownloadInfoResp, *http.Response, error) {
+	ctx, call := t.client.startCall(ctx, "TracksService.GetDownloadInfoResp", reqOpts)
+	defer call.end()
+
 	uri := fmt.Sprintf("tracks/%v/download-info", id)
 	req, err := t.client.NewRequest(http.MethodGet, uri, nil)
 	if err != nil {
@@ -86,7 +229,10 @@ func (t *TracksService) GetDownloadInfoResp(ctx context.Context, id int) (*Downl
 // GetDownloadInfo returns DownloadInfo by id of track.
 // Be careful: it uses the same context for GetDownloadInfoResp
 // and its request
-func (t *TracksService) GetDownloadInfo(ctx context.Context, id int) (*DownloadInfo, *http.Response, error) {
+func (t *TracksService) GetDownloadInfo(ctx context.Context, id int, reqOpts ...RequestOption) (*DownloadInfo, *http.Response, error) {
+	ctx, call := t.client.startCall(ctx, "TracksService.GetDownloadInfo", reqOpts)
+	defer call.end()
+
 	dlInfoResp, dirResp, err := t.GetDownloadInfoResp(ctx, id)
 	if err != nil {
 		return nil, dirResp, err
@@ -111,7 +257,10 @@ func (t *TracksService) GetDownloadInfo(ctx context.Context, id int) (*DownloadI
 }
 
 // GetDownloadURL computes path to track by ID
-func (t *TracksService) GetDownloadURL(ctx context.Context, id int) (string, error) {
+func (t *TracksService) GetDownloadURL(ctx context.Context, id int, reqOpts ...RequestOption) (string, error) {
+	ctx, call := t.client.startCall(ctx, "TracksService.GetDownloadURL", reqOpts)
+	defer call.end()
+
 	dlInfo, _, err := t.GetDownloadInfo(ctx, id)
 	if err != nil {
 		return "", err
@@ -118,3 +118,89 @@ func TestTracksSevice_GetDownloadInfo(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestTracksSevice_GetMany(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &TrackResp{}
+	want.InvocationInfo.ReqID = "Tracks.GetMany"
+
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+		assert.Equal(t, "1:10,2", r.FormValue("track-ids"))
+		assert.Equal(t, "true", r.FormValue("with-positions"))
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	result, _, err := client.Tracks().GetMany(
+		context.Background(),
+		[]string{
+			PlaylistsTrack{ID: 1, AlbumID: 10}.String(),
+			PlaylistsTrack{ID: 2}.String(),
+		},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
+}
+
+func TestTracksSevice_Supplement(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &TrackSupplementResp{}
+	want.InvocationInfo.ReqID = "Tracks.Supplement"
+	want.Result.Lyrics = &TrackLyrics{FullLyrics: "la la la"}
+
+	mux.HandleFunc("/tracks/42/supplement", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "OAuth "+accessToken, r.Header.Get("Authorization"))
+		b, err := json.Marshal(want)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	result, _, err := client.Tracks().Supplement(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.Equal(t, want.InvocationInfo.ReqID, result.InvocationInfo.ReqID)
+	assert.Equal(t, "la la la", result.Result.Lyrics.FullLyrics)
+}
+
+func TestTracksSevice_FullInfo(t *testing.T) {
+	setup()
+	defer teardown()
+
+	similar := &TrackSimilarResp{}
+	similar.Result.Track = Track{ID: "42", Albums: Albums{{ID: 7}}}
+	similar.Result.SimilarTracks = []Track{{ID: "43"}}
+
+	mux.HandleFunc("/tracks/42/similar", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		b, err := json.Marshal(similar)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	album := &AlbumsGetResp{}
+	album.Result.ID = 7
+	album.Result.Title = "Album"
+
+	mux.HandleFunc("/albums/7", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		b, err := json.Marshal(album)
+		assert.NoError(t, err)
+		fmt.Fprint(w, string(b))
+	})
+
+	info, err := client.Tracks().FullInfo(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "42", info.Track.ID)
+	assert.Equal(t, "Album", info.Album.Title)
+	assert.Equal(t, similar.Result.SimilarTracks, info.Similar)
+}
@@ -5,12 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"encoding/xml"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
+	"sync/atomic"
 )
 
 const (
@@ -22,15 +24,29 @@ type (
 	Doer interface {
 		Do(req *http.Request) (*http.Response, error)
 	}
+	// DoerFunc is an adapter to allow the use of ordinary functions as Doer
+	DoerFunc func(req *http.Request) (*http.Response, error)
+	// Middleware wraps Doer to add behaviour like caching or metrics
+	// to every request of the client
+	Middleware func(next Doer) Doer
 	// A Client manages communication with the Yandex.Music API.
 	Client struct {
+		// userID is accessed atomically since it may be set by SetUserID
+		// while the client is in use. It's the first field to be 64-bit
+		// aligned on 32-bit platforms
+		userID int64
 		// HTTP client used to communicate with the API.
 		client Doer
 		// Base URL for API requests.
 		baseURL *url.URL
 		// Access token to Yandex.Music API
 		accessToken string
-		userID      int
+		// Device info sent to queues API
+		device DeviceInfo
+		// Middlewares which wrap HTTP client
+		middlewares []Middleware
+		// Tracer which traces service methods and their requests
+		tracer Tracer
 		// Debug sets should library print debug messages or not
 		Debug bool
 		// Services
@@ -40,6 +56,11 @@ type (
 		feed      *FeedService
 		playlists *PlaylistsService
 		tracks    *TracksService
+		albums    *AlbumsService
+		landing   *LandingService
+		queues    *QueuesService
+		artists   *ArtistsService
+		likes     *LikesService
 	}
 )
 
@@ -53,20 +74,40 @@ func NewClient(options ...func(*Client)) *Client {
 	c := &Client{
 		client:  http.DefaultClient,
 		baseURL: baseURL,
+		device:  defaultDeviceInfo(),
 	}
 
 	for _, option := range options {
 		option(c)
 	}
 
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		c.client = c.middlewares[i](c.client)
+	}
+
+	c.initServices()
+
+	return c
+}
+
+// initServices creates services of the client.
+func (c *Client) initServices() {
 	c.genres = &GenresService{client: c}
 	c.search = &SearchService{client: c}
 	c.account = &AccountService{client: c}
 	c.feed = &FeedService{client: c}
 	c.playlists = &PlaylistsService{client: c}
 	c.tracks = &TracksService{client: c}
+	c.albums = &AlbumsService{client: c}
+	c.landing = &LandingService{client: c}
+	c.queues = &QueuesService{client: c}
+	c.artists = &ArtistsService{client: c}
+	c.likes = &LikesService{client: c}
+}
 
-	return c
+// Do calls f(req)
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
 }
 
 // HTTPClient sets http client for Yandex.Music client
@@ -91,7 +132,7 @@ func BaseURL(baseURL *url.URL) func(*Client) {
 func AccessToken(userID int, accessToken string) func(*Client) {
 	return func(c *Client) {
 		if userID != 0 {
-			c.userID = userID
+			c.SetUserID(userID)
 		}
 
 		if accessToken != "" {
@@ -100,6 +141,22 @@ func AccessToken(userID int, accessToken string) func(*Client) {
 	}
 }
 
+// Middlewares adds middlewares which wrap http client of Yandex.Music client.
+// The first middleware is the outermost one
+func Middlewares(middlewares ...Middleware) func(*Client) {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// Device sets device info for Yandex.Music client which is used
+// to identify the client in queues API
+func Device(device DeviceInfo) func(*Client) {
+	return func(c *Client) {
+		c.device = device
+	}
+}
+
 // NewRequest creates an API request. A relative URL can be provided in urlStr,
 // in which case it is resolved relative to the BaseURL of the Client.
 // Relative URLs should always be specified without a preceding slash.  If
@@ -154,30 +211,42 @@ func (c *Client) NewRequest(
 // JSON decoded and stored in the value pointed to by v, or returned as an
 // error if an API error has occurred.  If v implements the io.Writer
 // interface, the raw response body will be written to v, without attempting to
-// first decode it.
+// first decode it. Request options of ctx are applied to the request.
 func (c *Client) Do(
 	ctx context.Context,
 	req *http.Request,
 	v interface{},
 ) (*http.Response, error) {
-	req = req.WithContext(ctx)
+	opts := requestOptionsFrom(ctx)
+	ctx, span := c.startRequestSpan(ctx, req)
+	if opts != nil {
+		req = req.Clone(ctx)
+		opts.apply(req)
+	} else {
+		req = req.WithContext(ctx)
+	}
 
 	resp, err := c.client.Do(req)
 	if err != nil {
+		span.finish(nil, nil, err)
 		return nil, err
 	}
 
+	var body []byte
 	if v != nil {
 		if w, ok := v.(io.Writer); ok {
 			_, err = io.Copy(w, resp.Body)
 			if err != nil {
+				span.finish(resp, nil, err)
 				return nil, err
 			}
 		} else {
 			dat, err := io.ReadAll(resp.Body)
 			if err != nil {
+				span.finish(resp, nil, err)
 				return nil, err
 			}
+			body = dat
 			resp.Body = io.NopCloser(bytes.NewReader(dat))
 			err = json.Unmarshal(dat, v)
 			if err == io.EOF {
@@ -193,17 +262,23 @@ func (c *Client) Do(
 		}
 	}
 
+	span.finish(resp, body, err)
+	if opts != nil {
+		opts.onResponse(resp, body)
+	}
+
 	return resp, err
 }
 
-// SetUserID sets user's id in client
+// SetUserID sets user's id in client. It is safe to call it concurrently
+// with requests
 func (c *Client) SetUserID(nID int) {
-	c.userID = nID
+	atomic.StoreInt64(&c.userID, int64(nID))
 }
 
 // UserID returns id of authorized user. If wasn't authorized returns 0.
 func (c *Client) UserID() int {
-	return c.userID
+	return int(atomic.LoadInt64(&c.userID))
 }
 
 // Genres returns genres service
@@ -236,6 +311,31 @@ func (c *Client) Tracks() *TracksService {
 	return c.tracks
 }
 
+// Albums returns albums service
+func (c *Client) Albums() *AlbumsService {
+	return c.albums
+}
+
+// Landing returns landing service
+func (c *Client) Landing() *LandingService {
+	return c.landing
+}
+
+// Queues returns queues service
+func (c *Client) Queues() *QueuesService {
+	return c.queues
+}
+
+// Artists returns artists service
+func (c *Client) Artists() *ArtistsService {
+	return c.artists
+}
+
+// Likes returns likes service
+func (c *Client) Likes() *LikesService {
+	return c.likes
+}
+
 // General types
 type (
 	// InvocationInfo is base info in all requests
@@ -250,4 +350,34 @@ type (
 		Name    string `json:"name"`
 		Message string `json:"message"`
 	}
+	// ResponseError is returned by CheckResponse if the API responded
+	// with error status.
+	ResponseError struct {
+		StatusCode int
+		Status     string
+		Name       string
+		Message    string
+	}
 )
+
+func (e *ResponseError) Error() string {
+	if e.Name != "" {
+		return fmt.Sprintf("%s: %s: %s", e.Status, e.Name, e.Message)
+	}
+	return e.Status
+}
+
+// CheckResponse returns *ResponseError if the API responded with error
+// status. apiErr is Error field of the decoded response.
+func CheckResponse(resp *http.Response, apiErr Error) error {
+	if resp == nil || resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	return &ResponseError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Name:       apiErr.Name,
+		Message:    apiErr.Message,
+	}
+}
@@ -1,9 +1,13 @@
 package yamusic
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 var (
@@ -37,3 +41,18 @@ func setup() {
 func teardown() {
 	server.Close()
 }
+
+func TestCheckResponse(t *testing.T) {
+	assert.NoError(t, CheckResponse(nil, Error{}))
+	assert.NoError(t, CheckResponse(&http.Response{StatusCode: http.StatusOK}, Error{}))
+
+	resp := &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found"}
+	err := CheckResponse(resp, Error{Name: "not-found", Message: "no track"})
+	assert.EqualError(t, err, "404 Not Found: not-found: no track")
+
+	var respErr *ResponseError
+	assert.True(t, errors.As(err, &respErr))
+	assert.Equal(t, http.StatusNotFound, respErr.StatusCode)
+
+	assert.EqualError(t, CheckResponse(resp, Error{}), "404 Not Found")
+}